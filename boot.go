@@ -1,5 +1,11 @@
 package nacelle
 
+import (
+	"context"
+	"os"
+	"strings"
+)
+
 type (
 	// Bootstrapper wraps the entrypoint to the program.
 	Bootstrapper struct {
@@ -8,10 +14,16 @@ type (
 		configSetupFunc ConfigSetupFunc
 		initFunc        AppInitFunc
 		loggingInitFunc LoggingInitFunc
+		runnerConfigs   []ProcessRunnerConfigFunc
+		configServices  []configService
+		configSourcer   ConfigSourcer
 	}
 
 	bootstrapperConfig struct {
 		loggingInitFunc LoggingInitFunc
+		runnerConfigs   []ProcessRunnerConfigFunc
+		configServices  []configService
+		configSourcer   ConfigSourcer
 	}
 
 	// ConfigSetupFunc is called by the bootstrap procedure to populate
@@ -37,6 +49,19 @@ func WithLoggingInitFunc(loggingInitFunc LoggingInitFunc) BoostraperConfigFunc {
 	return func(c *bootstrapperConfig) { c.loggingInitFunc = loggingInitFunc }
 }
 
+// WithRunnerConfigs sets the configuration of the process runner created by
+// Boot (e.g. WithSignals).
+func WithRunnerConfigs(runnerConfigs ...ProcessRunnerConfigFunc) BoostraperConfigFunc {
+	return func(c *bootstrapperConfig) { c.runnerConfigs = append(c.runnerConfigs, runnerConfigs...) }
+}
+
+// WithConfigSourcer sets the sourcer from which the config is loaded. By default,
+// the config is loaded from the OS environment. Sources can be combined by
+// precedence with NewCompositeSourcer.
+func WithConfigSourcer(sourcer ConfigSourcer) BoostraperConfigFunc {
+	return func(c *bootstrapperConfig) { c.configSourcer = sourcer }
+}
+
 // NewBootstrapper creates an entrypoint to the program with the given configs.
 func NewBootstrapper(
 	name string,
@@ -46,6 +71,7 @@ func NewBootstrapper(
 ) *Bootstrapper {
 	config := &bootstrapperConfig{
 		loggingInitFunc: InitLogging,
+		configSourcer:   NewEnvSourcer(),
 	}
 
 	for _, f := range bootstrapperConfigs {
@@ -57,35 +83,68 @@ func NewBootstrapper(
 		configSetupFunc: configSetupFunc,
 		initFunc:        initFunc,
 		loggingInitFunc: config.loggingInitFunc,
+		runnerConfigs:   config.runnerConfigs,
+		configServices:  config.configServices,
+		configSourcer:   config.configSourcer,
 	}
 }
 
+// SelfTestFlag is the command line argument which causes Boot to run a self-test
+// in place of the application.
+const SelfTestFlag = "--self-test"
+
 // Boot will initialize services and return a status code - zero
-// for a successful exit and one if an error was encountered.
+// for a successful exit and one if an error was encountered. If the
+// program was invoked with the argument --self-test, Boot runs SelfTest
+// instead.
 func (bs *Bootstrapper) Boot() int {
+	for _, arg := range os.Args[1:] {
+		if arg == SelfTestFlag {
+			return bs.SelfTest()
+		}
+	}
+
+	return bs.boot(false)
+}
+
+// SelfTest initializes services as Boot does, then runs the self-test of each
+// initializer and process which implements SelfTester instead of starting any
+// process. A JSON report of the self-test is written to standard out. Zero is
+// returned if every self-test passed and one otherwise. This is intended to be
+// run as a pre-deploy check.
+func (bs *Bootstrapper) SelfTest() int {
+	return bs.boot(true)
+}
+
+func (bs *Bootstrapper) boot(selfTest bool) int {
 	var (
 		container = NewServiceContainer()
-		runner    = NewProcessRunner(container)
-		config    = NewEnvConfig(bs.name)
+		runner    = NewProcessRunner(container, bs.runnerConfigs...)
 	)
 
-	if err := config.Register(LoggingConfigToken, &LoggingConfig{}); err != nil {
-		emergencyLogger().Error("failed to register logging config (%s)", err.Error())
-		return 1
+	var (
+		sourcer             = bs.configSourcer
+		developmentDefaults []string
+	)
+
+	if IsDevelopment() {
+		developmentSourcer, applied, err := applyDevelopmentDefaults(bs.name, sourcer)
+		if err != nil {
+			emergencyLogger().Error("failed to apply development defaults (%s)", err.Error())
+			return 1
+		}
+
+		sourcer, developmentDefaults = developmentSourcer, applied
 	}
 
-	if err := bs.configSetupFunc(config); err != nil {
-		emergencyLogger().Error("failed to register configs (%s)", err.Error())
+	config, err := setupConfig(bs.name, sourcer, bs.configSetupFunc)
+	if err != nil {
+		emergencyLogger().Error("%s", err.Error())
 		return 1
 	}
 
-	if errs := config.Load(); len(errs) > 0 {
-		logger := emergencyLogger()
-
-		for _, err := range errs {
-			logger.Error("Failed to load configuration (%s)", err.Error())
-		}
-
+	if report := loadConfig(config); report != nil {
+		emergencyLogger().ErrorWithFields(report.Fields(), "Failed to load configuration (%s)", report.Error())
 		return 1
 	}
 
@@ -95,19 +154,116 @@ func (bs *Bootstrapper) Boot() int {
 		return 1
 	}
 
+	// The runner syncs the logger once all processes have stopped. This catches
+	// the messages logged by Boot after the runner has returned.
 	defer func() {
-		if err := logger.Sync(); err != nil {
+		if err := syncLogger(logger, defaultLogSyncTimeout); err != nil {
 			emergencyLogger().Error("failed to sync logs on shutdown (%s)", err.Error())
 		}
 	}()
 
 	logger.Info("Logging initialized")
 
+	if IsDevelopment() {
+		logger.Warning("Running in development mode (applied %s)", strings.Join(developmentDefaults, ", "))
+	}
+
+	loggingConfig := &LoggingConfig{}
+	if err := config.Fetch(LoggingConfigToken, loggingConfig); err != nil {
+		logger.Error("Failed to fetch logging config (%s)", err.Error())
+		return 1
+	}
+
+	if IsProduction() {
+		if report := checkHardening(config, loggingConfig); report.Err() != nil {
+			logger.ErrorWithFields(report.Fields(), "Refusing to start in production mode (%s)", report.Error())
+			return 1
+		}
+	}
+
+	if loggingConfig.LogCaptureStreams {
+		capture, err := CaptureStreams(logger)
+		if err != nil {
+			logger.Error("Failed to capture output streams (%s)", err.Error())
+			return 1
+		}
+
+		// Restored before the logger is synced so that all captured output
+		// has been logged
+		defer capture.Close()
+	}
+
+	if loggingConfig.LogRedirectStdlib {
+		defer redirectStdlib(logger)()
+	}
+
 	if err := container.Set("logger", logger); err != nil {
 		logger.Error("Failed to register logger to service container (%s)", err.Error())
 		return 1
 	}
 
+	if err := container.Set("health", NewHealth(container)); err != nil {
+		logger.Error("Failed to register health tracker to service container (%s)", err.Error())
+		return 1
+	}
+
+	if err := container.Set("runner", runner); err != nil {
+		logger.Error("Failed to register process runner to service container (%s)", err.Error())
+		return 1
+	}
+
+	if err := container.Set("ports", NewPorts()); err != nil {
+		logger.Error("Failed to register port registry to service container (%s)", err.Error())
+		return 1
+	}
+
+	killSwitchConfig := &KillSwitchConfig{}
+	if err := config.Fetch(KillSwitchConfigToken, killSwitchConfig); err != nil {
+		logger.Error("Failed to fetch kill switch config (%s)", err.Error())
+		return 1
+	}
+
+	if err := container.Set("killswitches", NewKillSwitches(killSwitchConfig.KillSwitches...)); err != nil {
+		logger.Error("Failed to register kill switches to service container (%s)", err.Error())
+		return 1
+	}
+
+	if err := container.Set("operations", NewOperations()); err != nil {
+		logger.Error("Failed to register operations to service container (%s)", err.Error())
+		return 1
+	}
+
+	timeoutsConfig := &TimeoutsConfig{}
+	if err := config.Fetch(TimeoutsConfigToken, timeoutsConfig); err != nil {
+		logger.Error("Failed to fetch timeouts config (%s)", err.Error())
+		return 1
+	}
+
+	if err := container.Set("timeouts", &timeoutsConfig.Timeouts); err != nil {
+		logger.Error("Failed to register timeouts to service container (%s)", err.Error())
+		return 1
+	}
+
+	builtinConfigServices := []configService{
+		{LoggingConfigToken, loggingConfig},
+		{KillSwitchConfigToken, killSwitchConfig},
+		{TimeoutsConfigToken, timeoutsConfig},
+	}
+
+	for _, service := range builtinConfigServices {
+		if err := container.Set(ConfigServiceKey(service.key), service.target); err != nil {
+			logger.Error("Failed to register config to service container (%s)", err.Error())
+			return 1
+		}
+	}
+
+	for _, service := range bs.configServices {
+		if err := RegisterConfigService(config, container, service.key, service.target); err != nil {
+			logger.Error("Failed to register config to service container (%s)", err.Error())
+			return 1
+		}
+	}
+
 	m, err := config.ToMap()
 	if err != nil {
 		logger.Error("Failed to serialize config (%s)", err.Error())
@@ -116,15 +272,39 @@ func (bs *Bootstrapper) Boot() int {
 
 	logger.InfoWithFields(m, "Process starting")
 
+	// Services registered by the bootstrapper are not reported as unused
+	container.ignoreRegisteredServices()
+
 	if err := bs.initFunc(runner, container); err != nil {
 		logger.Error("Failed to run initialization function (%s)", err.Error())
 		return 1
 	}
 
+	logger.DebugWithFields(container.describeFields(), "Registered %d services", len(container.Keys()))
+
+	if selfTest {
+		report := runner.SelfTest(context.Background(), config, logger)
+		if err := report.Err(); err != nil {
+			logger.Error("%s", err.Error())
+		}
+
+		if statusCode := writeJSON(report, os.Stdout, os.Stderr); statusCode != 0 || !report.Passed() {
+			return 1
+		}
+
+		return 0
+	}
+
 	statusCode := 0
 	for err := range runner.Run(config, logger) {
 		statusCode = 1
-		logger.Error("Encountered runtime error (%s)", err.Error())
+
+		if report, ok := err.(*StartupReport); ok {
+			logger.ErrorWithFields(report.Fields(), "Failed to validate processes (%s)", report.Error())
+			continue
+		}
+
+		logger.ErrorWithFields(errorFields(err), "Encountered runtime error (%s)", err.Error())
 	}
 
 	logger.Info("All processes have stopped")
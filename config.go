@@ -4,10 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type (
@@ -60,11 +60,15 @@ type (
 		PostLoad() error
 	}
 
-	// EnvConfig is a Config object that reads from the OS environment.
+	// EnvConfig is a Config object that reads from a ConfigSourcer. The
+	// sourcer of a config created by NewEnvConfig reads from the OS
+	// environment.
 	EnvConfig struct {
-		prefix string
-		chunks map[interface{}]interface{}
-		loaded bool
+		prefix  string
+		sourcer ConfigSourcer
+		chunks  map[interface{}]interface{}
+		loaded  bool
+		mutex   sync.RWMutex
 	}
 
 	reflectField struct {
@@ -88,6 +92,10 @@ var (
 	// ErrNotLoaded is returned on a call to Get without first calling Load.
 	ErrNotLoaded = errors.New("config not loaded")
 
+	// ErrConfigNotReloadable is returned when reloading a config which does not
+	// implement ReloadableConfig.
+	ErrConfigNotReloadable = errors.New("config does not support reloading")
+
 	replacer = strings.NewReplacer(
 		"\n", `\n`,
 		"\t", `\t`,
@@ -98,9 +106,17 @@ var (
 // NewEnvConfig creates a EnvConfig object with the given prefix. If supplied,
 // the {PREFIX}{NAME} envvar is read before falling back to the {NAME} envvar.
 func NewEnvConfig(prefix string) Config {
+	return NewConfig(prefix, NewEnvSourcer())
+}
+
+// NewConfig creates a EnvConfig object with the given prefix which reads from
+// the given sourcer (see NewCompositeSourcer). Values are looked up under the
+// same names as the envvars read by a config created by NewEnvConfig.
+func NewConfig(prefix string, sourcer ConfigSourcer) Config {
 	return &EnvConfig{
-		prefix: prefix,
-		chunks: map[interface{}]interface{}{},
+		prefix:  prefix,
+		sourcer: sourcer,
+		chunks:  map[interface{}]interface{}{},
 	}
 }
 
@@ -132,6 +148,9 @@ func (c *EnvConfig) Get(key interface{}) (interface{}, error) {
 		return nil, ErrNotLoaded
 	}
 
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
 	if config, ok := c.chunks[key]; ok {
 		return config, nil
 	}
@@ -236,7 +255,7 @@ func (c *EnvConfig) Load() []error {
 
 	errors := []error{}
 	for _, chunk := range c.chunks {
-		errors = loadChunk(chunk, errors, c.prefix)
+		errors = loadChunk(chunk, errors, c.prefix, c.sourcer)
 	}
 
 	return errors
@@ -248,6 +267,9 @@ func (c *EnvConfig) Load() []error {
 func (c *EnvConfig) ToMap() (map[string]interface{}, error) {
 	m := map[string]interface{}{}
 
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
 	for _, chunk := range c.chunks {
 		if err := dumpChunk(chunk, m); err != nil {
 			return nil, err
@@ -257,7 +279,7 @@ func (c *EnvConfig) ToMap() (map[string]interface{}, error) {
 	return m, nil
 }
 
-func loadChunk(obj interface{}, errors []error, prefix string) []error {
+func loadChunk(obj interface{}, errors []error, prefix string, sourcer ConfigSourcer) []error {
 	objValue, objType := getIndirect(obj)
 
 	for i := 0; i < objType.NumField(); i++ {
@@ -272,15 +294,11 @@ func loadChunk(obj interface{}, errors []error, prefix string) []error {
 			continue
 		}
 
-		envTags := []string{
-			strings.ToUpper(fmt.Sprintf("%s_%s", prefix, envTagValue)),
-			strings.ToUpper(envTagValue),
-		}
-
 		err := loadEnvField(
 			fieldType,
 			fieldValue,
-			envTags,
+			sourcer,
+			envTagNames(prefix, envTagValue),
 			defaultTagValue,
 			requiredTagValue,
 		)
@@ -304,7 +322,7 @@ func getIndirect(obj interface{}) (reflect.Value, reflect.Type) {
 	return indirect, indirect.Type()
 }
 
-func loadEnvField(fieldType reflect.StructField, fieldValue reflect.Value, envTags []string, defaultTag, requiredTag string) error {
+func loadEnvField(fieldType reflect.StructField, fieldValue reflect.Value, sourcer ConfigSourcer, envTags []string, defaultTag, requiredTag string) error {
 	if !fieldValue.IsValid() {
 		return fmt.Errorf("field '%s' is invalid", fieldType.Name)
 	}
@@ -313,7 +331,7 @@ func loadEnvField(fieldType reflect.StructField, fieldValue reflect.Value, envTa
 		return fmt.Errorf("field '%s' can not be set", fieldType.Name)
 	}
 
-	val, ok := getFirst(envTags)
+	val, ok := getFirst(sourcer, envTags)
 	if ok {
 		if !toJSON([]byte(val), fieldValue.Addr().Interface()) {
 			return fmt.Errorf("value supplied for field '%s' cannot be coerced into the expected type", fieldType.Name)
@@ -344,9 +362,18 @@ func loadEnvField(fieldType reflect.StructField, fieldValue reflect.Value, envTa
 	return nil
 }
 
-func getFirst(envTags []string) (string, bool) {
+// envTagNames returns the envvars from which a field with the given env tag is
+// read, in order of precedence.
+func envTagNames(prefix, envTagValue string) []string {
+	return []string{
+		strings.ToUpper(fmt.Sprintf("%s_%s", prefix, envTagValue)),
+		strings.ToUpper(envTagValue),
+	}
+}
+
+func getFirst(sourcer ConfigSourcer, envTags []string) (string, bool) {
 	for _, envTag := range envTags {
-		if val, ok := os.LookupEnv(envTag); ok {
+		if val, ok := sourcer.Get(envTag); ok {
 			return val, ok
 		}
 	}
@@ -0,0 +1,101 @@
+package nacelle
+
+import "fmt"
+
+type (
+	// PrefixedConfig is a view of a Config which is limited to the chunks that
+	// have been registered under a single prefix. This allows two instances of
+	// the same reusable process to register and fetch the same config key and
+	// receive values loaded from distinct environment variables. A process can
+	// be given a prefixed view of the application config by registering it with
+	// the WithProcessConfigPrefix option.
+	PrefixedConfig struct {
+		parent Config
+		prefix string
+	}
+
+	prefixedKey struct {
+		prefix string
+		key    interface{}
+	}
+)
+
+// NewPrefixedConfig creates a view of the given config limited to the chunks
+// registered under the given prefix.
+func NewPrefixedConfig(config Config, prefix string) Config {
+	return &PrefixedConfig{
+		parent: config,
+		prefix: prefix,
+	}
+}
+
+// RegisterPrefixed registers a config chunk to the given key under the given
+// prefix. This is shorthand for calling Register on a prefixed view.
+func RegisterPrefixed(config Config, prefix string, key, chunk interface{}) error {
+	return NewPrefixedConfig(config, prefix).Register(key, chunk)
+}
+
+// Load calls Load on the underlying config.
+func (c *PrefixedConfig) Load() []error {
+	return c.parent.Load()
+}
+
+// Register associates a config chunk with the given key under the view's prefix.
+// The `env` tags of the chunk are prefixed (as if by an EnvTagPrefixer) so that
+// a field tagged `env:"name"` is read from the {PREFIX}_{NAME} envvar.
+func (c *PrefixedConfig) Register(key interface{}, config interface{}) error {
+	modified, err := ApplyTagModifiers(config, NewEnvTagPrefixer(c.prefix))
+	if err != nil {
+		return err
+	}
+
+	return c.parent.Register(c.makeKey(key), modified)
+}
+
+// MustRegister calls Register and panics on error.
+func (c *PrefixedConfig) MustRegister(key interface{}, config interface{}) {
+	if err := c.Register(key, config); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Get retrieves the chunk registered to the given key under the view's prefix.
+func (c *PrefixedConfig) Get(key interface{}) (interface{}, error) {
+	return c.parent.Get(c.makeKey(key))
+}
+
+// MustGet calls Get and panics on error.
+func (c *PrefixedConfig) MustGet(key interface{}) interface{} {
+	config, err := c.Get(key)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return config
+}
+
+// Fetch populates the target struct with the field values in the chunk
+// registered to the given key under the view's prefix.
+func (c *PrefixedConfig) Fetch(key interface{}, target interface{}) error {
+	return c.parent.Fetch(c.makeKey(key), target)
+}
+
+// MustFetch calls Fetch and panics on error.
+func (c *PrefixedConfig) MustFetch(key interface{}, target interface{}) {
+	if err := c.Fetch(key, target); err != nil {
+		panic(err.Error())
+	}
+}
+
+// ToMap calls ToMap on the underlying config.
+func (c *PrefixedConfig) ToMap() (map[string]interface{}, error) {
+	return c.parent.ToMap()
+}
+
+func (c *PrefixedConfig) makeKey(key interface{}) interface{} {
+	return prefixedKey{prefix: c.prefix, key: key}
+}
+
+func (k prefixedKey) String() string {
+	return fmt.Sprintf("%s/%s", k.prefix, serializeKey(k.key))
+}
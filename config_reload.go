@@ -0,0 +1,64 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ReloadableConfig is implemented by Config objects whose registered structs can
+// be re-read from their source after the config has been loaded.
+type ReloadableConfig interface {
+	// Reload re-reads the config structs registered to the given keys.
+	Reload(keys ...interface{}) []error
+}
+
+// Reload re-reads the config structs registered to the given keys from the
+// config's sourcer. Each struct is populated into a new instance which replaces the
+// registered struct only if it loads without error, so that a failed reload
+// leaves the previous values in place. Structs previously returned by Get, and
+// targets previously populated by Fetch, are not modified.
+func (c *EnvConfig) Reload(keys ...interface{}) []error {
+	if !c.loaded {
+		return []error{ErrNotLoaded}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	errors := []error{}
+	for _, key := range keys {
+		chunk, ok := c.chunks[key]
+		if !ok {
+			errors = append(errors, fmt.Errorf("unregistered config key `%s`", serializeKey(key)))
+			continue
+		}
+
+		reloaded := reflect.New(reflect.TypeOf(chunk).Elem()).Interface()
+
+		if chunkErrors := loadChunk(reloaded, nil, c.prefix, c.sourcer); len(chunkErrors) > 0 {
+			errors = append(errors, chunkErrors...)
+			continue
+		}
+
+		c.chunks[key] = reloaded
+	}
+
+	return errors
+}
+
+// Reload re-reads the config structs registered to the given keys under the
+// view's prefix. It is an error to reload a view of a config which does not
+// implement ReloadableConfig.
+func (c *PrefixedConfig) Reload(keys ...interface{}) []error {
+	reloadable, ok := c.parent.(ReloadableConfig)
+	if !ok {
+		return []error{ErrConfigNotReloadable}
+	}
+
+	prefixed := []interface{}{}
+	for _, key := range keys {
+		prefixed = append(prefixed, c.makeKey(key))
+	}
+
+	return reloadable.Reload(prefixed...)
+}
@@ -0,0 +1,54 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// configService is a config struct which is registered to the service container
+// by Boot. See WithConfigService.
+type configService struct {
+	key    interface{}
+	target interface{}
+}
+
+// ConfigServiceKey returns the key under which RegisterConfigService registers the
+// config struct fetched from the given config key (e.g. `config/nacelle-logging`).
+// This value can be used in a `service` tag to inject the config struct. Tokens
+// whose underlying type is a string are identified by their value rather than by
+// their type name so that distinct tokens of the same type do not collide.
+func ConfigServiceKey(key interface{}) string {
+	if value := reflect.ValueOf(key); value.Kind() == reflect.String {
+		return fmt.Sprintf("config/%s", value.String())
+	}
+
+	return fmt.Sprintf("config/%s", serializeKey(key))
+}
+
+// RegisterConfigService fetches the config struct registered to the given key into
+// the target and registers the target to the container under ConfigServiceKey(key).
+// Components constructed after the config has been loaded can then receive resolved
+// config values by injection (or by type, via Provide) instead of re-fetching them,
+// so that every consumer reads the same snapshot. If the config is a prefixed view,
+// the service key includes the prefix (e.g. `config/{prefix}/{key}`).
+func RegisterConfigService(config Config, container *ServiceContainer, key, target interface{}) error {
+	if err := config.Fetch(key, target); err != nil {
+		return fmt.Errorf("failed to fetch config `%s` (%s)", serializeKey(key), err.Error())
+	}
+
+	serviceKey := key
+	if prefixed, ok := config.(*PrefixedConfig); ok {
+		serviceKey = prefixed.makeKey(key)
+	}
+
+	return container.Set(ConfigServiceKey(serviceKey), target)
+}
+
+// WithConfigService causes Boot to fetch the config struct registered to the given
+// key into the target after the config has been loaded and to register the target
+// to the service container. See RegisterConfigService.
+func WithConfigService(key, target interface{}) BoostraperConfigFunc {
+	return func(c *bootstrapperConfig) {
+		c.configServices = append(c.configServices, configService{key: key, target: target})
+	}
+}
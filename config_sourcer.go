@@ -0,0 +1,165 @@
+package nacelle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// ConfigSourcer supplies the raw values from which registered config structs
+	// are populated. Values are looked up by envvar-style names (e.g. APP_HTTP_PORT
+	// for a field tagged `env:"http_port"` of a config with the prefix app), so that
+	// a field can be supplied by any source under the same name. A value is parsed
+	// as JSON and, failing that, is treated as a string.
+	ConfigSourcer interface {
+		// Get returns the value supplied for the given name and true, or false
+		// if the sourcer does not supply a value for the name.
+		Get(name string) (string, bool)
+	}
+
+	// ConfigSourcerFunc is a function which conforms to the ConfigSourcer interface.
+	// This allows values to be read from a remote store (e.g. a key-value store or
+	// a secrets manager).
+	ConfigSourcerFunc func(name string) (string, bool)
+
+	envSourcer struct{}
+
+	mapSourcer struct {
+		values map[string]string
+	}
+
+	compositeSourcer struct {
+		sourcers []ConfigSourcer
+	}
+)
+
+// Get calls f(name).
+func (f ConfigSourcerFunc) Get(name string) (string, bool) {
+	return f(name)
+}
+
+// NewEnvSourcer creates a sourcer which reads from the OS environment.
+func NewEnvSourcer() ConfigSourcer {
+	return envSourcer{}
+}
+
+func (s envSourcer) Get(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+// NewMapSourcer creates a sourcer which reads from the given map. Names are
+// matched case-insensitively.
+func NewMapSourcer(values map[string]string) ConfigSourcer {
+	normalized := map[string]string{}
+	for name, value := range values {
+		normalized[strings.ToUpper(name)] = value
+	}
+
+	return &mapSourcer{values: normalized}
+}
+
+func (s *mapSourcer) Get(name string) (string, bool) {
+	value, ok := s.values[strings.ToUpper(name)]
+	return value, ok
+}
+
+// NewFileSourcer creates a sourcer which reads the values in the file at the
+// given path. A file with the extension .json must contain a JSON object whose
+// values are supplied under its keys (values which are not strings are supplied
+// as JSON). Any other file is read as lines of the form NAME=value, as in a .env
+// file. Names are matched case-insensitively.
+func NewFileSourcer(path string) (ConfigSourcer, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return readJSONSourceFile(path)
+	}
+
+	values, err := readEnvFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s (%s)", path, err.Error())
+	}
+
+	return NewMapSourcer(values), nil
+}
+
+func readJSONSourceFile(path string) (ConfigSourcer, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s (%s)", path, err.Error())
+	}
+
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to read %s (%s)", path, err.Error())
+	}
+
+	values := map[string]string{}
+	for name, value := range raw {
+		var str string
+		if json.Unmarshal(value, &str) == nil {
+			values[name] = str
+			continue
+		}
+
+		values[name] = string(value)
+	}
+
+	return NewMapSourcer(values), nil
+}
+
+// NewFlagSourcer creates a sourcer which reads from command line arguments of
+// the form --name=value or --name value. A flag which is not followed by a value
+// is supplied as true. The dashes within a flag name are treated as underscores,
+// so the flag --http-port supplies the name HTTP_PORT. Arguments which are not
+// flags are ignored.
+func NewFlagSourcer(args []string) ConfigSourcer {
+	values := map[string]string{}
+
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+
+		var (
+			parts = strings.SplitN(strings.TrimLeft(args[i], "-"), "=", 2)
+			name  = strings.Replace(parts[0], "-", "_", -1)
+		)
+
+		if name == "" {
+			continue
+		}
+
+		switch {
+		case len(parts) == 2:
+			values[name] = parts[1]
+		case i+1 < len(args) && !strings.HasPrefix(args[i+1], "-"):
+			values[name] = args[i+1]
+			i++
+		default:
+			values[name] = "true"
+		}
+	}
+
+	return NewMapSourcer(values)
+}
+
+// NewCompositeSourcer creates a sourcer which reads from each of the given
+// sourcers in order, so that a value supplied by an earlier sourcer takes
+// precedence over the values supplied by later sourcers (e.g. flags, then the
+// environment, then a file of defaults).
+func NewCompositeSourcer(sourcers ...ConfigSourcer) ConfigSourcer {
+	return &compositeSourcer{sourcers: sourcers}
+}
+
+func (s *compositeSourcer) Get(name string) (string, bool) {
+	for _, sourcer := range s.sourcers {
+		if value, ok := sourcer.Get(name); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
@@ -2,7 +2,9 @@ package nacelle
 
 import (
 	"errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/aphistic/sweet"
@@ -40,6 +42,38 @@ func (s *ConfigSuite) TestSimpleConfig(t sweet.T) {
 	Expect(chunk.Z).To(Equal([]string{"bar", "baz", "bonk"}))
 }
 
+func (s *ConfigSuite) TestPrefixedConfig(t sweet.T) {
+	var (
+		config = NewEnvConfig("app")
+		chunkA = &TestSimpleConfig{}
+		chunkB = &TestSimpleConfig{}
+	)
+
+	os.Setenv("APP_A_X", "foo")
+	os.Setenv("APP_B_X", "bar")
+	os.Setenv("B_Y", "123")
+
+	Expect(RegisterPrefixed(config, "a", "simple", chunkA)).To(BeNil())
+	Expect(RegisterPrefixed(config, "b", "simple", chunkB)).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+
+	targetA := &TestSimpleConfig{}
+	Expect(NewPrefixedConfig(config, "a").Fetch("simple", targetA)).To(BeNil())
+	Expect(targetA.X).To(Equal("foo"))
+	Expect(targetA.Y).To(Equal(0))
+
+	targetB := &TestSimpleConfig{}
+	Expect(NewPrefixedConfig(config, "b").Fetch("simple", targetB)).To(BeNil())
+	Expect(targetB.X).To(Equal("bar"))
+	Expect(targetB.Y).To(Equal(123))
+
+	_, err := config.Get("simple")
+	Expect(err).To(MatchError("unregistered config key `simple`"))
+
+	_, err = NewPrefixedConfig(config, "c").Get("simple")
+	Expect(err).To(MatchError("unregistered config key `c/simple`"))
+}
+
 func (s *ConfigSuite) TestNestedJSONDeserialization(t sweet.T) {
 	var (
 		config = NewEnvConfig("app")
@@ -386,6 +420,193 @@ func (s *ConfigSuite) TestFetchWithConfigTagRoundtrip(t sweet.T) {
 	Expect(target.duration).To(Equal(time.Second * 3))
 }
 
+func (s *ConfigSuite) TestReload(t sweet.T) {
+	var (
+		config = NewEnvConfig("app").(*EnvConfig)
+		chunk  = &TestPostLoadConfig{}
+	)
+
+	Expect(config.Reload("post-load")).To(ConsistOf(ErrNotLoaded))
+	Expect(config.Register("post-load", chunk)).To(BeNil())
+
+	os.Setenv("APP_X", "3")
+	Expect(config.Load()).To(BeEmpty())
+
+	os.Setenv("APP_X", "5")
+	Expect(config.Reload("post-load")).To(BeEmpty())
+
+	reloaded := config.MustGet("post-load").(*TestPostLoadConfig)
+	Expect(reloaded.X).To(Equal(5))
+	Expect(chunk.X).To(Equal(3))
+
+	// A failed reload retains the previous values
+	os.Setenv("APP_X", "-4")
+	Expect(config.Reload("post-load")).To(ConsistOf(MatchError("X must be positive")))
+	Expect(config.MustGet("post-load")).To(BeIdenticalTo(reloaded))
+
+	Expect(config.Reload("missing")).To(ConsistOf(MatchError("unregistered config key `missing`")))
+}
+
+func (s *ConfigSuite) TestReloadPrefixed(t sweet.T) {
+	config := NewEnvConfig("app")
+	Expect(RegisterPrefixed(config, "a", "simple", &TestSimpleConfig{})).To(BeNil())
+
+	os.Setenv("APP_A_X", "foo")
+	Expect(config.Load()).To(BeEmpty())
+
+	os.Setenv("APP_A_X", "bar")
+	prefixed := NewPrefixedConfig(config, "a")
+	Expect(prefixed.(ReloadableConfig).Reload("simple")).To(BeEmpty())
+
+	target := &TestSimpleConfig{}
+	Expect(prefixed.Fetch("simple", target)).To(BeNil())
+	Expect(target.X).To(Equal("bar"))
+}
+
+func (s *ConfigSuite) TestRegisterConfigService(t sweet.T) {
+	var (
+		config    = NewEnvConfig("app")
+		container = NewServiceContainer()
+		target    = &TestSimpleConfig{}
+	)
+
+	os.Setenv("APP_X", "foo")
+	os.Setenv("APP_Y", "123")
+	Expect(RegisterPrefixed(config, "a", "simple", &TestSimpleConfig{})).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+
+	prefixed := NewPrefixedConfig(config, "a")
+	Expect(RegisterConfigService(prefixed, container, "simple", target)).To(BeNil())
+	Expect(ConfigServiceKey(prefixedKey{"a", "simple"})).To(Equal("config/a/simple"))
+	Expect(ConfigServiceKey(LoggingConfigToken)).To(Equal("config/nacelle-logging"))
+
+	// Injected by key
+	consumer := &TestConfigConsumer{}
+	Expect(container.Inject(consumer)).To(BeNil())
+	Expect(consumer.Config).To(BeIdenticalTo(target))
+	Expect(consumer.Config.X).To(Equal("foo"))
+	Expect(consumer.Config.Y).To(Equal(123))
+
+	// Resolved by type
+	Expect(container.Provide(func(config *TestSimpleConfig) *IntWrapper {
+		return &IntWrapper{config.Y}
+	}, "value")).To(BeNil())
+	Expect(container.MustGet("value")).To(Equal(&IntWrapper{123}))
+}
+
+func (s *ConfigSuite) TestRegisterConfigServiceErrors(t sweet.T) {
+	config := NewEnvConfig("app")
+	container := NewServiceContainer()
+	Expect(config.Register("simple", &TestSimpleConfig{})).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+
+	Expect(RegisterConfigService(config, container, "missing", &TestSimpleConfig{})).To(MatchError("failed to fetch config `missing` (unregistered config key `missing`)"))
+	Expect(RegisterConfigService(config, container, "simple", &TestSimpleConfig{})).To(BeNil())
+	Expect(RegisterConfigService(config, container, "simple", &TestSimpleConfig{})).To(MatchError("duplicate service key `config/simple`"))
+}
+
+func (s *ConfigSuite) TestCompositeSourcer(t sweet.T) {
+	var (
+		flags   = NewFlagSourcer([]string{"--app-x", "flag", "serve"})
+		values  = NewMapSourcer(map[string]string{"app_x": "map", "app_y": "123"})
+		sourcer = NewCompositeSourcer(flags, values, NewEnvSourcer())
+		config  = NewConfig("app", sourcer)
+		chunk   = &TestSimpleConfig{}
+	)
+
+	os.Setenv("APP_X", "env")
+	os.Setenv("APP_Y", "456")
+	os.Setenv("APP_W", `["bar", "baz"]`)
+
+	Expect(config.Register("simple", chunk)).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+	Expect(chunk.X).To(Equal("flag"))
+	Expect(chunk.Y).To(Equal(123))
+	Expect(chunk.Z).To(Equal([]string{"bar", "baz"}))
+}
+
+func (s *ConfigSuite) TestSourcerFunc(t sweet.T) {
+	var (
+		names   = []string{}
+		sourcer = ConfigSourcerFunc(func(name string) (string, bool) {
+			names = append(names, name)
+			return "5", name == "APP_X"
+		})
+
+		config = NewConfig("app", sourcer)
+		chunk  = &TestPostLoadConfig{}
+	)
+
+	Expect(config.Register("post-load", chunk)).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+	Expect(chunk.X).To(Equal(5))
+	Expect(names).To(ContainElement("APP_X"))
+}
+
+func (s *ConfigSuite) TestFlagSourcer(t sweet.T) {
+	sourcer := NewFlagSourcer([]string{"run", "--http-port=8080", "-host", "localhost", "--verbose", "--", "--debug"})
+
+	for name, expected := range map[string]string{
+		"HTTP_PORT": "8080",
+		"host":      "localhost",
+		"VERBOSE":   "true",
+		"DEBUG":     "true",
+	} {
+		value, ok := sourcer.Get(name)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(expected))
+	}
+
+	_, ok := sourcer.Get("RUN")
+	Expect(ok).To(BeFalse())
+}
+
+func (s *ConfigSuite) TestFileSourcer(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-sourcer")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		jsonPath = filepath.Join(dir, "config.json")
+		envPath  = filepath.Join(dir, "config.env")
+	)
+
+	Expect(ioutil.WriteFile(jsonPath, []byte(`{"app_x": "foo", "app_y": 123, "app_w": ["bar", "baz"]}`), 0644)).To(BeNil())
+	Expect(ioutil.WriteFile(envPath, []byte("APP_X=bonk\n"), 0644)).To(BeNil())
+
+	jsonSourcer, err := NewFileSourcer(jsonPath)
+	Expect(err).To(BeNil())
+	envSourcer, err := NewFileSourcer(envPath)
+	Expect(err).To(BeNil())
+
+	var (
+		config = NewConfig("app", NewCompositeSourcer(envSourcer, jsonSourcer))
+		chunk  = &TestSimpleConfig{}
+	)
+
+	Expect(config.Register("simple", chunk)).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+	Expect(chunk.X).To(Equal("bonk"))
+	Expect(chunk.Y).To(Equal(123))
+	Expect(chunk.Z).To(Equal([]string{"bar", "baz"}))
+}
+
+func (s *ConfigSuite) TestFileSourcerErrors(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-sourcer")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	Expect(ioutil.WriteFile(path, []byte(`["foo"]`), 0644)).To(BeNil())
+
+	_, err = NewFileSourcer(path)
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(HavePrefix("failed to read " + path))
+
+	_, err = NewFileSourcer(filepath.Join(dir, "missing.env"))
+	Expect(err).NotTo(BeNil())
+}
+
 //
 // Chunks
 
@@ -396,6 +617,10 @@ type (
 		Z []string `env:"w" display:"q"`
 	}
 
+	TestConfigConsumer struct {
+		Config *TestSimpleConfig `service:"config/a/simple"`
+	}
+
 	TestSimpleConfigClone struct {
 		X string
 		Y int
@@ -0,0 +1,209 @@
+package nacelle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SchemaField describes a field of a registered config struct, as printed by
+// the schema subcommand of the config tool.
+type SchemaField struct {
+	Field    string   `json:"field"`
+	Type     string   `json:"type"`
+	Env      []string `json:"env"`
+	Default  string   `json:"default,omitempty"`
+	Required bool     `json:"required"`
+	Masked   bool     `json:"masked"`
+}
+
+const configToolUsage = "usage: config validate|render|schema"
+
+// RunConfigTool runs a config subcommand with the given arguments and returns
+// a status code - zero on success and one on failure. This allows a binary to
+// expose config subcommands for use in CI and by operators which register and
+// load config exactly as Boot does. The config is registered to an environment
+// config with the given name as its prefix by the given setup function. The
+// subcommands are:
+//
+//	validate - load the config and print each error encountered
+//	render   - load the config and print its values (excluding masked fields)
+//	schema   - print the fields, envvars, and defaults of each config struct
+func RunConfigTool(name string, configSetupFunc ConfigSetupFunc, args []string) int {
+	return runConfigTool(name, NewEnvSourcer(), configSetupFunc, args, os.Stdout, os.Stderr)
+}
+
+// RunConfigTool calls RunConfigTool with the name, config sourcer, and config
+// setup function of the bootstrapper.
+func (bs *Bootstrapper) RunConfigTool(args []string) int {
+	return runConfigTool(bs.name, bs.configSourcer, bs.configSetupFunc, args, os.Stdout, os.Stderr)
+}
+
+func runConfigTool(name string, sourcer ConfigSourcer, configSetupFunc ConfigSetupFunc, args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, configToolUsage)
+		return 1
+	}
+
+	config, err := setupConfig(name, sourcer, configSetupFunc)
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	switch args[0] {
+	case "validate":
+		if !loadConfigForTool(config, stderr) {
+			return 1
+		}
+
+		fmt.Fprintln(stdout, "config is valid")
+		return 0
+
+	case "render":
+		if !loadConfigForTool(config, stderr) {
+			return 1
+		}
+
+		m, err := config.ToMap()
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to serialize config (%s)\n", err.Error())
+			return 1
+		}
+
+		return writeJSON(m, stdout, stderr)
+
+	case "schema":
+		fields, err := configSchema(config, name)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to describe config (%s)\n", err.Error())
+			return 1
+		}
+
+		return writeJSON(fields, stdout, stderr)
+	}
+
+	fmt.Fprintln(stderr, configToolUsage)
+	return 1
+}
+
+// setupConfig creates an environment config with the given prefix and registers
+// the logging config followed by the configs of the given setup function.
+func setupConfig(name string, sourcer ConfigSourcer, configSetupFunc ConfigSetupFunc) (Config, error) {
+	config := NewConfig(name, sourcer)
+
+	if err := config.Register(LoggingConfigToken, &LoggingConfig{}); err != nil {
+		return nil, fmt.Errorf("failed to register logging config (%s)", err.Error())
+	}
+
+	if err := config.Register(KillSwitchConfigToken, &KillSwitchConfig{}); err != nil {
+		return nil, fmt.Errorf("failed to register kill switch config (%s)", err.Error())
+	}
+
+	if err := config.Register(TimeoutsConfigToken, &TimeoutsConfig{}); err != nil {
+		return nil, fmt.Errorf("failed to register timeouts config (%s)", err.Error())
+	}
+
+	if err := configSetupFunc(config); err != nil {
+		return nil, fmt.Errorf("failed to register configs (%s)", err.Error())
+	}
+
+	return config, nil
+}
+
+// loadConfig loads the given config and returns a report of the errors which
+// were encountered, or nil if there were none.
+func loadConfig(config Config) *StartupReport {
+	errs := config.Load()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	report := &StartupReport{}
+	for _, err := range errs {
+		report.add(StageConfig, "", err)
+	}
+
+	return report
+}
+
+func loadConfigForTool(config Config, stderr io.Writer) bool {
+	report := loadConfig(config)
+	if report == nil {
+		return true
+	}
+
+	for _, problem := range report.Problems {
+		fmt.Fprintln(stderr, problem.String())
+	}
+
+	return false
+}
+
+// configSchema describes the env-tagged fields of each struct registered to
+// the given config, ordered by envvar.
+func configSchema(config Config, prefix string) ([]SchemaField, error) {
+	envConfig, ok := config.(*EnvConfig)
+	if !ok {
+		return nil, fmt.Errorf("config is not an environment config")
+	}
+
+	fields := []SchemaField{}
+	for _, chunk := range envConfig.chunks {
+		_, objType := getIndirect(chunk)
+
+		for i := 0; i < objType.NumField(); i++ {
+			var (
+				fieldType        = objType.Field(i)
+				envTagValue      = fieldType.Tag.Get(envTag)
+				requiredTagValue = fieldType.Tag.Get(requiredTag)
+				maskTagValue     = fieldType.Tag.Get(maskTag)
+			)
+
+			if envTagValue == "" {
+				continue
+			}
+
+			required, _ := strconv.ParseBool(requiredTagValue)
+			masked, _ := strconv.ParseBool(maskTagValue)
+
+			fields = append(fields, SchemaField{
+				Field:    fmt.Sprintf("%s.%s", objType.Name(), fieldType.Name),
+				Type:     typeName(fieldType.Type),
+				Env:      envTagNames(prefix, envTagValue),
+				Default:  fieldType.Tag.Get(defaultTag),
+				Required: required,
+				Masked:   masked,
+			})
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Env[0] < fields[j].Env[0]
+	})
+
+	return fields, nil
+}
+
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+
+	return t.String()
+}
+
+func writeJSON(v interface{}, stdout, stderr io.Writer) int {
+	serialized, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to serialize output (%s)\n", err.Error())
+		return 1
+	}
+
+	fmt.Fprintln(stdout, string(serialized))
+	return 0
+}
@@ -0,0 +1,104 @@
+package nacelle
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type (
+	ConfigToolSuite struct{}
+
+	toolConfig struct {
+		Host     string `env:"host" required:"true"`
+		Port     int    `env:"port" default:"8080"`
+		Password string `env:"password" mask:"true"`
+		Computed string
+	}
+)
+
+func (s *ConfigToolSuite) SetUpTest(t sweet.T) {
+	os.Clearenv()
+}
+
+func (s *ConfigToolSuite) TestValidate(t sweet.T) {
+	os.Setenv("APP_HOST", "localhost")
+
+	code, stdout, stderr := runTestConfigTool("validate")
+	Expect(code).To(Equal(0))
+	Expect(stdout).To(Equal("config is valid\n"))
+	Expect(stderr).To(BeEmpty())
+}
+
+func (s *ConfigToolSuite) TestValidateErrors(t sweet.T) {
+	os.Setenv("APP_PORT", "eighty")
+
+	code, stdout, stderr := runTestConfigTool("validate")
+	Expect(code).To(Equal(1))
+	Expect(stdout).To(BeEmpty())
+	Expect(stderr).To(ContainSubstring("config: no value supplied for field 'Host'"))
+	Expect(stderr).To(ContainSubstring("config: value supplied for field 'Port' cannot be coerced into the expected type"))
+}
+
+func (s *ConfigToolSuite) TestRender(t sweet.T) {
+	os.Setenv("APP_HOST", "localhost")
+	os.Setenv("APP_PASSWORD", "secret")
+
+	code, stdout, _ := runTestConfigTool("render")
+	Expect(code).To(Equal(0))
+
+	rendered := map[string]interface{}{}
+	Expect(json.Unmarshal([]byte(stdout), &rendered)).To(BeNil())
+	Expect(rendered).To(HaveKeyWithValue("host", "localhost"))
+	Expect(rendered).To(HaveKeyWithValue("port", "8080"))
+	Expect(rendered).To(HaveKeyWithValue("log_level", "info"))
+	Expect(rendered).NotTo(HaveKey("password"))
+}
+
+func (s *ConfigToolSuite) TestSchema(t sweet.T) {
+	code, stdout, _ := runTestConfigTool("schema")
+	Expect(code).To(Equal(0))
+
+	fields := []SchemaField{}
+	Expect(json.Unmarshal([]byte(stdout), &fields)).To(BeNil())
+
+	schema := map[string]SchemaField{}
+	for _, field := range fields {
+		schema[field.Field] = field
+	}
+
+	Expect(schema).To(HaveKey("Config.LogLevel"))
+	Expect(schema).NotTo(HaveKey("toolConfig.Computed"))
+	Expect(schema["toolConfig.Host"]).To(Equal(SchemaField{
+		Field:    "toolConfig.Host",
+		Type:     "string",
+		Env:      []string{"APP_HOST", "HOST"},
+		Required: true,
+	}))
+	Expect(schema["toolConfig.Port"].Default).To(Equal("8080"))
+	Expect(schema["toolConfig.Password"].Masked).To(BeTrue())
+}
+
+func (s *ConfigToolSuite) TestUsage(t sweet.T) {
+	for _, args := range [][]string{nil, {"dump"}, {"validate", "extra"}} {
+		code, _, stderr := runTestConfigTool(args...)
+		Expect(code).To(Equal(1))
+		Expect(stderr).To(Equal(configToolUsage + "\n"))
+	}
+}
+
+func runTestConfigTool(args ...string) (int, string, string) {
+	var (
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		setup  = func(config Config) error {
+			return config.Register("tool", &toolConfig{})
+		}
+	)
+
+	code := runConfigTool("app", NewEnvSourcer(), setup, args, stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
@@ -0,0 +1,175 @@
+package nacelle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// CrashLoopPolicy determines when a restarting process is considered to be
+	// in a crash loop and how the process runner escalates once it is.
+	CrashLoopPolicy struct {
+		// Threshold is the number of restarts within the window which is
+		// tolerated. A process restarted more often is in a crash loop.
+		Threshold int
+
+		// Window is the sliding time window in which restarts are counted.
+		Window time.Duration
+
+		// Escalation determines what happens to a process in a crash loop.
+		Escalation CrashLoopEscalation
+	}
+
+	// CrashLoopEscalation is the action taken when a process is in a crash loop.
+	CrashLoopEscalation int
+
+	// CrashLoopEvent describes a detected crash loop. It is passed to the hook
+	// registered via WithCrashLoopHook.
+	CrashLoopEvent struct {
+		Process    string
+		Restarts   int
+		Window     time.Duration
+		Escalation CrashLoopEscalation
+		Err        error
+	}
+
+	// CrashLoopHook is called when the process runner detects a crash loop.
+	// This can be used to emit events or metrics.
+	CrashLoopHook func(CrashLoopEvent)
+)
+
+const (
+	// CrashLoopShutdown stops restarting the process and shuts the application
+	// down as if the process had no restart policy.
+	CrashLoopShutdown CrashLoopEscalation = iota
+
+	// CrashLoopGiveUp stops restarting the process and leaves the rest of the
+	// application running.
+	CrashLoopGiveUp
+
+	// CrashLoopDegrade stops restarting the process and leaves the rest of the
+	// application running, but marks the runner as unhealthy.
+	CrashLoopDegrade
+)
+
+func (e CrashLoopEscalation) String() string {
+	switch e {
+	case CrashLoopGiveUp:
+		return "give-up"
+	case CrashLoopDegrade:
+		return "degrade"
+	default:
+		return "shutdown"
+	}
+}
+
+// WithCrashLoopHook sets a function which is called each time a crash loop
+// is detected.
+func WithCrashLoopHook(hook CrashLoopHook) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.crashLoopHook = hook }
+}
+
+// HealthCheck returns an error naming each process which has been abandoned
+// due to a crash loop with the CrashLoopDegrade escalation. The runner is
+// registered to the service container by the bootstrapper so that it is
+// discovered by the health tracker.
+func (pr *ProcessRunner) HealthCheck() error {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	if len(pr.degraded) == 0 {
+		return nil
+	}
+
+	names := []string{}
+	for name := range pr.degraded {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return fmt.Errorf("processes in crash loop (%s)", strings.Join(names, ", "))
+}
+
+// recordRestart records a restart of the process and returns true if the
+// process has been restarted more often than its crash loop policy allows.
+func (pr *ProcessRunner) recordRestart(process *processMeta) bool {
+	policy := process.crashLoopPolicy
+	if policy == nil {
+		return false
+	}
+
+	var (
+		now    = time.Now()
+		recent = []time.Time{}
+	)
+
+	for _, t := range append(process.restartTimes, now) {
+		if now.Sub(t) < policy.Window {
+			recent = append(recent, t)
+		}
+	}
+
+	process.restartTimes = recent
+	return len(recent) > policy.Threshold
+}
+
+// escalateCrashLoop applies the crash loop escalation of the process. The
+// return values have the same meaning as those of restartAfterExit.
+func (pr *ProcessRunner) escalateCrashLoop(process *processMeta, err error) (bool, error) {
+	policy := process.crashLoopPolicy
+	pr.flushFailures(process)
+
+	event := CrashLoopEvent{
+		Process:    process.Name(),
+		Restarts:   len(process.restartTimes),
+		Window:     policy.Window,
+		Escalation: policy.Escalation,
+		Err:        err,
+	}
+
+	crashLoopErr := fmt.Errorf(
+		"%s is in a crash loop (%d restarts within %s)",
+		event.Process,
+		event.Restarts,
+		event.Window,
+	)
+
+	pr.logger.ErrorWithFields(Fields{
+		"process":    event.Process,
+		"restarts":   event.Restarts,
+		"escalation": event.Escalation.String(),
+	}, "%s, escalating by %s", crashLoopErr.Error(), event.Escalation)
+
+	pr.record("%s, escalating by %s", crashLoopErr.Error(), event.Escalation)
+
+	if pr.crashLoopHook != nil {
+		pr.crashLoopHook(event)
+	}
+
+	switch policy.Escalation {
+	case CrashLoopGiveUp:
+		process.abandoned = true
+		return false, nil
+
+	case CrashLoopDegrade:
+		process.abandoned = true
+		pr.setDegraded(process.Name(), true)
+		return false, nil
+
+	default:
+		return false, crashLoopErr
+	}
+}
+
+func (pr *ProcessRunner) setDegraded(name string, degraded bool) {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	if degraded {
+		pr.degraded[name] = struct{}{}
+	} else {
+		delete(pr.degraded, name)
+	}
+}
@@ -0,0 +1,119 @@
+package nacelle
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// EnvironmentEnvvar names the envvar which selects the environment in which
+	// the application runs. See EnvDevelopment.
+	EnvironmentEnvvar = "NACELLE_ENV"
+
+	// EnvDevelopment is the environment in which the bootstrapper applies the
+	// DevelopmentDefaults and the DevelopmentEnvFile when loading the application
+	// config, so that an application can be run locally without setting a dozen
+	// envvars. Nacelle has no template support, so there is no template reload.
+	EnvDevelopment = "development"
+
+	// DevelopmentEnvFile is the file, relative to the working directory, from
+	// which values are loaded in development mode. The file contains lines of
+	// the form KEY=value. Blank lines and lines starting with # are ignored, and
+	// values may be quoted.
+	DevelopmentEnvFile = ".env"
+)
+
+// DevelopmentDefaults are the values supplied to the config by the bootstrapper in
+// development mode. Logs are colorized and written at the debug level, dial timeouts
+// are relaxed for slow local dependencies, and servers listen on ephemeral ports so
+// that several applications can run side-by-side. A default is not applied if the
+// config's sourcer supplies the name (or its prefixed form) or if it is set by the
+// DevelopmentEnvFile.
+var DevelopmentDefaults = map[string]string{
+	"LOG_LEVEL":     "debug",
+	"LOG_ENCODING":  "console",
+	"LOG_COLORIZE":  "true",
+	"TIMEOUT_DIAL":  "30",
+	"TIMEOUT_READ":  "0",
+	"TIMEOUT_WRITE": "0",
+	"HTTP_PORT":     "0",
+	"GRPC_PORT":     "0",
+}
+
+// IsDevelopment returns true if the application is running in development mode.
+func IsDevelopment() bool {
+	return strings.EqualFold(os.Getenv(EnvironmentEnvvar), EnvDevelopment)
+}
+
+// applyDevelopmentDefaults loads the DevelopmentEnvFile, if it exists, and returns
+// a sourcer which supplies the values of the file and the DevelopmentDefaults not
+// supplied by the given sourcer. The given sourcer takes precedence over the file,
+// which takes precedence over the defaults. The process environment is not
+// modified. The names of the values which were applied are returned in sorted
+// order.
+func applyDevelopmentDefaults(prefix string, sourcer ConfigSourcer) (ConfigSourcer, []string, error) {
+	values, err := readEnvFile(DevelopmentEnvFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read %s (%s)", DevelopmentEnvFile, err.Error())
+	}
+
+	for name, value := range DevelopmentDefaults {
+		if _, ok := values[name]; !ok {
+			values[name] = value
+		}
+	}
+
+	applied := []string{}
+	for name := range values {
+		if _, ok := getFirst(sourcer, envTagNames(prefix, name)); ok {
+			delete(values, name)
+			continue
+		}
+
+		applied = append(applied, name)
+	}
+
+	sort.Strings(applied)
+	return NewCompositeSourcer(sourcer, NewMapSourcer(values)), applied, nil
+}
+
+// readEnvFile parses the envvars in the given file. A map is returned even if
+// the file cannot be read.
+func readEnvFile(path string) (map[string]string, error) {
+	values := map[string]string{}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return values, err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return values, fmt.Errorf("malformed line %d", n)
+		}
+
+		values[strings.TrimSpace(parts[0])] = unquote(strings.TrimSpace(parts[1]))
+	}
+
+	return values, scanner.Err()
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
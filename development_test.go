@@ -0,0 +1,111 @@
+package nacelle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type DevelopmentSuite struct{}
+
+func (s *DevelopmentSuite) TestApplyDefaults(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-development")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	Expect(err).To(BeNil())
+	Expect(os.Chdir(dir)).To(BeNil())
+	defer os.Chdir(wd)
+
+	Expect(ioutil.WriteFile(filepath.Join(dir, DevelopmentEnvFile), []byte(`
+# local database
+export DATABASE_URL="postgres://localhost/app"
+LOG_LEVEL=info
+HTTP_PORT=8080
+`), 0644)).To(BeNil())
+
+	os.Setenv("APP_HTTP_PORT", "9090")
+	os.Setenv("GRPC_PORT", "7000")
+	defer os.Clearenv()
+
+	sourcer, applied, err := applyDevelopmentDefaults("app", NewEnvSourcer())
+	Expect(err).To(BeNil())
+	Expect(applied).To(Equal([]string{
+		"DATABASE_URL",
+		"LOG_COLORIZE",
+		"LOG_ENCODING",
+		"LOG_LEVEL",
+		"TIMEOUT_DIAL",
+		"TIMEOUT_READ",
+		"TIMEOUT_WRITE",
+	}))
+
+	// The env file takes precedence over the defaults, and the environment
+	// takes precedence over both
+	for name, expected := range map[string]string{
+		"DATABASE_URL":  "postgres://localhost/app",
+		"LOG_LEVEL":     "info",
+		"LOG_ENCODING":  "console",
+		"GRPC_PORT":     "7000",
+		"APP_HTTP_PORT": "9090",
+	} {
+		value, ok := sourcer.Get(name)
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(expected))
+	}
+
+	_, ok := sourcer.Get("HTTP_PORT")
+	Expect(ok).To(BeFalse())
+
+	// The environment is not modified
+	Expect(os.Getenv("LOG_LEVEL")).To(BeEmpty())
+	Expect(os.Getenv("DATABASE_URL")).To(BeEmpty())
+}
+
+func (s *DevelopmentSuite) TestApplyDefaultsToSourcer(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-development")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	Expect(err).To(BeNil())
+	Expect(os.Chdir(dir)).To(BeNil())
+	defer os.Chdir(wd)
+
+	sourcer, _, err := applyDevelopmentDefaults("app", NewMapSourcer(map[string]string{"log_encoding": "json"}))
+	Expect(err).To(BeNil())
+
+	var (
+		config = NewConfig("app", sourcer)
+		chunk  = &LoggingConfig{}
+	)
+
+	Expect(config.Register(LoggingConfigToken, chunk)).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+	Expect(chunk.LogLevel).To(Equal("debug"))
+	Expect(chunk.LogEncoding).To(Equal("json"))
+}
+
+func (s *DevelopmentSuite) TestMalformedEnvFile(t sweet.T) {
+	file, err := ioutil.TempFile("", "nacelle-env")
+	Expect(err).To(BeNil())
+	defer os.Remove(file.Name())
+
+	file.WriteString("FOO=bar\nBAZ\n")
+	file.Close()
+
+	_, err = readEnvFile(file.Name())
+	Expect(err).To(MatchError("malformed line 2"))
+}
+
+func (s *DevelopmentSuite) TestIsDevelopment(t sweet.T) {
+	defer os.Clearenv()
+
+	Expect(IsDevelopment()).To(BeFalse())
+	os.Setenv(EnvironmentEnvvar, "Development")
+	Expect(IsDevelopment()).To(BeTrue())
+}
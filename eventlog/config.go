@@ -0,0 +1,56 @@
+package eventlog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type (
+	Config struct {
+		EventLogBackend          string `env:"EVENT_LOG_BACKEND" default:"file"`
+		EventLogPath             string `env:"EVENT_LOG_PATH"`
+		RawEventLogFlushInterval int    `env:"EVENT_LOG_FLUSH_INTERVAL" default:"5"`
+		EventLogBufferSize       int    `env:"EVENT_LOG_BUFFER_SIZE" default:"1000"`
+
+		EventLogFlushInterval time.Duration
+	}
+
+	configToken string
+)
+
+var (
+	ConfigToken             = MakeConfigToken("default")
+	ErrIllegalBackend       = errors.New("illegal event log backend")
+	ErrMissingPath          = errors.New("event log path is required for the file backend")
+	ErrIllegalFlushInterval = errors.New("event log flush interval must be positive")
+	ErrIllegalBufferSize    = errors.New("event log buffer size must be positive")
+)
+
+func MakeConfigToken(name string) interface{} {
+	return configToken(fmt.Sprintf("nacelle-eventlog-%s", name))
+}
+
+func (c *Config) PostLoad() error {
+	switch c.EventLogBackend {
+	case "file":
+		if c.EventLogPath == "" {
+			return ErrMissingPath
+		}
+
+	case "stdout", "custom":
+	default:
+		return ErrIllegalBackend
+	}
+
+	if c.RawEventLogFlushInterval <= 0 {
+		return ErrIllegalFlushInterval
+	}
+
+	if c.EventLogBufferSize <= 0 {
+		return ErrIllegalBufferSize
+	}
+
+	c.EventLogFlushInterval = time.Duration(c.RawEventLogFlushInterval) * time.Second
+	return nil
+}
@@ -0,0 +1,245 @@
+package eventlog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// EventLog records typed business and audit events, kept separate from the
+	// diagnostic logger so that the pipelines consuming them are not polluted by
+	// free-form messages. Each event type must be registered with a schema, and
+	// an event which does not conform to its schema is rejected at emit time.
+	// Events are buffered and written to a sink periodically, when the buffer
+	// fills, and once more when the process runner finalizes the event log.
+	//
+	// An event log is a process. It should be registered to the service container
+	// (so that it can be injected into the processes which emit events) and to the
+	// process runner at a lower priority than the processes which emit events, so
+	// that it is stopped after them.
+	EventLog struct {
+		Logger      nacelle.Logger `service:"logger"`
+		configToken interface{}
+		clock       glock.Clock
+		mutex       sync.Mutex
+		flushMutex  sync.Mutex
+		schemas     map[string]Schema
+		buffer      []Event
+		bufferSize  int
+		interval    time.Duration
+		sink        Sink
+		closed      bool
+		full        chan struct{}
+		halt        chan struct{}
+		once        *sync.Once
+	}
+)
+
+var (
+	ErrBadConfig   = errors.New("event log config not registered properly")
+	ErrMissingSink = errors.New("event log backend is custom but no sink was supplied")
+	ErrClosed      = errors.New("event log is closed")
+)
+
+// NewEventLog creates a new event log.
+func NewEventLog(configs ...ConfigFunc) *EventLog {
+	return newEventLog(glock.NewRealClock(), configs...)
+}
+
+func newEventLog(clock glock.Clock, configs ...ConfigFunc) *EventLog {
+	options := getOptions(configs)
+
+	return &EventLog{
+		configToken: options.configToken,
+		clock:       clock,
+		schemas:     map[string]Schema{},
+		sink:        options.sink,
+		full:        make(chan struct{}, 1),
+		halt:        make(chan struct{}),
+		once:        &sync.Once{},
+	}
+}
+
+// Register associates a schema with an event type. It is an error to register
+// the same event type twice or to register an invalid schema.
+func (l *EventLog) Register(name string, schema Schema) error {
+	if err := schema.validate(); err != nil {
+		return fmt.Errorf("illegal schema for event `%s` (%s)", name, err.Error())
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.schemas[name]; ok {
+		return fmt.Errorf("duplicate event `%s`", name)
+	}
+
+	l.schemas[name] = schema
+	return nil
+}
+
+// MustRegister calls Register and panics on error.
+func (l *EventLog) MustRegister(name string, schema Schema) {
+	if err := l.Register(name, schema); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Emit validates the given fields against the schema of the event type and
+// buffers the event for delivery. It is an error to emit an event of a type
+// which has not been registered or an event which does not conform to its
+// schema. Events emitted before the event log is initialized are buffered.
+func (l *EventLog) Emit(name string, fields map[string]interface{}) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	schema, ok := l.schemas[name]
+	if !ok {
+		return fmt.Errorf("event `%s` is not registered", name)
+	}
+
+	if err := schema.check(fields); err != nil {
+		return fmt.Errorf("invalid event `%s` (%s)", name, err.Error())
+	}
+
+	copied := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		copied[key] = value
+	}
+
+	l.buffer = append(l.buffer, Event{
+		Name:   name,
+		Time:   l.clock.Now().UTC(),
+		Fields: copied,
+	})
+
+	if l.bufferSize > 0 && len(l.buffer) >= l.bufferSize {
+		select {
+		case l.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (l *EventLog) Init(config nacelle.Config) error {
+	eventLogConfig := &Config{}
+	if err := config.Fetch(l.configToken, eventLogConfig); err != nil {
+		return ErrBadConfig
+	}
+
+	l.bufferSize = eventLogConfig.EventLogBufferSize
+	l.interval = eventLogConfig.EventLogFlushInterval
+	l.halt = make(chan struct{})
+	l.once = &sync.Once{}
+
+	if l.sink != nil {
+		return nil
+	}
+
+	switch eventLogConfig.EventLogBackend {
+	case "file":
+		sink, err := NewFileSink(eventLogConfig.EventLogPath)
+		if err != nil {
+			return err
+		}
+
+		l.sink = sink
+
+	case "stdout":
+		l.sink = NewWriterSink(os.Stdout)
+
+	case "custom":
+		return ErrMissingSink
+	}
+
+	return nil
+}
+
+func (l *EventLog) Start() error {
+	defer l.Stop()
+
+	for {
+		select {
+		case <-l.halt:
+			return nil
+		case <-l.full:
+		case <-l.clock.After(l.interval):
+		}
+
+		if err := l.Flush(); err != nil {
+			l.Logger.Error("Failed to flush event log (%s)", err.Error())
+		}
+	}
+}
+
+func (l *EventLog) Stop() (err error) {
+	l.once.Do(func() { close(l.halt) })
+	return
+}
+
+// Finalize rejects any further events, writes all buffered events to the sink,
+// and closes the sink. This is called by the process runner after all processes
+// have exited, so events emitted while other processes shut down are not lost.
+func (l *EventLog) Finalize() error {
+	l.mutex.Lock()
+	l.closed = true
+	l.mutex.Unlock()
+
+	if l.sink == nil {
+		return nil
+	}
+
+	if err := l.Flush(); err != nil {
+		l.mutex.Lock()
+		lost := len(l.buffer)
+		l.mutex.Unlock()
+
+		l.sink.Close()
+		return fmt.Errorf("failed to flush %d events (%s)", lost, err.Error())
+	}
+
+	return l.sink.Close()
+}
+
+// Flush writes all buffered events to the sink. Events remain buffered if the
+// sink returns an error. Flush does nothing before the event log is initialized.
+func (l *EventLog) Flush() error {
+	l.flushMutex.Lock()
+	defer l.flushMutex.Unlock()
+
+	if l.sink == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	batch := l.buffer
+	l.buffer = nil
+	l.mutex.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := l.sink.Write(batch); err != nil {
+		l.mutex.Lock()
+		l.buffer = append(batch, l.buffer...)
+		l.mutex.Unlock()
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package eventlog
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle/log"
+)
+
+type EventLogSuite struct{}
+
+func (s *EventLogSuite) TestEmit(t sweet.T) {
+	var (
+		sink     = &mockSink{}
+		eventLog = newEventLog(glock.NewMockClock(), WithSink(sink))
+	)
+
+	eventLog.MustRegister("order_placed", orderSchema)
+	Expect(eventLog.Register("order_placed", orderSchema)).To(MatchError("duplicate event `order_placed`"))
+
+	err := eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_BACKEND": "custom"}))
+	Expect(err).To(BeNil())
+
+	Expect(eventLog.Emit("order_placed", map[string]interface{}{
+		"order_id": "o-123",
+		"quantity": 2,
+		"total":    24.5,
+	})).To(BeNil())
+
+	Expect(eventLog.Emit("order_shipped", nil)).To(MatchError("event `order_shipped` is not registered"))
+	Expect(eventLog.Emit("order_placed", map[string]interface{}{"order_id": "o-124"})).To(MatchError("" +
+		"invalid event `order_placed` (missing required field `quantity`, missing required field `total`)",
+	))
+
+	Expect(sink.written()).To(BeEmpty())
+	Expect(eventLog.Flush()).To(BeNil())
+
+	events := sink.written()
+	Expect(events).To(HaveLen(1))
+	Expect(events[0].Name).To(Equal("order_placed"))
+	Expect(events[0].Fields).To(HaveKeyWithValue("order_id", "o-123"))
+}
+
+func (s *EventLogSuite) TestFlushInterval(t sweet.T) {
+	var (
+		clock    = glock.NewMockClock()
+		sink     = &mockSink{}
+		eventLog = newEventLog(clock, WithSink(sink))
+		errChan  = make(chan error)
+	)
+
+	eventLog.Logger = log.NewNilLogger()
+	eventLog.MustRegister("login", Schema{Fields: []Field{{Name: "user", Type: TypeString}}})
+
+	err := eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_BACKEND": "custom"}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- eventLog.Start()
+	}()
+
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "alice"})).To(BeNil())
+	Consistently(sink.written).Should(BeEmpty())
+
+	clock.BlockingAdvance(time.Second * 5)
+	Eventually(sink.written).Should(HaveLen(1))
+
+	eventLog.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *EventLogSuite) TestFlushWhenFull(t sweet.T) {
+	var (
+		sink     = &mockSink{}
+		eventLog = newEventLog(glock.NewMockClock(), WithSink(sink))
+		errChan  = make(chan error)
+	)
+
+	eventLog.Logger = log.NewNilLogger()
+	eventLog.MustRegister("login", Schema{Fields: []Field{{Name: "user", Type: TypeString}}})
+
+	err := eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_BACKEND": "custom", "EVENT_LOG_BUFFER_SIZE": "3"}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- eventLog.Start()
+	}()
+
+	for _, user := range []string{"alice", "bob"} {
+		Expect(eventLog.Emit("login", map[string]interface{}{"user": user})).To(BeNil())
+	}
+
+	Consistently(sink.written).Should(BeEmpty())
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "carol"})).To(BeNil())
+	Eventually(sink.written).Should(HaveLen(3))
+
+	eventLog.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *EventLogSuite) TestFlushRetry(t sweet.T) {
+	var (
+		sink     = &mockSink{}
+		eventLog = newEventLog(glock.NewMockClock(), WithSink(sink))
+	)
+
+	eventLog.MustRegister("login", Schema{Fields: []Field{{Name: "user", Type: TypeString}}})
+	Expect(eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_BACKEND": "custom"}))).To(BeNil())
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "alice"})).To(BeNil())
+
+	sink.setError(errors.New("utoh"))
+	Expect(eventLog.Flush()).To(MatchError("utoh"))
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "bob"})).To(BeNil())
+
+	sink.setError(nil)
+	Expect(eventLog.Flush()).To(BeNil())
+
+	events := sink.written()
+	Expect(events).To(HaveLen(2))
+	Expect(events[0].Fields["user"]).To(Equal("alice"))
+	Expect(events[1].Fields["user"]).To(Equal("bob"))
+}
+
+func (s *EventLogSuite) TestFinalize(t sweet.T) {
+	var (
+		sink     = &mockSink{}
+		eventLog = newEventLog(glock.NewMockClock(), WithSink(sink))
+	)
+
+	eventLog.MustRegister("login", Schema{Fields: []Field{{Name: "user", Type: TypeString}}})
+	Expect(eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_BACKEND": "custom"}))).To(BeNil())
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "alice"})).To(BeNil())
+
+	Expect(eventLog.Finalize()).To(BeNil())
+	Expect(sink.written()).To(HaveLen(1))
+	Expect(sink.closed).To(BeTrue())
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "bob"})).To(Equal(ErrClosed))
+}
+
+func (s *EventLogSuite) TestFinalizeFailure(t sweet.T) {
+	var (
+		sink     = &mockSink{err: errors.New("utoh")}
+		eventLog = newEventLog(glock.NewMockClock(), WithSink(sink))
+	)
+
+	eventLog.MustRegister("login", Schema{Fields: []Field{{Name: "user", Type: TypeString}}})
+	Expect(eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_BACKEND": "custom"}))).To(BeNil())
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "alice"})).To(BeNil())
+	Expect(eventLog.Finalize()).To(MatchError("failed to flush 1 events (utoh)"))
+}
+
+func (s *EventLogSuite) TestFileSink(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-eventlog")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		path     = filepath.Join(dir, "events", "audit.log")
+		eventLog = newEventLog(glock.NewMockClock())
+	)
+
+	eventLog.MustRegister("login", Schema{Fields: []Field{{Name: "user", Type: TypeString}}})
+	Expect(eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_PATH": path}))).To(BeNil())
+	Expect(eventLog.Emit("login", map[string]interface{}{"user": "alice"})).To(BeNil())
+	Expect(eventLog.Finalize()).To(BeNil())
+
+	content, err := ioutil.ReadFile(path)
+	Expect(err).To(BeNil())
+	Expect(strings.Count(string(content), "\n")).To(Equal(1))
+	Expect(string(content)).To(ContainSubstring(`"event":"login"`))
+	Expect(string(content)).To(ContainSubstring(`"fields":{"user":"alice"}`))
+}
+
+func (s *EventLogSuite) TestWriterSink(t sweet.T) {
+	buffer := &bytes.Buffer{}
+	sink := NewWriterSink(buffer)
+
+	Expect(sink.Write([]Event{
+		{Name: "a", Time: time.Unix(0, 0).UTC(), Fields: map[string]interface{}{"x": 1}},
+		{Name: "b", Time: time.Unix(0, 0).UTC(), Fields: map[string]interface{}{}},
+	})).To(BeNil())
+
+	Expect(buffer.String()).To(Equal("" +
+		`{"event":"a","timestamp":"1970-01-01T00:00:00Z","fields":{"x":1}}` + "\n" +
+		`{"event":"b","timestamp":"1970-01-01T00:00:00Z","fields":{}}` + "\n",
+	))
+}
+
+func (s *EventLogSuite) TestCustomBackendWithoutSink(t sweet.T) {
+	eventLog := NewEventLog()
+	err := eventLog.Init(makeEnvConfig(map[string]string{"EVENT_LOG_BACKEND": "custom"}))
+	Expect(err).To(Equal(ErrMissingSink))
+}
+
+func (s *EventLogSuite) TestBadConfig(t sweet.T) {
+	eventLog := NewEventLog()
+	err := eventLog.Init(makeConfig(ConfigToken, &emptyConfig{}))
+	Expect(err).To(Equal(ErrBadConfig))
+}
@@ -0,0 +1,91 @@
+package eventlog
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/aphistic/sweet"
+	"github.com/aphistic/sweet-junit"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+)
+
+func TestMain(m *testing.M) {
+	RegisterFailHandler(sweet.GomegaFail)
+
+	sweet.Run(m, func(s *sweet.S) {
+		s.RegisterPlugin(junit.NewPlugin())
+
+		s.AddSuite(&EventLogSuite{})
+		s.AddSuite(&SchemaSuite{})
+	})
+}
+
+//
+// Config
+
+type emptyConfig struct{}
+
+func makeConfig(token, base interface{}) nacelle.Config {
+	config := nacelle.NewEnvConfig("")
+	config.Register(token, base)
+	config.Load()
+
+	return config
+}
+
+// makeEnvConfig loads the event log config from the given environment.
+func makeEnvConfig(env map[string]string) nacelle.Config {
+	for key, value := range env {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+
+	return makeConfig(ConfigToken, &Config{})
+}
+
+//
+// Mocks
+
+type mockSink struct {
+	mutex  sync.Mutex
+	events []Event
+	err    error
+	closed bool
+}
+
+func (s *mockSink) Write(events []Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *mockSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func (s *mockSink) setError(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.err = err
+}
+
+func (s *mockSink) written() []Event {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]Event{}, s.events...)
+}
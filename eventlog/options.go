@@ -0,0 +1,35 @@
+package eventlog
+
+type (
+	options struct {
+		configToken interface{}
+		sink        Sink
+	}
+
+	// ConfigFunc is a function used to configure an instance of an EventLog.
+	ConfigFunc func(*options)
+)
+
+// WithConfigToken sets the config token to use. This is useful if an application
+// has multiple event logs with different configuration tags.
+func WithConfigToken(token interface{}) ConfigFunc {
+	return func(o *options) { o.configToken = token }
+}
+
+// WithSink sets the sink to which events are written. This is required when the
+// configured backend is custom, and takes precedence over any other backend.
+func WithSink(sink Sink) ConfigFunc {
+	return func(o *options) { o.sink = sink }
+}
+
+func getOptions(configs []ConfigFunc) *options {
+	options := &options{
+		configToken: ConfigToken,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
@@ -0,0 +1,160 @@
+package eventlog
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// Schema describes the fields of an event type. An event is rejected at
+	// emit time if it is missing a required field, has a field which is not
+	// described by the schema, or has a field of the wrong type.
+	Schema struct {
+		Fields []Field
+	}
+
+	// Field describes a single field of an event.
+	Field struct {
+		Name     string
+		Type     FieldType
+		Optional bool
+	}
+
+	// FieldType is the type of value a field may hold.
+	FieldType int
+)
+
+const (
+	TypeString FieldType = iota
+	TypeInt
+	TypeFloat
+	TypeBool
+	TypeTime
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (t FieldType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "int"
+	case TypeFloat:
+		return "float"
+	case TypeBool:
+		return "bool"
+	case TypeTime:
+		return "time"
+	}
+
+	return fmt.Sprintf("FieldType(%d)", int(t))
+}
+
+// validate returns an error if the schema describes the same field twice or
+// describes a field with an unknown type.
+func (s Schema) validate() error {
+	names := map[string]struct{}{}
+	for _, field := range s.Fields {
+		if field.Name == "" {
+			return fmt.Errorf("field has no name")
+		}
+
+		if _, ok := names[field.Name]; ok {
+			return fmt.Errorf("duplicate field `%s`", field.Name)
+		}
+
+		if field.Type < TypeString || field.Type > TypeTime {
+			return fmt.Errorf("field `%s` has unknown type %s", field.Name, field.Type)
+		}
+
+		names[field.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// check returns an error describing each way in which the given fields do
+// not conform to the schema.
+func (s Schema) check(fields map[string]interface{}) error {
+	problems := []string{}
+	described := map[string]struct{}{}
+
+	for _, field := range s.Fields {
+		described[field.Name] = struct{}{}
+
+		value, ok := fields[field.Name]
+		if !ok {
+			if !field.Optional {
+				problems = append(problems, fmt.Sprintf("missing required field `%s`", field.Name))
+			}
+
+			continue
+		}
+
+		if !field.Type.accepts(value) {
+			problems = append(problems, fmt.Sprintf(
+				"field `%s` must be a %s, not %T",
+				field.Name,
+				field.Type,
+				value,
+			))
+		}
+	}
+
+	unknown := []string{}
+	for name := range fields {
+		if _, ok := described[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		problems = append(problems, fmt.Sprintf("unknown field `%s`", name))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(problems, ", "))
+}
+
+func (t FieldType) accepts(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch t {
+	case TypeString:
+		return v.Kind() == reflect.String
+	case TypeInt:
+		return isInt(v.Kind())
+	case TypeFloat:
+		return isInt(v.Kind()) || v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+	case TypeBool:
+		return v.Kind() == reflect.Bool
+	case TypeTime:
+		return v.Type() == timeType
+	}
+
+	return false
+}
+
+func isInt(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
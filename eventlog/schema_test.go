@@ -0,0 +1,53 @@
+package eventlog
+
+import (
+	"time"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type SchemaSuite struct{}
+
+var orderSchema = Schema{
+	Fields: []Field{
+		{Name: "order_id", Type: TypeString},
+		{Name: "quantity", Type: TypeInt},
+		{Name: "total", Type: TypeFloat},
+		{Name: "gift", Type: TypeBool, Optional: true},
+		{Name: "placed_at", Type: TypeTime, Optional: true},
+	},
+}
+
+func (s *SchemaSuite) TestCheck(t sweet.T) {
+	Expect(orderSchema.check(map[string]interface{}{
+		"order_id":  "o-123",
+		"quantity":  uint8(3),
+		"total":     12,
+		"placed_at": time.Now(),
+	})).To(BeNil())
+}
+
+func (s *SchemaSuite) TestCheckProblems(t sweet.T) {
+	err := orderSchema.check(map[string]interface{}{
+		"quantity": 1.5,
+		"total":    "12.00",
+		"coupon":   "SAVE10",
+		"discount": nil,
+	})
+
+	Expect(err).To(MatchError("" +
+		"missing required field `order_id`, " +
+		"field `quantity` must be a int, not float64, " +
+		"field `total` must be a float, not string, " +
+		"unknown field `coupon`, " +
+		"unknown field `discount`",
+	))
+}
+
+func (s *SchemaSuite) TestValidate(t sweet.T) {
+	Expect(orderSchema.validate()).To(BeNil())
+	Expect(Schema{Fields: []Field{{Type: TypeInt}}}.validate()).To(MatchError("field has no name"))
+	Expect(Schema{Fields: []Field{{Name: "a"}, {Name: "a"}}}.validate()).To(MatchError("duplicate field `a`"))
+	Expect(Schema{Fields: []Field{{Name: "a", Type: FieldType(12)}}}.validate()).To(MatchError("field `a` has unknown type FieldType(12)"))
+}
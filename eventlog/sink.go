@@ -0,0 +1,91 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type (
+	// Sink is the destination of an event log. A sink for a message broker
+	// (e.g. Kafka) can be supplied to an event log with WithSink.
+	Sink interface {
+		// Write persists the given batch of events. The batch is retried
+		// on the next flush if an error is returned.
+		Write(events []Event) error
+
+		// Close releases any resources held by the sink.
+		Close() error
+	}
+
+	// Event is a validated event awaiting delivery to a sink.
+	Event struct {
+		Name   string                 `json:"event"`
+		Time   time.Time              `json:"timestamp"`
+		Fields map[string]interface{} `json:"fields"`
+	}
+
+	writerSink struct {
+		mutex  sync.Mutex
+		writer io.Writer
+		closer io.Closer
+	}
+)
+
+// NewWriterSink creates a sink which writes each event as a single line of
+// JSON to the given writer.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{writer: w}
+}
+
+// NewFileSink creates a sink which appends each event as a single line of
+// JSON to the file at the given path. The file and its parent directories
+// are created if they do not exist.
+func NewFileSink(path string) (Sink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &writerSink{writer: file, closer: file}, nil
+}
+
+func (s *writerSink) Write(events []Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	buffer := []byte{}
+	for _, event := range events {
+		serialized, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		buffer = append(append(buffer, serialized...), '\n')
+	}
+
+	if _, err := s.writer.Write(buffer); err != nil {
+		return err
+	}
+
+	if syncer, ok := s.writer.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+
+	return nil
+}
+
+func (s *writerSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+
+	return s.closer.Close()
+}
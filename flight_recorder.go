@@ -0,0 +1,156 @@
+package nacelle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/efritz/nacelle/log"
+)
+
+type (
+	// FlightRecorder keeps a bounded in-memory history of recent lifecycle
+	// events, log messages, and metric snapshots. Once the recorder is full,
+	// the oldest event is overwritten by each new event. The history can be
+	// dumped on demand (on a signal or from an admin endpoint) or when a process
+	// crashes (see WithFlightRecorder) in order to reconstruct the moments before
+	// a failure.
+	FlightRecorder struct {
+		events []FlightEvent
+		next   int
+		full   bool
+		mutex  sync.Mutex
+	}
+
+	// FlightEvent is an event captured by a flight recorder.
+	FlightEvent struct {
+		Time    time.Time `json:"time"`
+		Kind    string    `json:"kind"`
+		Message string    `json:"message"`
+		Fields  Fields    `json:"fields,omitempty"`
+	}
+)
+
+const (
+	// FlightEventLifecycle is the kind of events recorded by the process runner.
+	FlightEventLifecycle = "lifecycle"
+
+	// FlightEventLog is the kind of events recorded from log messages.
+	FlightEventLog = "log"
+
+	// FlightEventSnapshot is the kind of events recorded via Snapshot.
+	FlightEventSnapshot = "snapshot"
+)
+
+// NewFlightRecorder creates a flight recorder which holds the given number of
+// most recent events.
+func NewFlightRecorder(capacity int) *FlightRecorder {
+	return &FlightRecorder{
+		events: make([]FlightEvent, capacity),
+	}
+}
+
+// Record adds an event of the given kind to the recorder.
+func (r *FlightRecorder) Record(kind string, fields Fields, format string, args ...interface{}) {
+	r.add(FlightEvent{
+		Time:    time.Now(),
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  fields,
+	})
+}
+
+// Snapshot records the current values of a set of metrics with the given name.
+func (r *FlightRecorder) Snapshot(name string, values Fields) {
+	r.Record(FlightEventSnapshot, values, "%s", name)
+}
+
+// WrapLogger returns a logger which records each message logged at the given
+// level or a more severe level before logging it with the given logger.
+func (r *FlightRecorder) WrapLogger(logger Logger, level LogLevel) Logger {
+	return log.NewRecordingAdapter(logger, level, func(level LogLevel, fields Fields, message string) {
+		r.Record(FlightEventLog, fields, "%s", message)
+	})
+}
+
+func (r *FlightRecorder) add(event FlightEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.events) == 0 {
+		return
+	}
+
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	r.full = r.full || r.next == 0
+}
+
+// Events returns the recorded events, oldest first.
+func (r *FlightRecorder) Events() []FlightEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.full {
+		return append([]FlightEvent{}, r.events[:r.next]...)
+	}
+
+	return append(append([]FlightEvent{}, r.events[r.next:]...), r.events[:r.next]...)
+}
+
+// Dump writes the recorded events, oldest first, to the given writer as a
+// sequence of JSON objects separated by newlines.
+func (r *FlightRecorder) Dump(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	for _, event := range r.Events() {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DumpOnSignal dumps the recorded events to the given writer each time one of
+// the given signals is received (e.g. SIGUSR1). The returned function stops
+// listening for the signals.
+func (r *FlightRecorder) DumpOnSignal(w io.Writer, signals ...os.Signal) func() {
+	var (
+		sigChan = make(chan os.Signal, 1)
+		halt    = make(chan struct{})
+		once    = &sync.Once{}
+	)
+
+	signal.Notify(sigChan, signals...)
+
+	go func() {
+		for {
+			select {
+			case <-sigChan:
+				r.Dump(w)
+			case <-halt:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigChan)
+			close(halt)
+		})
+	}
+}
+
+// ServeHTTP dumps the recorded events as the response body. This allows the
+// recorder to be mounted as a handler of an admin server.
+func (r *FlightRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	r.Dump(w)
+}
@@ -0,0 +1,68 @@
+package nacelle
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/nacelle/log"
+	. "github.com/onsi/gomega"
+)
+
+type FlightRecorderSuite struct{}
+
+func (s *FlightRecorderSuite) TestEvents(t sweet.T) {
+	recorder := NewFlightRecorder(3)
+	recorder.Record(FlightEventLifecycle, nil, "a %d", 1)
+	recorder.Record(FlightEventLifecycle, nil, "b %d", 2)
+	Expect(flightMessages(recorder.Events())).To(Equal([]string{"a 1", "b 2"}))
+
+	recorder.Record(FlightEventLifecycle, nil, "c %d", 3)
+	recorder.Record(FlightEventLifecycle, nil, "d %d", 4)
+	recorder.Record(FlightEventLifecycle, nil, "e %d", 5)
+	Expect(flightMessages(recorder.Events())).To(Equal([]string{"c 3", "d 4", "e 5"}))
+}
+
+func (s *FlightRecorderSuite) TestWrapLogger(t sweet.T) {
+	recorder := NewFlightRecorder(10)
+	logger := recorder.WrapLogger(log.NewNilLogger(), LevelWarning)
+	logger.Info("skipped")
+	logger.WarningWithFields(Fields{"x": 1}, "kept %s", "warning")
+	logger.Error("kept error")
+
+	events := recorder.Events()
+	Expect(flightMessages(events)).To(Equal([]string{"kept warning", "kept error"}))
+	Expect(events[0].Kind).To(Equal(FlightEventLog))
+	Expect(events[0].Fields["x"]).To(Equal(1))
+}
+
+func (s *FlightRecorderSuite) TestDump(t sweet.T) {
+	recorder := NewFlightRecorder(10)
+	recorder.Record(FlightEventLifecycle, nil, "Starting %s", "a")
+	recorder.Snapshot("goroutines", Fields{"count": 12})
+
+	buffer := &bytes.Buffer{}
+	Expect(recorder.Dump(buffer)).To(BeNil())
+
+	events := []FlightEvent{}
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		event := FlightEvent{}
+		Expect(decoder.Decode(&event)).To(BeNil())
+		events = append(events, event)
+	}
+
+	Expect(flightMessages(events)).To(Equal([]string{"Starting a", "goroutines"}))
+	Expect(events[0].Kind).To(Equal(FlightEventLifecycle))
+	Expect(events[1].Kind).To(Equal(FlightEventSnapshot))
+	Expect(events[1].Fields).To(Equal(Fields{"count": float64(12)}))
+}
+
+func flightMessages(events []FlightEvent) []string {
+	messages := []string{}
+	for _, event := range events {
+		messages = append(messages, event.Message)
+	}
+
+	return messages
+}
@@ -0,0 +1,80 @@
+package nacelle
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvProduction is the environment (see EnvironmentEnvvar) in which the
+// bootstrapper enforces the invariants expected of a production deployment
+// before starting any process: secrets must not take their default values, and
+// logs must be written at the info level or above. Base processes enforce their
+// own invariants from their Init methods (e.g. the HTTP server requires TLS on
+// listeners which are not bound to localhost, and serves debug endpoints only
+// on localhost). A violation refuses the application start.
+const EnvProduction = "production"
+
+// StageHardening is the stage of the problems found by the production checks.
+const StageHardening = "hardening"
+
+// IsProduction returns true if the application is running in production mode.
+func IsProduction() bool {
+	return strings.EqualFold(os.Getenv(EnvironmentEnvvar), EnvProduction)
+}
+
+// checkHardening returns a report of each production invariant violated by
+// the given config.
+func checkHardening(config Config, loggingConfig *LoggingConfig) *StartupReport {
+	report := &StartupReport{}
+
+	for _, secret := range defaultedSecrets(config) {
+		report.add(StageHardening, "", fmt.Errorf("secret %s uses its default value", secret))
+	}
+
+	if loggingConfig.LogLevel == "debug" {
+		report.add(StageHardening, "", fmt.Errorf("log level must be info or above (found debug)"))
+	}
+
+	for _, backend := range loggingConfig.LogBackends {
+		if strings.ToLower(backend.Level) == "debug" {
+			report.add(StageHardening, "", fmt.Errorf("log level of %s backend must be info or above (found debug)", backend.Backend))
+		}
+	}
+
+	return report
+}
+
+// defaultedSecrets returns the envvar of each masked field with a default value
+// which is not supplied by the config's sourcer, in sorted order. Only an
+// EnvConfig can be checked.
+func defaultedSecrets(config Config) []string {
+	envConfig, ok := config.(*EnvConfig)
+	if !ok {
+		return nil
+	}
+
+	secrets := []string{}
+	for _, chunk := range envConfig.chunks {
+		for _, field := range getExportedFields(chunk) {
+			var (
+				envTagValue     = field.fieldType.Tag.Get(envTag)
+				maskTagValue    = field.fieldType.Tag.Get(maskTag)
+				defaultTagValue = field.fieldType.Tag.Get(defaultTag)
+			)
+
+			if masked, _ := strconv.ParseBool(maskTagValue); !masked || envTagValue == "" || defaultTagValue == "" {
+				continue
+			}
+
+			if _, ok := getFirst(envConfig.sourcer, envTagNames(envConfig.prefix, envTagValue)); !ok {
+				secrets = append(secrets, strings.ToUpper(envTagValue))
+			}
+		}
+	}
+
+	sort.Strings(secrets)
+	return secrets
+}
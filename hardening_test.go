@@ -0,0 +1,48 @@
+package nacelle
+
+import (
+	"os"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type HardeningSuite struct{}
+
+func (s *HardeningSuite) TestCheckHardening(t sweet.T) {
+	config := NewEnvConfig("app")
+	config.MustRegister("secrets", &hardeningConfig{})
+
+	os.Setenv("APP_API_KEY", "s3cr3t")
+	defer os.Clearenv()
+
+	Expect(config.Load()).To(BeEmpty())
+
+	report := checkHardening(config, &LoggingConfig{
+		LogLevel:    "debug",
+		LogBackends: []BackendConfig{{Backend: "zap", Level: "info"}, {Backend: "gomol", Level: "DEBUG"}},
+	})
+
+	Expect(report.Err()).To(MatchError("found 3 startup problems (" +
+		"hardening: secret DB_PASSWORD uses its default value; " +
+		"hardening: log level must be info or above (found debug); " +
+		"hardening: log level of gomol backend must be info or above (found debug))"))
+}
+
+func (s *HardeningSuite) TestCheckHardeningPasses(t sweet.T) {
+	config := NewEnvConfig("")
+	config.MustRegister("secrets", &hardeningConfig{})
+
+	os.Setenv("API_KEY", "s3cr3t")
+	os.Setenv("DB_PASSWORD", "hunter2")
+	defer os.Clearenv()
+
+	Expect(config.Load()).To(BeEmpty())
+	Expect(checkHardening(config, &LoggingConfig{LogLevel: "info"}).Err()).To(BeNil())
+}
+
+type hardeningConfig struct {
+	APIKey     string `env:"api_key" mask:"true" default:"changeme"`
+	DBPassword string `env:"db_password" mask:"true" default:"postgres"`
+	DBHost     string `env:"db_host" default:"localhost"`
+}
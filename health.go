@@ -0,0 +1,140 @@
+package nacelle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// HealthChecker is an optional interface for services registered to the
+	// service container. Services which implement this interface are found
+	// automatically by the health tracker and contribute to the health of the
+	// application (e.g. a database pool which has lost its connection).
+	HealthChecker interface {
+		HealthCheck() error
+	}
+
+	// Health tracks the health of an application. An application is unhealthy
+	// while any registered service reports a failing health check or while any
+	// reason has been added via AddReason.
+	Health struct {
+		container *ServiceContainer
+		reasons   map[interface{}]struct{}
+		mutex     sync.RWMutex
+	}
+)
+
+// NewHealth creates a health tracker which discovers health contributions
+// from services registered to the given container.
+func NewHealth(container *ServiceContainer) *Health {
+	return &Health{
+		container: container,
+		reasons:   map[interface{}]struct{}{},
+	}
+}
+
+// AddReason marks the application as unhealthy for the given reason until the
+// reason is removed. This is generally used by a process which is not able to
+// do its work (e.g. a consumer which is waiting to reconnect). It is an error
+// to add a reason which is already present.
+func (h *Health) AddReason(key interface{}) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.reasons[key]; ok {
+		return fmt.Errorf("reason `%s` already registered", serializeKey(key))
+	}
+
+	h.reasons[key] = struct{}{}
+	return nil
+}
+
+// RemoveReason removes a reason previously added via AddReason. It is an error
+// to remove a reason which is not present.
+func (h *Health) RemoveReason(key interface{}) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.reasons[key]; !ok {
+		return fmt.Errorf("reason `%s` not registered", serializeKey(key))
+	}
+
+	delete(h.reasons, key)
+	return nil
+}
+
+// HasReason returns true if the given reason is present.
+func (h *Health) HasReason(key interface{}) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	_, ok := h.reasons[key]
+	return ok
+}
+
+// Reasons returns the serialized keys of the present reasons, sorted.
+func (h *Health) Reasons() []string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	reasons := []string{}
+	for key := range h.reasons {
+		reasons = append(reasons, serializeKey(key))
+	}
+
+	sort.Strings(reasons)
+	return reasons
+}
+
+// Check calls the HealthCheck method of each registered service which
+// implements the HealthChecker interface and returns a map from the key
+// of each failing service to its error.
+func (h *Health) Check() map[string]error {
+	failures := map[string]error{}
+	for key, service := range h.container.snapshot() {
+		checker, ok := service.(HealthChecker)
+		if !ok {
+			continue
+		}
+
+		if err := checker.HealthCheck(); err != nil {
+			failures[serializeKey(key)] = err
+		}
+	}
+
+	return failures
+}
+
+// Healthy returns true if no reasons are present and no registered service
+// reports a failing health check.
+func (h *Health) Healthy() bool {
+	return len(h.Reasons()) == 0 && len(h.Check()) == 0
+}
+
+// Err returns an error describing every present reason and every failing
+// health check, or nil if the application is healthy.
+func (h *Health) Err() error {
+	var (
+		reasons  = h.Reasons()
+		failures = h.Check()
+	)
+
+	if len(reasons) == 0 && len(failures) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for key, err := range failures {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, err.Error()))
+	}
+
+	sort.Strings(messages)
+
+	if len(reasons) > 0 {
+		messages = append(messages, fmt.Sprintf("reasons: %s", strings.Join(reasons, ", ")))
+	}
+
+	return fmt.Errorf("unhealthy services (%s)", strings.Join(messages, ", "))
+}
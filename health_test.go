@@ -0,0 +1,64 @@
+package nacelle
+
+import (
+	"errors"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type HealthSuite struct{}
+
+func (s *HealthSuite) TestCheck(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		health    = NewHealth(container)
+		a         = &mockHealthChecker{}
+		b         = &mockHealthChecker{}
+	)
+
+	container.Set("a", a)
+	container.Set("b", b)
+	container.Set("c", &IntWrapper{10})
+	Expect(health.Healthy()).To(BeTrue())
+	Expect(health.Err()).To(BeNil())
+
+	a.err = errors.New("utoh")
+	b.err = errors.New("oops")
+	Expect(health.Healthy()).To(BeFalse())
+	Expect(health.Check()).To(HaveLen(2))
+	Expect(health.Err()).To(MatchError("unhealthy services (a: utoh, b: oops)"))
+
+	// Services registered via SetFactory are checked once constructed
+	d := &mockHealthChecker{err: errors.New("down")}
+	container.MustSetFactory("d", func(c *ServiceContainer) (interface{}, error) { return d, nil })
+	Expect(health.Check()).To(HaveLen(2))
+
+	_, err := container.Get("d")
+	Expect(err).To(BeNil())
+	Expect(health.Err()).To(MatchError("unhealthy services (a: utoh, b: oops, d: down)"))
+}
+
+func (s *HealthSuite) TestReasons(t sweet.T) {
+	health := NewHealth(NewServiceContainer())
+	Expect(health.Healthy()).To(BeTrue())
+
+	Expect(health.AddReason("a")).To(BeNil())
+	Expect(health.AddReason("b")).To(BeNil())
+	Expect(health.AddReason("a")).To(MatchError("reason `a` already registered"))
+	Expect(health.HasReason("a")).To(BeTrue())
+	Expect(health.Reasons()).To(Equal([]string{"a", "b"}))
+	Expect(health.Healthy()).To(BeFalse())
+	Expect(health.Err()).To(MatchError("unhealthy services (reasons: a, b)"))
+
+	Expect(health.RemoveReason("a")).To(BeNil())
+	Expect(health.RemoveReason("a")).To(MatchError("reason `a` not registered"))
+	Expect(health.RemoveReason("b")).To(BeNil())
+	Expect(health.Healthy()).To(BeTrue())
+}
+
+type mockHealthChecker struct {
+	err error
+}
+
+func (c *mockHealthChecker) HealthCheck() error { return c.err }
@@ -0,0 +1,25 @@
+package ids
+
+import "errors"
+
+type (
+	Config struct {
+		IDSeed int64 `env:"ID_SEED"`
+		IDNode int   `env:"ID_NODE" default:"0"`
+	}
+
+	configToken string
+)
+
+var (
+	ConfigToken    = configToken("nacelle-ids")
+	ErrIllegalNode = errors.New("ID node must be in the range [0, 1023]")
+)
+
+func (c *Config) PostLoad() error {
+	if c.IDNode < 0 || c.IDNode > snowflakeMaxNode {
+		return ErrIllegalNode
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package ids
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+)
+
+type (
+	// Generator creates unique identifiers.
+	Generator interface {
+		NewID() string
+	}
+
+	// UUIDGenerator creates random (version 4) UUIDs.
+	UUIDGenerator struct {
+		rand *Rand
+	}
+
+	// ULIDGenerator creates ULIDs, which are lexicographically sortable by
+	// their creation time (at millisecond resolution).
+	ULIDGenerator struct {
+		clock glock.Clock
+		rand  *Rand
+	}
+
+	// SnowflakeGenerator creates 63-bit identifiers composed of a millisecond
+	// timestamp, a node number, and a sequence number. Identifiers created by
+	// one generator are strictly increasing, and identifiers created by two
+	// generators with distinct node numbers never collide.
+	SnowflakeGenerator struct {
+		clock    glock.Clock
+		node     int64
+		mutex    sync.Mutex
+		last     int64
+		sequence int64
+	}
+)
+
+const (
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+	snowflakeMaxNode  = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSeq   = 1<<snowflakeSeqBits - 1
+)
+
+// SnowflakeEpoch is the time from which the timestamp of a snowflake ID
+// is measured.
+var SnowflakeEpoch = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewUUIDGenerator creates a UUID generator which reads from the given source.
+func NewUUIDGenerator(rand *Rand) *UUIDGenerator {
+	return &UUIDGenerator{rand: rand}
+}
+
+// NewULIDGenerator creates a ULID generator which reads the time from the given
+// clock and randomness from the given source.
+func NewULIDGenerator(clock glock.Clock, rand *Rand) *ULIDGenerator {
+	return &ULIDGenerator{clock: clock, rand: rand}
+}
+
+// NewSnowflakeGenerator creates a snowflake ID generator with the given node
+// number, which must be in the range [0, 1023].
+func NewSnowflakeGenerator(clock glock.Clock, node int) (*SnowflakeGenerator, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, ErrIllegalNode
+	}
+
+	return &SnowflakeGenerator{clock: clock, node: int64(node), last: -1}, nil
+}
+
+// NewID creates a UUID in its canonical hyphenated form.
+func (g *UUIDGenerator) NewID() string {
+	b := make([]byte, 16)
+	g.rand.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// NewID creates a ULID in its canonical 26-character form.
+func (g *ULIDGenerator) NewID() string {
+	b := make([]byte, 16)
+	g.rand.Read(b[6:])
+
+	ms := uint64(g.clock.Now().UnixNano() / int64(time.Millisecond))
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+
+	return encodeCrockford(b)
+}
+
+// NewID creates a snowflake ID in its decimal form.
+func (g *SnowflakeGenerator) NewID() string {
+	return fmt.Sprintf("%d", g.Next())
+}
+
+// Next creates a snowflake ID. If the sequence for the current millisecond is
+// exhausted, or if the clock moves backwards, the timestamp of the previous ID
+// is advanced instead of waiting for the clock.
+func (g *SnowflakeGenerator) Next() int64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := int64(g.clock.Now().Sub(SnowflakeEpoch) / time.Millisecond)
+
+	if now > g.last {
+		g.last = now
+		g.sequence = 0
+	} else if g.sequence < snowflakeMaxSeq {
+		g.sequence++
+	} else {
+		g.last++
+		g.sequence = 0
+	}
+
+	return g.last<<(snowflakeNodeBits+snowflakeSeqBits) | g.node<<snowflakeSeqBits | g.sequence
+}
+
+// encodeCrockford encodes 16 bytes as 26 characters of Crockford's base32. The
+// value is treated as a 130-bit number whose two leading bits are zero.
+func encodeCrockford(b []byte) string {
+	out := make([]byte, 26)
+
+	for i := 0; i < 26; i++ {
+		offset := 125 - 5*i
+
+		var value byte
+		for bit := 4; bit >= 0; bit-- {
+			value <<= 1
+
+			if pos := offset + bit; pos < 128 {
+				value |= (b[15-pos/8] >> uint(pos%8)) & 1
+			}
+		}
+
+		out[i] = crockfordAlphabet[value]
+	}
+
+	return string(out)
+}
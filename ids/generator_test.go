@@ -0,0 +1,110 @@
+package ids
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type GeneratorSuite struct{}
+
+func (s *GeneratorSuite) TestUUID(t sweet.T) {
+	var (
+		g1 = NewUUIDGenerator(NewRand(42))
+		g2 = NewUUIDGenerator(NewRand(42))
+	)
+
+	id := g1.NewID()
+	Expect(id).To(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+	Expect(g2.NewID()).To(Equal(id))
+	Expect(g1.NewID()).NotTo(Equal(id))
+}
+
+func (s *GeneratorSuite) TestULID(t sweet.T) {
+	var (
+		clock = glock.NewMockClock()
+		g1    = NewULIDGenerator(clock, NewRand(42))
+		g2    = NewULIDGenerator(clock, NewRand(42))
+	)
+
+	id := g1.NewID()
+	Expect(id).To(MatchRegexp(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`))
+	Expect(g2.NewID()).To(Equal(id))
+
+	ids := []string{id}
+	for i := 0; i < 10; i++ {
+		clock.Advance(time.Millisecond)
+		ids = append(ids, g1.NewID())
+	}
+
+	Expect(sort.StringsAreSorted(ids)).To(BeTrue())
+}
+
+func (s *GeneratorSuite) TestEncodeCrockford(t sweet.T) {
+	b := make([]byte, 16)
+	Expect(encodeCrockford(b)).To(Equal("00000000000000000000000000"))
+
+	b[15] = 0x21
+	Expect(encodeCrockford(b)).To(Equal("00000000000000000000000011"))
+
+	for i := range b {
+		b[i] = 0xff
+	}
+
+	Expect(encodeCrockford(b)).To(Equal("7ZZZZZZZZZZZZZZZZZZZZZZZZZ"))
+}
+
+func (s *GeneratorSuite) TestSnowflake(t sweet.T) {
+	clock := glock.NewMockClock()
+	g, err := NewSnowflakeGenerator(clock, 7)
+	Expect(err).To(BeNil())
+
+	id1 := g.Next()
+	id2 := g.Next()
+	clock.Advance(time.Millisecond)
+	id3 := g.Next()
+
+	Expect(id2).To(Equal(id1 + 1))
+	Expect(id3).To(BeNumerically(">", id2))
+	Expect(id3 >> snowflakeSeqBits & snowflakeMaxNode).To(Equal(int64(7)))
+	Expect(id3 & snowflakeMaxSeq).To(Equal(int64(0)))
+}
+
+func (s *GeneratorSuite) TestSnowflakeSequenceExhausted(t sweet.T) {
+	clock := glock.NewMockClock()
+	g, err := NewSnowflakeGenerator(clock, 0)
+	Expect(err).To(BeNil())
+
+	first := g.Next()
+	for i := 0; i < snowflakeMaxSeq; i++ {
+		g.Next()
+	}
+
+	// Borrows the next millisecond rather than repeating an ID
+	next := g.Next()
+	Expect(next >> (snowflakeNodeBits + snowflakeSeqBits)).To(Equal(first>>(snowflakeNodeBits+snowflakeSeqBits) + 1))
+	Expect(next & snowflakeMaxSeq).To(Equal(int64(0)))
+}
+
+func (s *GeneratorSuite) TestSnowflakeClockBackwards(t sweet.T) {
+	clock := glock.NewMockClock()
+	clock.Advance(time.Second)
+
+	g, err := NewSnowflakeGenerator(clock, 0)
+	Expect(err).To(BeNil())
+
+	id1 := g.Next()
+	clock.Advance(-time.Millisecond * 10)
+	Expect(g.Next()).To(BeNumerically(">", id1))
+}
+
+func (s *GeneratorSuite) TestSnowflakeIllegalNode(t sweet.T) {
+	_, err := NewSnowflakeGenerator(glock.NewMockClock(), 1024)
+	Expect(err).To(Equal(ErrIllegalNode))
+
+	_, err = NewSnowflakeGenerator(glock.NewMockClock(), -1)
+	Expect(err).To(Equal(ErrIllegalNode))
+}
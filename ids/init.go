@@ -0,0 +1,103 @@
+package ids
+
+import (
+	"errors"
+
+	"github.com/efritz/glock"
+
+	"github.com/efritz/nacelle"
+)
+
+// Initializer registers a random source and UUID, ULID, and snowflake ID
+// generators to the service container under the keys RandServiceKey,
+// UUIDServiceKey, ULIDServiceKey, and SnowflakeServiceKey. A service which
+// has already been registered to one of these keys (e.g. a generator with
+// a fixed seed registered by a test) is left in place, so that the other
+// services can be overridden for determinism.
+//
+// The random source is seeded by the ID_SEED config value, or by the current
+// time if it is zero. The node number of the snowflake generator is set by the
+// ID_NODE config value and should be unique among the instances of an application.
+type Initializer struct {
+	Container   *nacelle.ServiceContainer `service:"container"`
+	Logger      nacelle.Logger            `service:"logger"`
+	configToken interface{}
+	clock       glock.Clock
+}
+
+const (
+	RandServiceKey      = "rand"
+	UUIDServiceKey      = "uuid-generator"
+	ULIDServiceKey      = "ulid-generator"
+	SnowflakeServiceKey = "snowflake-generator"
+)
+
+var ErrBadConfig = errors.New("ID config not registered properly")
+
+// NewInitializer creates a new ID initializer.
+func NewInitializer(configs ...ConfigFunc) *Initializer {
+	return newInitializer(glock.NewRealClock(), configs...)
+}
+
+func newInitializer(clock glock.Clock, configs ...ConfigFunc) *Initializer {
+	options := getOptions(configs)
+
+	return &Initializer{
+		configToken: options.configToken,
+		clock:       clock,
+	}
+}
+
+func (i *Initializer) Init(config nacelle.Config) error {
+	idConfig := &Config{}
+	if err := config.Fetch(i.configToken, idConfig); err != nil {
+		return ErrBadConfig
+	}
+
+	seed := idConfig.IDSeed
+	if seed == 0 {
+		seed = i.clock.Now().UnixNano()
+	}
+
+	rand, err := nacelle.ResolveOptional[*Rand](i.Container, RandServiceKey)
+	if err != nil {
+		return err
+	}
+
+	if rand == nil {
+		rand = NewRand(seed)
+	}
+
+	snowflake, err := NewSnowflakeGenerator(i.clock, idConfig.IDNode)
+	if err != nil {
+		return err
+	}
+
+	services := []struct {
+		key     string
+		service interface{}
+	}{
+		{RandServiceKey, rand},
+		{UUIDServiceKey, NewUUIDGenerator(rand)},
+		{ULIDServiceKey, NewULIDGenerator(i.clock, rand)},
+		{SnowflakeServiceKey, snowflake},
+	}
+
+	for _, s := range services {
+		if i.has(s.key) {
+			i.Logger.Debug("Service `%s` is already registered, not replacing", s.key)
+			continue
+		}
+
+		if err := i.Container.Set(s.key, s.service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Initializer) has(key string) bool {
+	_, err := i.Container.Get(key)
+	return err == nil
+}
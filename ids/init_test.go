@@ -0,0 +1,68 @@
+package ids
+
+import (
+	"os"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+	"github.com/efritz/nacelle/log"
+)
+
+type InitializerSuite struct{}
+
+func (s *InitializerSuite) TestInit(t sweet.T) {
+	os.Setenv("ID_SEED", "42")
+	defer os.Unsetenv("ID_SEED")
+
+	container := nacelle.NewServiceContainer()
+	initializer := newInitializer(glock.NewMockClock())
+	initializer.Container = container
+	initializer.Logger = log.NewNilLogger()
+
+	Expect(initializer.Init(makeConfig(ConfigToken, &Config{}))).To(BeNil())
+
+	for _, key := range []string{RandServiceKey, UUIDServiceKey, ULIDServiceKey, SnowflakeServiceKey} {
+		_, err := container.Get(key)
+		Expect(err).To(BeNil())
+	}
+
+	// Seeded by config
+	generator, err := nacelle.Resolve[*UUIDGenerator](container, UUIDServiceKey)
+	Expect(err).To(BeNil())
+	Expect(generator.NewID()).To(Equal(NewUUIDGenerator(NewRand(42)).NewID()))
+}
+
+func (s *InitializerSuite) TestInitOverrides(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		rand      = NewRand(7)
+		uuids     = NewUUIDGenerator(NewRand(8))
+	)
+
+	container.Set(RandServiceKey, rand)
+	container.Set(UUIDServiceKey, uuids)
+
+	initializer := newInitializer(glock.NewMockClock())
+	initializer.Container = container
+	initializer.Logger = log.NewNilLogger()
+
+	Expect(initializer.Init(makeConfig(ConfigToken, &Config{}))).To(BeNil())
+	Expect(container.MustGet(RandServiceKey)).To(BeIdenticalTo(rand))
+	Expect(container.MustGet(UUIDServiceKey)).To(BeIdenticalTo(uuids))
+
+	// Generators which are not overridden share the overridden source
+	generator := container.MustGet(ULIDServiceKey).(*ULIDGenerator)
+	Expect(generator.rand).To(BeIdenticalTo(rand))
+}
+
+func (s *InitializerSuite) TestIllegalNode(t sweet.T) {
+	os.Setenv("ID_NODE", "2048")
+	defer os.Unsetenv("ID_NODE")
+
+	config := nacelle.NewEnvConfig("")
+	config.Register(ConfigToken, &Config{})
+	Expect(config.Load()).To(HaveLen(1))
+}
@@ -0,0 +1,33 @@
+package ids
+
+import (
+	"testing"
+
+	"github.com/aphistic/sweet"
+	"github.com/aphistic/sweet-junit"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+)
+
+func TestMain(m *testing.M) {
+	RegisterFailHandler(sweet.GomegaFail)
+
+	sweet.Run(m, func(s *sweet.S) {
+		s.RegisterPlugin(junit.NewPlugin())
+
+		s.AddSuite(&GeneratorSuite{})
+		s.AddSuite(&InitializerSuite{})
+	})
+}
+
+//
+// Config
+
+func makeConfig(token, base interface{}) nacelle.Config {
+	config := nacelle.NewEnvConfig("")
+	config.Register(token, base)
+	config.Load()
+
+	return config
+}
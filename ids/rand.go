@@ -0,0 +1,54 @@
+package ids
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Rand is a seedable source of pseudo-random values which is safe for
+// concurrent use. Two instances created with the same seed produce the
+// same sequence of values, which makes generated identifiers deterministic
+// in tests.
+type Rand struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+// NewRand creates a random source with the given seed.
+func NewRand(seed int64) *Rand {
+	return &Rand{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer.
+func (r *Rand) Int63() int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rand.Int63()
+}
+
+// Intn returns a non-negative pseudo-random integer in [0,n). It panics
+// if n is not positive.
+func (r *Rand) Intn(n int) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rand.Intn(n)
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0).
+func (r *Rand) Float64() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rand.Float64()
+}
+
+// Read fills the given slice with pseudo-random bytes. It always returns
+// the length of the slice and a nil error.
+func (r *Rand) Read(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rand.Read(p)
+}
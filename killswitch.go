@@ -0,0 +1,146 @@
+package nacelle
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// KillSwitches tracks the set of application features which have been disabled
+	// by an operator. Processes consult the kill switches before doing risky work
+	// (e.g. a consumer stops reading a topic, or a route responds with a 503) so
+	// that a feature can be disabled at runtime without redeploying. The bootstrapper
+	// registers an instance to the service key "killswitches", initially populated
+	// from the comma-separated KILL_SWITCHES config value.
+	KillSwitches struct {
+		mutex  sync.RWMutex
+		killed map[string]struct{}
+	}
+
+	// KillSwitchConfig is the config of the kill switches registered by the bootstrapper.
+	KillSwitchConfig struct {
+		RawKillSwitches string `env:"kill_switches"`
+
+		KillSwitches []string
+	}
+
+	killSwitchConfigToken string
+)
+
+var KillSwitchConfigToken = killSwitchConfigToken("nacelle-kill-switches")
+
+func (c *KillSwitchConfig) PostLoad() error {
+	c.KillSwitches = parseFeatures(c.RawKillSwitches)
+	return nil
+}
+
+// NewKillSwitches creates a set of kill switches in which the given features
+// are disabled.
+func NewKillSwitches(features ...string) *KillSwitches {
+	k := &KillSwitches{}
+	k.Set(features)
+	return k
+}
+
+// Enabled returns true if the kill switch of the given feature is not engaged.
+func (k *KillSwitches) Enabled(feature string) bool {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	_, ok := k.killed[feature]
+	return !ok
+}
+
+// Kill disables the given feature.
+func (k *KillSwitches) Kill(feature string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.killed[feature] = struct{}{}
+}
+
+// Restore re-enables the given feature.
+func (k *KillSwitches) Restore(feature string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	delete(k.killed, feature)
+}
+
+// Set replaces the set of disabled features.
+func (k *KillSwitches) Set(features []string) {
+	killed := map[string]struct{}{}
+	for _, feature := range features {
+		killed[feature] = struct{}{}
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.killed = killed
+}
+
+// Reload replaces the set of disabled features with the features listed in the
+// given reader, one per line or separated by commas. Blank lines and lines which
+// begin with # are ignored. This allows the kill switches to be hot-reloaded from
+// a file (e.g. by a process.FileWatcher).
+func (k *KillSwitches) Reload(r io.Reader) error {
+	features := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		features = append(features, parseFeatures(line)...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	k.Set(features)
+	return nil
+}
+
+// Killed returns the disabled features, sorted.
+func (k *KillSwitches) Killed() []string {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	features := []string{}
+	for feature := range k.killed {
+		features = append(features, feature)
+	}
+
+	sort.Strings(features)
+	return features
+}
+
+// Fields returns the state of the kill switches as log fields, suitable for
+// reporting to a metrics backend. Each disabled feature is reported as the
+// field kill_switch.<feature> with the value 1.
+func (k *KillSwitches) Fields() Fields {
+	fields := Fields{}
+	for _, feature := range k.Killed() {
+		fields["kill_switch."+feature] = 1
+	}
+
+	return fields
+}
+
+func parseFeatures(value string) []string {
+	features := []string{}
+	for _, feature := range strings.Split(value, ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			features = append(features, feature)
+		}
+	}
+
+	return features
+}
@@ -0,0 +1,41 @@
+package nacelle
+
+import (
+	"strings"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type KillSwitchSuite struct{}
+
+func (s *KillSwitchSuite) TestKillAndRestore(t sweet.T) {
+	switches := NewKillSwitches("checkout")
+	Expect(switches.Enabled("checkout")).To(BeFalse())
+	Expect(switches.Enabled("search")).To(BeTrue())
+
+	switches.Kill("search")
+	Expect(switches.Enabled("search")).To(BeFalse())
+	Expect(switches.Killed()).To(Equal([]string{"checkout", "search"}))
+
+	switches.Restore("checkout")
+	Expect(switches.Enabled("checkout")).To(BeTrue())
+	Expect(switches.Killed()).To(Equal([]string{"search"}))
+	Expect(switches.Fields()).To(Equal(Fields{"kill_switch.search": 1}))
+}
+
+func (s *KillSwitchSuite) TestReload(t sweet.T) {
+	switches := NewKillSwitches("checkout")
+
+	Expect(switches.Reload(strings.NewReader("# disabled during the incident\nsearch, recommendations\n\nexport\n"))).To(BeNil())
+	Expect(switches.Killed()).To(Equal([]string{"export", "recommendations", "search"}))
+
+	Expect(switches.Reload(strings.NewReader(""))).To(BeNil())
+	Expect(switches.Killed()).To(BeEmpty())
+}
+
+func (s *KillSwitchSuite) TestConfig(t sweet.T) {
+	c := &KillSwitchConfig{RawKillSwitches: "search, ,checkout"}
+	Expect(c.PostLoad()).To(BeNil())
+	Expect(c.KillSwitches).To(Equal([]string{"search", "checkout"}))
+}
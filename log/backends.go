@@ -0,0 +1,60 @@
+package log
+
+import (
+	"io"
+	"os"
+)
+
+type initFunc func(*Config, io.Writer) (Logger, error)
+
+var initFuncs = map[string]initFunc{
+	"gomol":  initGomolShim,
+	"logrus": initLogrusShim,
+	"zap":    initZapShim,
+}
+
+// InitBackends creates a logger which writes to each of the backends declared
+// in the LogBackends list of the given config. Each backend is configured by
+// its own block, falling back to the top-level settings (see BackendConfig).
+func InitBackends(c *Config) (Logger, error) {
+	loggers := []Logger{}
+	for i := range c.LogBackends {
+		backend := c.backend(i)
+
+		w, err := openDestination(backend)
+		if err != nil {
+			return nil, err
+		}
+
+		logger, err := initFuncs[backend.Backend](&Config{
+			LogBackend:       backend.Backend,
+			LogLevel:         backend.Level,
+			LogEncoding:      backend.Encoding,
+			LogColorize:      *backend.Colorize,
+			LogInitialFields: c.LogInitialFields,
+		}, w)
+
+		if err != nil {
+			return nil, err
+		}
+
+		loggers = append(loggers, logger)
+	}
+
+	if len(loggers) == 1 {
+		return loggers[0], nil
+	}
+
+	return NewTeeLogger(loggers...), nil
+}
+
+func openDestination(backend BackendConfig) (io.Writer, error) {
+	switch backend.Destination {
+	case "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	default:
+		return openRotatingFile(backend.Destination, backend.MaxSize, backend.MaxBackups)
+	}
+}
@@ -0,0 +1,32 @@
+package log
+
+import stdlog "log"
+
+// NewStdLogger creates a logger from the standard library log package which logs
+// each line written to it with the given logger at the given level.
+func NewStdLogger(logger Logger, level LogLevel) *stdlog.Logger {
+	return stdlog.New(newLineWriter(logger, level, Fields{}), "", 0)
+}
+
+// RedirectStdLog routes the output of the standard library log package's default
+// logger (e.g. log.Printf) through the given logger at the given level, so that
+// dependencies which log through the standard library are written in the same
+// format as the rest of the application. The returned function restores the
+// previous output and flags of the default logger.
+func RedirectStdLog(logger Logger, level LogLevel) func() {
+	var (
+		flags  = stdlog.Flags()
+		prefix = stdlog.Prefix()
+		output = stdlog.Writer()
+	)
+
+	stdlog.SetFlags(0)
+	stdlog.SetPrefix("")
+	stdlog.SetOutput(newLineWriter(logger, level, Fields{}))
+
+	return func() {
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+		stdlog.SetOutput(output)
+	}
+}
@@ -0,0 +1,120 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	stdlog "log"
+	"log/slog"
+)
+
+type slogHandler struct {
+	logger Logger
+	fields Fields
+	prefix string
+}
+
+// NewSlogHandler creates a handler for the log/slog package which writes each
+// record with the given logger. Levels below info are logged at the debug level,
+// levels below warn at the info level, levels below error at the warning level,
+// and all others at the error level. The attributes of a record are logged as
+// fields, where the attributes of a group are named by the group name and the
+// attribute key separated by a dot.
+func NewSlogHandler(logger Logger) slog.Handler {
+	return &slogHandler{logger: logger, fields: Fields{}}
+}
+
+// RedirectSlog sets the default logger of the log/slog package to one which writes
+// with the given logger. As the log/slog package also routes the standard library
+// log package's default logger through the default handler, this also redirects
+// calls such as log.Printf (at the info level). The returned function restores the
+// previous default logger.
+func RedirectSlog(logger Logger) func() {
+	var (
+		previous = slog.Default()
+		flags    = stdlog.Flags()
+		output   = stdlog.Writer()
+	)
+
+	slog.SetDefault(slog.New(NewSlogHandler(logger)))
+
+	return func() {
+		slog.SetDefault(previous)
+
+		// Restoring the default handler does not restore the output of the
+		// standard library log package, which would otherwise still write
+		// to the replaced handler
+		stdlog.SetFlags(flags)
+		stdlog.SetOutput(output)
+	}
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	// Filtering is performed by the logger
+	return true
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := h.fields.clone()
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	h.logger.LogWithFields(slogLevel(record.Level), fields, "%s", record.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := h.fields.clone()
+	for _, attr := range attrs {
+		addAttr(fields, h.prefix, attr)
+	}
+
+	return &slogHandler{logger: h.logger, fields: fields, prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &slogHandler{logger: h.logger, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+func addAttr(fields Fields, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		// A group with an empty key is inlined
+		if attr.Key != "" {
+			prefix = prefix + attr.Key + "."
+		}
+
+		for _, attr := range value.Group() {
+			addAttr(fields, prefix, attr)
+		}
+
+		return
+	}
+
+	if attr.Key == "" {
+		return
+	}
+
+	fields[prefix+attr.Key] = value.Any()
+}
+
+func slogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarning
+	default:
+		return LevelError
+	}
+}
@@ -0,0 +1,82 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	stdlog "log"
+	"log/slog"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+func (s *BridgeSuite) TestSlogHandler(t sweet.T) {
+	var (
+		shim   = &testShim{}
+		logger = slog.New(NewSlogHandler(adaptShim(shim)))
+	)
+
+	logger.
+		With("a", 1).
+		WithGroup("req").
+		With("id", "x").
+		Warn("foo", "b", true, slog.Group("user", "name", "bar"))
+
+	Expect(shim.messages).To(HaveLen(1))
+	Expect(shim.messages[0].level).To(Equal(LevelWarning))
+	Expect(shim.messages[0].format).To(Equal("%s"))
+	Expect(shim.messages[0].args).To(Equal([]interface{}{"foo"}))
+
+	fields := shim.messages[0].fields
+	Expect(fields["a"]).To(Equal(int64(1)))
+	Expect(fields["req.id"]).To(Equal("x"))
+	Expect(fields["req.b"]).To(Equal(true))
+	Expect(fields["req.user.name"]).To(Equal("bar"))
+}
+
+func (s *BridgeSuite) TestSlogLevels(t sweet.T) {
+	var (
+		shim   = &testShim{}
+		logger = slog.New(NewSlogHandler(adaptShim(shim)))
+	)
+
+	logger.Log(context.Background(), slog.LevelDebug-4, "a")
+	logger.Debug("b")
+	logger.Info("c")
+	logger.Warn("d")
+	logger.Error("e")
+	logger.Log(context.Background(), slog.LevelError+4, "f")
+
+	levels := []LogLevel{}
+	for _, message := range shim.messages {
+		levels = append(levels, message.level)
+	}
+
+	Expect(levels).To(Equal([]LogLevel{
+		LevelDebug,
+		LevelDebug,
+		LevelInfo,
+		LevelWarning,
+		LevelError,
+		LevelError,
+	}))
+}
+
+func (s *BridgeSuite) TestRedirectSlog(t sweet.T) {
+	var (
+		shim     = &testShim{}
+		previous = slog.Default()
+		restore  = RedirectSlog(adaptShim(shim))
+	)
+
+	slog.Info("foo")
+	stdlog.Print("bar")
+	restore()
+
+	Expect(slog.Default()).To(BeIdenticalTo(previous))
+	Expect(shim.messages).To(HaveLen(2))
+	Expect(shim.messages[0].args).To(Equal([]interface{}{"foo"}))
+	Expect(shim.messages[1].args).To(Equal([]interface{}{"bar"}))
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	stdlog "log"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type BridgeSuite struct{}
+
+func (s *BridgeSuite) TestStdLogger(t sweet.T) {
+	shim := &testShim{}
+	NewStdLogger(adaptShim(shim), LevelWarning).Printf("foo %d", 42)
+
+	Expect(shim.messages).To(HaveLen(1))
+	Expect(shim.messages[0].level).To(Equal(LevelWarning))
+	Expect(shim.messages[0].args).To(Equal([]interface{}{"foo 42"}))
+}
+
+func (s *BridgeSuite) TestRedirectStdLog(t sweet.T) {
+	shim := &testShim{}
+	restore := RedirectStdLog(adaptShim(shim), LevelInfo)
+	stdlog.Print("foo")
+	restore()
+
+	Expect(shim.messages).To(HaveLen(1))
+	Expect(shim.messages[0].level).To(Equal(LevelInfo))
+	Expect(shim.messages[0].args).To(Equal([]interface{}{"foo"}))
+}
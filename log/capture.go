@@ -0,0 +1,158 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+type (
+	// StreamCapture redirects writes to the process's standard out and standard
+	// error streams through a logger (see CaptureStreams).
+	StreamCapture struct {
+		stdout  *os.File
+		stderr  *os.File
+		writers []*os.File
+		wg      sync.WaitGroup
+		once    sync.Once
+	}
+
+	lineWriter struct {
+		logger Logger
+		level  LogLevel
+		fields Fields
+		mutex  sync.Mutex
+		buffer bytes.Buffer
+	}
+)
+
+// FieldStream is a field assigned to each message written to a captured stream.
+// Its value is the name of the stream (stdout or stderr).
+const FieldStream = "stream"
+
+// NewStreamWriter creates a writer which logs each line written to it with the
+// given logger at the given level. Each message has the field FieldStream set
+// to the given stream name. A partial line is buffered until it is completed
+// or the writer is closed.
+func NewStreamWriter(logger Logger, level LogLevel, stream string) io.WriteCloser {
+	return newLineWriter(logger, level, Fields{FieldStream: stream})
+}
+
+func newLineWriter(logger Logger, level LogLevel, fields Fields) *lineWriter {
+	return &lineWriter{
+		logger: logger,
+		level:  level,
+		fields: fields,
+	}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer.Write(p)
+
+	for {
+		i := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		w.log(string(w.buffer.Next(i + 1)))
+	}
+
+	return len(p), nil
+}
+
+func (w *lineWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.buffer.Len() > 0 {
+		w.log(w.buffer.String())
+		w.buffer.Reset()
+	}
+
+	return nil
+}
+
+func (w *lineWriter) log(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return
+	}
+
+	w.logger.LogWithFields(w.level, w.fields.clone(), "%s", line)
+}
+
+// CaptureStreams replaces os.Stdout and os.Stderr with pipes whose contents are
+// logged line-by-line with the given logger, so that output printed directly by
+// third-party libraries is written in the same structured format as the rest of
+// the application. Lines written to standard out are logged at the info level
+// and lines written to standard error at the warning level. Writes made directly
+// to the underlying file descriptors (e.g. by the Go runtime or by C code) are not
+// captured. The logger must have been created before calling this function so
+// that it writes to the original streams. The original streams are restored by
+// calling Close on the returned value.
+func CaptureStreams(logger Logger) (*StreamCapture, error) {
+	c := &StreamCapture{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	stdout, err := c.capture("stdout", LevelInfo, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := c.capture("stderr", LevelWarning, logger)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	os.Stdout = stdout
+	os.Stderr = stderr
+	return c, nil
+}
+
+func (c *StreamCapture) capture(stream string, level LogLevel, logger Logger) (*os.File, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	c.writers = append(c.writers, writer)
+	c.wg.Add(1)
+
+	go func() {
+		defer c.wg.Done()
+		defer reader.Close()
+
+		lineWriter := NewStreamWriter(logger, level, stream)
+		defer lineWriter.Close()
+
+		io.Copy(lineWriter, reader)
+	}()
+
+	return writer, nil
+}
+
+// Close restores the original standard out and standard error streams and blocks
+// until all captured output has been logged.
+func (c *StreamCapture) Close() error {
+	c.once.Do(func() {
+		os.Stdout = c.stdout
+		os.Stderr = c.stderr
+
+		for _, writer := range c.writers {
+			writer.Close()
+		}
+
+		c.wg.Wait()
+	})
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type CaptureSuite struct{}
+
+func (s *CaptureSuite) TestStreamWriter(t sweet.T) {
+	var (
+		shim   = &testShim{}
+		writer = NewStreamWriter(adaptShim(shim), LevelWarning, "stderr")
+	)
+
+	writer.Write([]byte("foo\nba"))
+	writer.Write([]byte("r\n\nbaz"))
+	Expect(shim.messages).To(HaveLen(2))
+
+	Expect(writer.Close()).To(BeNil())
+	Expect(shim.messages).To(HaveLen(3))
+
+	for i, line := range []string{"foo", "bar", "baz"} {
+		Expect(shim.messages[i].level).To(Equal(LevelWarning))
+		Expect(shim.messages[i].format).To(Equal("%s"))
+		Expect(shim.messages[i].args).To(Equal([]interface{}{line}))
+		Expect(shim.messages[i].fields[FieldStream]).To(Equal("stderr"))
+	}
+}
+
+func (s *CaptureSuite) TestCaptureStreams(t sweet.T) {
+	var (
+		shim   = &testShim{}
+		stdout = os.Stdout
+		stderr = os.Stderr
+	)
+
+	// Streams are logged from separate goroutines
+	capture, err := CaptureStreams(adaptShim(&lockedShim{logShim: shim}))
+	Expect(err).To(BeNil())
+	Expect(os.Stdout).NotTo(BeIdenticalTo(stdout))
+	Expect(os.Stderr).NotTo(BeIdenticalTo(stderr))
+
+	fmt.Fprintln(os.Stdout, "foo")
+	fmt.Fprint(os.Stderr, "bar")
+	Expect(capture.Close()).To(BeNil())
+	Expect(os.Stdout).To(BeIdenticalTo(stdout))
+	Expect(os.Stderr).To(BeIdenticalTo(stderr))
+
+	Expect(shim.messages).To(HaveLen(2))
+
+	levels := map[string]LogLevel{}
+	for _, message := range shim.messages {
+		levels[message.args[0].(string)] = message.level
+	}
+
+	Expect(levels).To(Equal(map[string]LogLevel{
+		"foo": LevelInfo,
+		"bar": LevelWarning,
+	}))
+}
+
+type lockedShim struct {
+	logShim
+	mutex sync.Mutex
+}
+
+func (s *lockedShim) LogWithFields(level LogLevel, fields Fields, format string, args ...interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.logShim.LogWithFields(level, fields, format, args...)
+}
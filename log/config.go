@@ -5,18 +5,61 @@ import (
 	"strings"
 )
 
-type Config struct {
-	LogBackend       string `env:"LOG_BACKEND" default:"gomol"`
-	LogLevel         string `env:"LOG_LEVEL" default:"info"`
-	LogEncoding      string `env:"LOG_ENCODING" default:"console"`
-	LogColorize      bool   `env:"LOG_COLORIZE" default:"true"`
-	LogInitialFields Fields `env:"LOG_FIELDS"`
-}
+type (
+	Config struct {
+		LogBackend       string          `env:"LOG_BACKEND" default:"gomol"`
+		LogLevel         string          `env:"LOG_LEVEL" default:"info"`
+		LogEncoding      string          `env:"LOG_ENCODING" default:"console"`
+		LogColorize      bool            `env:"LOG_COLORIZE" default:"true"`
+		LogInitialFields Fields          `env:"LOG_FIELDS"`
+		LogBackends      []BackendConfig `env:"LOG_BACKENDS"`
+
+		// LogMaxFieldSize and LogMaxEntrySize bound the size in bytes of each
+		// field value and of each message along with its fields. Oversized
+		// values are truncated (see NewLimitAdapter). Zero disables a limit.
+		LogMaxFieldSize int `env:"LOG_MAX_FIELD_SIZE"`
+		LogMaxEntrySize int `env:"LOG_MAX_ENTRY_SIZE"`
+
+		// LogCaptureStreams redirects output written to standard out and standard
+		// error by the application (e.g. by third-party libraries which print
+		// directly) through the logger (see CaptureStreams).
+		LogCaptureStreams bool `env:"LOG_CAPTURE_STREAMS"`
+
+		// LogRedirectStdlib routes messages logged through the standard library
+		// log and log/slog packages through the logger (see RedirectSlog). The
+		// log/slog package is only redirected when built with Go 1.21 or later.
+		LogRedirectStdlib bool `env:"LOG_REDIRECT_STDLIB"`
+	}
+
+	// BackendConfig declares one of several log backends to which messages are
+	// written (see InitBackends). Backends are given as a JSON list in the
+	// LOG_BACKENDS envvar, e.g. [{"backend": "zap", "encoding": "json",
+	// "destination": "/var/log/app.log", "max_size": 100}]. An omitted backend,
+	// level, encoding, or colorize setting inherits the top-level setting.
+	BackendConfig struct {
+		Backend  string `json:"backend"`
+		Level    string `json:"level"`
+		Encoding string `json:"encoding"`
+		Colorize *bool  `json:"colorize"`
+
+		// Destination is stderr (the default), stdout, or the path of a file.
+		Destination string `json:"destination"`
+
+		// MaxSize is the size in megabytes at which a file destination is
+		// rotated. MaxBackups is the number of rotated files which are kept.
+		// A MaxSize of zero disables rotation.
+		MaxSize    int `json:"max_size"`
+		MaxBackups int `json:"max_backups"`
+	}
+)
 
 var (
 	ErrIllegalBackend  = errors.New("illegal log backend")
 	ErrIllegalLevel    = errors.New("illegal log level")
 	ErrIllegalEncoding = errors.New("illegal log encoding")
+	ErrIllegalRotation = errors.New("illegal log rotation")
+	ErrMultipleGomol   = errors.New("gomol backend may only be declared once")
+	ErrIllegalLimit    = errors.New("illegal log size limit")
 )
 
 func (c *Config) PostLoad() error {
@@ -34,9 +77,74 @@ func (c *Config) PostLoad() error {
 		return ErrIllegalEncoding
 	}
 
+	if c.LogMaxFieldSize < 0 || c.LogMaxEntrySize < 0 {
+		return ErrIllegalLimit
+	}
+
+	gomolBackends := 0
+	for i := range c.LogBackends {
+		backend := c.backend(i)
+
+		if !isLegalBackend(backend.Backend) {
+			return ErrIllegalBackend
+		}
+
+		if !isLegalLevel(backend.Level) {
+			return ErrIllegalLevel
+		}
+
+		if !isLegalEncoding(backend.Encoding) {
+			return ErrIllegalEncoding
+		}
+
+		if backend.MaxSize < 0 || backend.MaxBackups < 0 {
+			return ErrIllegalRotation
+		}
+
+		if backend.Backend == "gomol" {
+			gomolBackends++
+		}
+	}
+
+	// Gomol is configured globally, so its level and outputs can't vary
+	// between blocks.
+	if gomolBackends > 1 {
+		return ErrMultipleGomol
+	}
+
 	return nil
 }
 
+// backend returns the backend block at the given index with omitted settings
+// inherited from the top-level config.
+func (c *Config) backend(i int) BackendConfig {
+	backend := c.LogBackends[i]
+
+	if backend.Backend == "" {
+		backend.Backend = c.LogBackend
+	}
+
+	if backend.Level == "" {
+		backend.Level = c.LogLevel
+	}
+
+	if backend.Encoding == "" {
+		backend.Encoding = c.LogEncoding
+	}
+
+	if backend.Colorize == nil {
+		colorize := c.LogColorize
+		backend.Colorize = &colorize
+	}
+
+	if backend.Destination == "" {
+		backend.Destination = "stderr"
+	}
+
+	backend.Level = strings.ToLower(backend.Level)
+	return backend
+}
+
 func isLegalBackend(backend string) bool {
 	for _, whitelisted := range []string{"gomol", "logrus", "zap"} {
 		if backend == whitelisted {
@@ -32,3 +32,50 @@ func (s *ConfigSuite) TestIsLegalEncoding(t sweet.T) {
 	Expect(isLegalEncoding("file")).To(BeFalse())
 	Expect(isLegalEncoding("yaml")).To(BeFalse())
 }
+
+func (s *ConfigSuite) TestBackendDefaults(t sweet.T) {
+	colorize := false
+	c := &Config{
+		LogBackend:  "gomol",
+		LogLevel:    "info",
+		LogEncoding: "console",
+		LogColorize: true,
+		LogBackends: []BackendConfig{
+			{},
+			{Backend: "zap", Level: "DEBUG", Encoding: "json", Colorize: &colorize, Destination: "app.log"},
+		},
+	}
+
+	Expect(c.PostLoad()).To(BeNil())
+
+	first := c.backend(0)
+	Expect(first.Backend).To(Equal("gomol"))
+	Expect(first.Level).To(Equal("info"))
+	Expect(first.Encoding).To(Equal("console"))
+	Expect(*first.Colorize).To(BeTrue())
+	Expect(first.Destination).To(Equal("stderr"))
+
+	second := c.backend(1)
+	Expect(second.Backend).To(Equal("zap"))
+	Expect(second.Level).To(Equal("debug"))
+	Expect(second.Encoding).To(Equal("json"))
+	Expect(*second.Colorize).To(BeFalse())
+	Expect(second.Destination).To(Equal("app.log"))
+}
+
+func (s *ConfigSuite) TestIllegalBackends(t sweet.T) {
+	makeConfig := func(backends ...BackendConfig) *Config {
+		return &Config{
+			LogBackend:  "logrus",
+			LogLevel:    "info",
+			LogEncoding: "json",
+			LogBackends: backends,
+		}
+	}
+
+	Expect(makeConfig(BackendConfig{Backend: "paz"}).PostLoad()).To(Equal(ErrIllegalBackend))
+	Expect(makeConfig(BackendConfig{Level: "trace"}).PostLoad()).To(Equal(ErrIllegalLevel))
+	Expect(makeConfig(BackendConfig{Encoding: "yaml"}).PostLoad()).To(Equal(ErrIllegalEncoding))
+	Expect(makeConfig(BackendConfig{MaxSize: -1}).PostLoad()).To(Equal(ErrIllegalRotation))
+	Expect(makeConfig(BackendConfig{Backend: "gomol"}, BackendConfig{Backend: "gomol"}).PostLoad()).To(Equal(ErrMultipleGomol))
+}
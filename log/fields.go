@@ -13,6 +13,15 @@ func (f Fields) clone() Fields {
 	return clone
 }
 
+func (f Fields) concat(fields Fields) Fields {
+	concat := f.clone()
+	for k, v := range fields {
+		concat[k] = v
+	}
+
+	return concat
+}
+
 func (f Fields) normalizeTimeValues() Fields {
 	for key, val := range f {
 		switch v := val.(type) {
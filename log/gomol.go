@@ -1,6 +1,7 @@
 package log
 
 import (
+	"io"
 	"os"
 	"strings"
 
@@ -52,13 +53,17 @@ func (g *GomolShim) Sync() error {
 // Init
 
 func InitGomolShim(c *Config) (Logger, error) {
+	return initGomolShim(c, os.Stderr)
+}
+
+func initGomolShim(c *Config, w io.Writer) (Logger, error) {
 	level, _ := gomol.ToLogLevel(c.LogLevel)
 	gomol.SetLogLevel(level)
 
 	if c.LogEncoding == "console" {
 		consoleLogger, err := console.NewConsoleLogger(&console.ConsoleLoggerConfig{
 			Colorize: true,
-			Writer:   os.Stderr,
+			Writer:   w,
 		})
 
 		if err != nil {
@@ -73,7 +78,9 @@ func InitGomolShim(c *Config) (Logger, error) {
 		consoleLogger.SetTemplate(tpl)
 		gomol.AddLogger(consoleLogger)
 	} else {
-		gomol.AddLogger(newJSONLogger())
+		jsonLogger := newJSONLogger()
+		jsonLogger.stream = w
+		gomol.AddLogger(jsonLogger)
 	}
 
 	if err := gomol.InitLoggers(); err != nil {
@@ -0,0 +1,193 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+type limitShim struct {
+	logger       Logger
+	maxFieldSize int
+	maxEntrySize int
+	fieldsSize   int
+}
+
+// truncationReserve is the number of bytes reserved for the truncation
+// marker when shrinking a value to fit within the entry size limit.
+const truncationReserve = 32
+
+//
+// Shim
+
+var _ logShim = &limitShim{}
+
+// NewLimitAdapter returns a logger which bounds the size of the messages logged
+// with the wrapped logger, so that a handler which logs an entire request body
+// can't exhaust a downstream ingestion quota. A field value larger than the max
+// field size is truncated. If the message along with its fields is larger than
+// the max entry size, the largest values are truncated until it fits. Truncated
+// values end with a marker noting the number of bytes removed. Values which are
+// not strings are formatted before they are truncated. A limit of zero disables
+// that limit.
+func NewLimitAdapter(logger Logger, maxFieldSize, maxEntrySize int) Logger {
+	return adaptShim(newLimitShim(logger, maxFieldSize, maxEntrySize, 0))
+}
+
+func newLimitShim(logger Logger, maxFieldSize, maxEntrySize, fieldsSize int) *limitShim {
+	return &limitShim{
+		logger:       logger,
+		maxFieldSize: maxFieldSize,
+		maxEntrySize: maxEntrySize,
+		fieldsSize:   fieldsSize,
+	}
+}
+
+func (s *limitShim) WithFields(fields Fields) logShim {
+	if len(fields) == 0 {
+		return s
+	}
+
+	fields = s.limitFields(fields)
+
+	return newLimitShim(
+		s.logger.WithFields(fields),
+		s.maxFieldSize,
+		s.maxEntrySize,
+		s.fieldsSize+entrySize(fields),
+	)
+}
+
+func (s *limitShim) LogWithFields(level LogLevel, fields Fields, format string, args ...interface{}) {
+	fields = s.limitFields(addCaller(fields))
+	message := fmt.Sprintf(format, args...)
+
+	if s.maxEntrySize > 0 {
+		message = s.limitEntry(fields, message)
+	}
+
+	s.logger.LogWithFields(level, fields, "%s", message)
+}
+
+func (s *limitShim) Sync() error {
+	return s.logger.Sync()
+}
+
+// limitFields returns a copy of the given fields with each value larger than
+// the max field size truncated.
+func (s *limitShim) limitFields(fields Fields) Fields {
+	limited := fields.clone()
+	if s.maxFieldSize <= 0 {
+		return limited
+	}
+
+	for key, value := range limited {
+		if serialized := serializeValue(value); len(serialized) > s.maxFieldSize {
+			limited[key] = truncate(serialized, s.maxFieldSize)
+		}
+	}
+
+	return limited
+}
+
+// limitEntry truncates the largest field values (in place) and the message,
+// largest first, until the entry fits within the max entry size. The possibly
+// truncated message is returned.
+func (s *limitShim) limitEntry(fields Fields, message string) string {
+	excess := s.fieldsSize + entrySize(fields) + len(message) - s.maxEntrySize
+	if excess <= 0 {
+		return message
+	}
+
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sizes := map[string]int{}
+	for _, key := range keys {
+		sizes[key] = len(serializeValue(fields[key]))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if sizes[keys[i]] == sizes[keys[j]] {
+			return keys[i] < keys[j]
+		}
+
+		return sizes[keys[i]] > sizes[keys[j]]
+	})
+
+	shrink := func(value string) string {
+		truncated := truncate(value, len(value)-excess-truncationReserve)
+		if len(truncated) >= len(value) {
+			return value
+		}
+
+		excess -= len(value) - len(truncated)
+		return truncated
+	}
+
+	// The message is shrunk in turn with the field values, once it is the
+	// largest value which has not yet been shrunk
+	messageShrunk := false
+	for _, key := range keys {
+		if !messageShrunk && excess > 0 && len(message) >= sizes[key] {
+			message = shrink(message)
+			messageShrunk = true
+		}
+
+		if excess <= 0 {
+			break
+		}
+
+		serialized := serializeValue(fields[key])
+		if truncated := shrink(serialized); truncated != serialized {
+			fields[key] = truncated
+		}
+	}
+
+	if !messageShrunk && excess > 0 {
+		message = shrink(message)
+	}
+
+	return message
+}
+
+// truncate returns the prefix of the value of at most the given number of bytes
+// followed by a marker noting the number of bytes removed. The prefix does not
+// split a multi-byte character. The value is returned unchanged if it fits.
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+
+	if limit < 0 {
+		limit = 0
+	}
+
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+
+	return fmt.Sprintf("%s...[truncated %d bytes]", value[:limit], len(value)-limit)
+}
+
+func serializeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func entrySize(fields Fields) int {
+	size := 0
+	for key, value := range fields {
+		size += len(key) + len(serializeValue(value))
+	}
+
+	return size
+}
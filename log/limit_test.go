@@ -0,0 +1,104 @@
+package log
+
+import (
+	"strings"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type LimitSuite struct{}
+
+func (s *LimitSuite) TestFieldLimit(t sweet.T) {
+	var (
+		shim    = &testShim{}
+		adapter = newLimitShim(adaptShim(shim), 10, 0, 0)
+	)
+
+	adapter.LogWithFields(LevelInfo, Fields{
+		"short": "abc",
+		"body":  strings.Repeat("x", 25),
+		"bytes": []byte(strings.Repeat("y", 12)),
+		"count": 12345678901234,
+	}, "request %d", 1)
+
+	Expect(shim.messages).To(HaveLen(1))
+	Expect(shim.messages[0].format).To(Equal("%s"))
+	Expect(shim.messages[0].args).To(Equal([]interface{}{"request 1"}))
+
+	fields := shim.messages[0].fields
+	Expect(fields["short"]).To(Equal("abc"))
+	Expect(fields["body"]).To(Equal("xxxxxxxxxx...[truncated 15 bytes]"))
+	Expect(fields["bytes"]).To(Equal("yyyyyyyyyy...[truncated 2 bytes]"))
+	Expect(fields["count"]).To(Equal("1234567890...[truncated 4 bytes]"))
+}
+
+func (s *LimitSuite) TestFieldLimitDoesNotMutate(t sweet.T) {
+	var (
+		shim    = &testShim{}
+		adapter = newLimitShim(adaptShim(shim), 3, 0, 0)
+		fields  = Fields{"body": "abcdef"}
+	)
+
+	adapter.LogWithFields(LevelInfo, fields, "a")
+	Expect(fields["body"]).To(Equal("abcdef"))
+}
+
+func (s *LimitSuite) TestEntryLimit(t sweet.T) {
+	var (
+		shim    = &testShim{}
+		adapter = newLimitShim(adaptShim(shim), 0, 200, 0)
+	)
+
+	adapter.LogWithFields(LevelInfo, Fields{
+		"caller": "handler.go:10",
+		"user":   "alice",
+		"body":   strings.Repeat("x", 500),
+	}, "handled request")
+
+	Expect(shim.messages).To(HaveLen(1))
+
+	fields := shim.messages[0].fields
+	Expect(fields["user"]).To(Equal("alice"))
+	Expect(fields["caller"]).To(Equal("handler.go:10"))
+	Expect(fields["body"]).To(HaveSuffix("...[truncated 379 bytes]"))
+	Expect(shim.messages[0].args).To(Equal([]interface{}{"handled request"}))
+	Expect(entrySize(fields) + len("handled request")).To(BeNumerically("<=", 200))
+}
+
+func (s *LimitSuite) TestEntryLimitMessage(t sweet.T) {
+	var (
+		shim    = &testShim{}
+		adapter = newLimitShim(adaptShim(shim), 0, 100, 0)
+	)
+
+	adapter.LogWithFields(LevelInfo, Fields{"caller": "handler.go:10"}, "body: %s", strings.Repeat("x", 500))
+	Expect(shim.messages).To(HaveLen(1))
+
+	message := shim.messages[0].args[0].(string)
+	Expect(message).To(HavePrefix("body: xxx"))
+	Expect(message).To(HaveSuffix("bytes]"))
+	Expect(len(message) + entrySize(shim.messages[0].fields)).To(BeNumerically("<=", 100))
+}
+
+func (s *LimitSuite) TestEntryLimitWithFields(t sweet.T) {
+	var (
+		shim    = &testShim{}
+		adapter = newLimitShim(adaptShim(shim), 0, 100, 0)
+	)
+
+	adapter.WithFields(Fields{"request": strings.Repeat("r", 60)}).LogWithFields(LevelInfo, Fields{
+		"caller": "handler.go:10",
+		"body":   strings.Repeat("x", 60),
+	}, "a")
+
+	Expect(shim.messages).To(HaveLen(1))
+	Expect(shim.messages[0].fields["body"]).To(HaveSuffix("bytes]"))
+}
+
+func (s *LimitSuite) TestTruncate(t sweet.T) {
+	Expect(truncate("abc", 3)).To(Equal("abc"))
+	Expect(truncate("abcdef", 3)).To(Equal("abc...[truncated 3 bytes]"))
+	Expect(truncate("abc", -1)).To(Equal("...[truncated 3 bytes]"))
+	Expect(truncate("héllo", 2)).To(Equal("h...[truncated 5 bytes]"))
+}
@@ -1,6 +1,9 @@
 package log
 
 import (
+	"io"
+	"os"
+
 	"github.com/sirupsen/logrus"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 )
@@ -42,6 +45,10 @@ func (l *LogrusShim) LogWithFields(level LogLevel, fields Fields, format string,
 }
 
 func (l *LogrusShim) Sync() error {
+	if file, ok := l.entry.Logger.Out.(*rotatingFile); ok {
+		return file.Sync()
+	}
+
 	return nil
 }
 
@@ -57,12 +64,17 @@ func (l *LogrusShim) getEntry(fields Fields) *logrus.Entry {
 // Init
 
 func InitLogrusShim(c *Config) (Logger, error) {
+	return initLogrusShim(c, os.Stderr)
+}
+
+func initLogrusShim(c *Config, w io.Writer) (Logger, error) {
 	level, err := logrus.ParseLevel(c.LogLevel)
 	if err != nil {
 		return nil, err
 	}
 
 	logger := logrus.New()
+	logger.Out = w
 	logger.Level = level
 
 	if c.LogEncoding == "console" {
@@ -19,11 +19,17 @@ func TestMain(m *testing.M) {
 		s.RegisterPlugin(junit.NewPlugin())
 
 		s.AddSuite(&LoggerSuite{})
+		s.AddSuite(&LimitSuite{})
+		s.AddSuite(&BridgeSuite{})
 		s.AddSuite(&CallerSuite{})
+		s.AddSuite(&CaptureSuite{})
 		s.AddSuite(&ConfigSuite{})
 		s.AddSuite(&GomolJSONSuite{})
+		s.AddSuite(&RecordingSuite{})
 		s.AddSuite(&ReplaySuite{})
 		s.AddSuite(&RollupSuite{})
+		s.AddSuite(&RotatingFileSuite{})
+		s.AddSuite(&TeeSuite{})
 	})
 }
 
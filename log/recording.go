@@ -0,0 +1,67 @@
+package log
+
+import (
+	"fmt"
+)
+
+type (
+	// RecordFunc receives each message logged through a recording adapter at or
+	// above the adapter's level. The message is formatted and the fields include
+	// those attached to the adapter via WithFields.
+	RecordFunc func(level LogLevel, fields Fields, message string)
+
+	recordingShim struct {
+		logger Logger
+		level  LogLevel
+		fields Fields
+		record RecordFunc
+	}
+)
+
+//
+// Shim
+
+var _ logShim = &recordingShim{}
+
+// NewRecordingAdapter returns a logger which passes each message logged at the
+// given level or a more severe level to the given function before logging it
+// with the wrapped logger.
+func NewRecordingAdapter(logger Logger, level LogLevel, record RecordFunc) Logger {
+	return adaptShim(newRecordingShim(logger, level, nil, record))
+}
+
+func newRecordingShim(logger Logger, level LogLevel, fields Fields, record RecordFunc) *recordingShim {
+	return &recordingShim{
+		logger: logger,
+		level:  level,
+		fields: fields,
+		record: record,
+	}
+}
+
+func (s *recordingShim) WithFields(fields Fields) logShim {
+	if len(fields) == 0 {
+		return s
+	}
+
+	return newRecordingShim(
+		s.logger.WithFields(fields),
+		s.level,
+		s.fields.concat(fields),
+		s.record,
+	)
+}
+
+func (s *recordingShim) LogWithFields(level LogLevel, fields Fields, format string, args ...interface{}) {
+	fields = addCaller(fields)
+
+	if level <= s.level {
+		s.record(level, s.fields.concat(fields), fmt.Sprintf(format, args...))
+	}
+
+	s.logger.LogWithFields(level, fields, format, args...)
+}
+
+func (s *recordingShim) Sync() error {
+	return s.logger.Sync()
+}
@@ -0,0 +1,39 @@
+package log
+
+import (
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type RecordingSuite struct{}
+
+func (s *RecordingSuite) TestRecordAboveLevel(t sweet.T) {
+	var (
+		shim     = &testShim{}
+		recorded = []string{}
+		adapter  = newRecordingShim(adaptShim(shim), LevelWarning, nil, func(level LogLevel, fields Fields, message string) {
+			recorded = append(recorded, message)
+		})
+	)
+
+	adapter.LogWithFields(LevelInfo, nil, "a %d", 1)
+	adapter.LogWithFields(LevelWarning, nil, "b %d", 2)
+	adapter.LogWithFields(LevelError, nil, "c %d", 3)
+
+	Expect(shim.messages).To(HaveLen(3))
+	Expect(recorded).To(Equal([]string{"b 2", "c 3"}))
+}
+
+func (s *RecordingSuite) TestRecordWithFields(t sweet.T) {
+	var (
+		shim    = &testShim{}
+		fields  Fields
+		adapter = newRecordingShim(adaptShim(shim), LevelWarning, nil, func(level LogLevel, f Fields, message string) {
+			fields = f
+		})
+	)
+
+	adapter.WithFields(Fields{"x": 1}).LogWithFields(LevelError, Fields{"y": 2}, "a")
+	Expect(fields["x"]).To(Equal(1))
+	Expect(fields["y"]).To(Equal(2))
+}
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotatingFile is a file writer which renames the file once it reaches a
+// maximum size and continues writing to a new file. The rotated files are
+// named path.1 (the most recent) through path.N.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	mutex      sync.Mutex
+}
+
+const megabyte = 1024 * 1024
+
+func openRotatingFile(path string, maxSize, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:       path,
+		maxSize:    int64(maxSize) * megabyte,
+		maxBackups: maxBackups,
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Sync() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.file.Sync()
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	if f.maxBackups == 0 {
+		if err := os.Remove(f.path); err != nil {
+			return err
+		}
+	} else {
+		for i := f.maxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(f.backupPath(i), f.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		if err := os.Rename(f.path, f.backupPath(1)); err != nil {
+			return err
+		}
+	}
+
+	return f.open()
+}
+
+func (f *rotatingFile) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", f.path, i)
+}
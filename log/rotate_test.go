@@ -0,0 +1,49 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type RotatingFileSuite struct{}
+
+func (s *RotatingFileSuite) TestRotate(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-log")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	f, err := openRotatingFile(path, 0, 2)
+	Expect(err).To(BeNil())
+	f.maxSize = 4
+
+	for _, line := range []string{"aaa\n", "bbb\n", "ccc\n", "ddd\n"} {
+		_, err := f.Write([]byte(line))
+		Expect(err).To(BeNil())
+	}
+
+	Expect(f.Sync()).To(BeNil())
+	Expect(ioutil.ReadFile(path)).To(Equal([]byte("ddd\n")))
+	Expect(ioutil.ReadFile(path + ".1")).To(Equal([]byte("ccc\n")))
+	Expect(ioutil.ReadFile(path + ".2")).To(Equal([]byte("bbb\n")))
+	Expect(path + ".3").NotTo(BeAnExistingFile())
+}
+
+func (s *RotatingFileSuite) TestNoRotation(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-log")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	Expect(ioutil.WriteFile(path, []byte("old\n"), 0644)).To(BeNil())
+
+	f, err := openRotatingFile(path, 0, 0)
+	Expect(err).To(BeNil())
+	_, err = f.Write([]byte("new\n"))
+	Expect(err).To(BeNil())
+	Expect(ioutil.ReadFile(path)).To(Equal([]byte("old\nnew\n")))
+}
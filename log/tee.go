@@ -0,0 +1,59 @@
+package log
+
+type teeShim struct {
+	loggers []Logger
+}
+
+//
+// Shim
+
+var _ logShim = &teeShim{}
+
+// NewTeeLogger returns a logger which writes each message to all of the given
+// loggers. Syncing the returned logger syncs each of the given loggers.
+func NewTeeLogger(loggers ...Logger) Logger {
+	return adaptShim(&teeShim{loggers: loggers})
+}
+
+func (s *teeShim) WithFields(fields Fields) logShim {
+	if len(fields) == 0 {
+		return s
+	}
+
+	loggers := []Logger{}
+	for _, logger := range s.loggers {
+		loggers = append(loggers, logger.WithFields(fields))
+	}
+
+	return &teeShim{loggers: loggers}
+}
+
+// LogWithFields writes the message to every logger. As logging a fatal message
+// exits the process, a fatal message is written to all but the last logger at
+// the error level (and those loggers are synced) before being written to the
+// last logger.
+func (s *teeShim) LogWithFields(level LogLevel, fields Fields, format string, args ...interface{}) {
+	fields = addCaller(fields)
+
+	for i, logger := range s.loggers {
+		if level == LevelFatal && i < len(s.loggers)-1 {
+			logger.LogWithFields(LevelError, fields.clone(), format, args...)
+			logger.Sync()
+			continue
+		}
+
+		logger.LogWithFields(level, fields.clone(), format, args...)
+	}
+}
+
+// Sync syncs every logger and returns the first error encountered.
+func (s *teeShim) Sync() error {
+	var firstErr error
+	for _, logger := range s.loggers {
+		if err := logger.Sync(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type TeeSuite struct{}
+
+func (s *TeeSuite) TestLog(t sweet.T) {
+	var (
+		shim1  = &testShim{}
+		shim2  = &testShim{}
+		logger = NewTeeLogger(adaptShim(shim1), adaptShim(shim2))
+	)
+
+	logger.InfoWithFields(Fields{"x": 1}, "a %d", 1)
+	logger.Warning("b")
+
+	for _, shim := range []*testShim{shim1, shim2} {
+		Expect(shim.messages).To(HaveLen(2))
+		Expect(shim.messages[0].level).To(Equal(LevelInfo))
+		Expect(shim.messages[0].fields["x"]).To(Equal(1))
+		Expect(shim.messages[1].level).To(Equal(LevelWarning))
+	}
+}
+
+func (s *TeeSuite) TestInitBackends(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-log")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		infoPath  = filepath.Join(dir, "info.log")
+		errorPath = filepath.Join(dir, "error.log")
+	)
+
+	logger, err := InitBackends(&Config{
+		LogBackend:  "logrus",
+		LogLevel:    "info",
+		LogEncoding: "json",
+		LogBackends: []BackendConfig{
+			{Destination: infoPath},
+			{Backend: "zap", Level: "error", Destination: errorPath},
+		},
+	})
+
+	Expect(err).To(BeNil())
+	logger.Info("informational")
+	logger.Error("erroneous")
+	Expect(logger.Sync()).To(BeNil())
+
+	info, err := ioutil.ReadFile(infoPath)
+	Expect(err).To(BeNil())
+	Expect(string(info)).To(ContainSubstring("informational"))
+	Expect(string(info)).To(ContainSubstring("erroneous"))
+
+	errors, err := ioutil.ReadFile(errorPath)
+	Expect(err).To(BeNil())
+	Expect(string(errors)).NotTo(ContainSubstring("informational"))
+	Expect(string(errors)).To(ContainSubstring("erroneous"))
+}
@@ -1,6 +1,8 @@
 package log
 
 import (
+	"io"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
@@ -65,6 +67,10 @@ func (z *ZapShim) getLogger(fields Fields) *zap.SugaredLogger {
 // Init
 
 func InitZapShim(c *Config) (Logger, error) {
+	return initZapShim(c, os.Stderr)
+}
+
+func initZapShim(c *Config, w io.Writer) (Logger, error) {
 	var (
 		level        zap.AtomicLevel
 		levelEncoder zapcore.LevelEncoder
@@ -88,32 +94,30 @@ func InitZapShim(c *Config) (Logger, error) {
 		timeEncoder = zapJSONTimeEncoder
 	}
 
-	config := zap.Config{
-		Level:             level,
-		DisableCaller:     true,
-		Encoding:          c.LogEncoding,
-		Development:       false,
-		DisableStacktrace: true,
-		OutputPaths:       []string{"stderr"},
-		ErrorOutputPaths:  []string{"stderr"},
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "timestamp",
-			LevelKey:       "level",
-			MessageKey:     "message",
-			CallerKey:      "caller",
-			EncodeLevel:    levelEncoder,
-			EncodeTime:     timeEncoder,
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
+	encoderConfig := zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		MessageKey:     "message",
+		CallerKey:      "caller",
+		EncodeLevel:    levelEncoder,
+		EncodeTime:     timeEncoder,
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	logger, err := config.Build()
-	if err != nil {
-		return nil, err
+	var encoder zapcore.Encoder
+	if c.LogEncoding == "console" {
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
 	}
 
+	logger := zap.New(
+		zapcore.NewCore(encoder, zapcore.Lock(zapcore.AddSync(w)), level),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+	)
+
 	return NewZapLogger(logger.Sugar(), c.LogInitialFields), nil
 }
 
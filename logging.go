@@ -11,6 +11,7 @@ type (
 	ReplayLogger  = log.ReplayLogger
 	Fields        = log.Fields
 	LoggingConfig = log.Config
+	BackendConfig = log.BackendConfig
 	LogLevel      = log.LogLevel
 
 	loggingConfigToken string
@@ -26,8 +27,15 @@ const (
 )
 
 var (
-	NewReplayAdapter = log.NewReplayAdapter
-	NewRollupAdapter = log.NewRollupAdapter
+	NewReplayAdapter    = log.NewReplayAdapter
+	NewRollupAdapter    = log.NewRollupAdapter
+	NewRecordingAdapter = log.NewRecordingAdapter
+	NewTeeLogger        = log.NewTeeLogger
+	NewLimitAdapter     = log.NewLimitAdapter
+	NewStreamWriter     = log.NewStreamWriter
+	CaptureStreams      = log.CaptureStreams
+	NewStdLogger        = log.NewStdLogger
+	RedirectStdLog      = log.RedirectStdLog
 
 	LoggingConfigToken = loggingConfigToken("nacelle-logging")
 	ErrBadConfig       = errors.New("logging config not registered properly")
@@ -39,16 +47,26 @@ func InitLogging(config Config) (logger Logger, err error) {
 		return nil, ErrBadConfig
 	}
 
-	switch c.LogBackend {
-	case "gomol":
+	switch {
+	case len(c.LogBackends) > 0:
+		logger, err = log.InitBackends(c)
+	case c.LogBackend == "gomol":
 		logger, err = log.InitGomolShim(c)
-	case "logrus":
+	case c.LogBackend == "logrus":
 		logger, err = log.InitLogrusShim(c)
-	case "zap":
+	case c.LogBackend == "zap":
 		logger, err = log.InitZapShim(c)
 	}
 
-	return
+	if err != nil {
+		return nil, err
+	}
+
+	if c.LogMaxFieldSize > 0 || c.LogMaxEntrySize > 0 {
+		logger = log.NewLimitAdapter(logger, c.LogMaxFieldSize, c.LogMaxEntrySize)
+	}
+
+	return logger, nil
 }
 
 func emergencyLogger() Logger {
@@ -0,0 +1,10 @@
+//go:build !go1.21
+// +build !go1.21
+
+package nacelle
+
+// redirectStdlib routes the standard library log package through the given
+// logger. The log/slog package is not available before Go 1.21.
+func redirectStdlib(logger Logger) func() {
+	return RedirectStdLog(logger, LevelInfo)
+}
@@ -0,0 +1,17 @@
+//go:build go1.21
+// +build go1.21
+
+package nacelle
+
+import "github.com/efritz/nacelle/log"
+
+var (
+	NewSlogHandler = log.NewSlogHandler
+	RedirectSlog   = log.RedirectSlog
+)
+
+// redirectStdlib routes the standard library log and log/slog packages through
+// the given logger.
+func redirectStdlib(logger Logger) func() {
+	return RedirectSlog(logger)
+}
@@ -14,10 +14,22 @@ func TestMain(m *testing.M) {
 	sweet.Run(m, func(s *sweet.S) {
 		s.RegisterPlugin(junit.NewPlugin())
 
+		s.AddSuite(&CleanupSuite{})
 		s.AddSuite(&ConfigSuite{})
 		s.AddSuite(&ConfigTagsSuite{})
+		s.AddSuite(&ConfigToolSuite{})
+		s.AddSuite(&DevelopmentSuite{})
+		s.AddSuite(&FlightRecorderSuite{})
+		s.AddSuite(&HardeningSuite{})
+		s.AddSuite(&HealthSuite{})
+		s.AddSuite(&KillSwitchSuite{})
+		s.AddSuite(&OperationsSuite{})
+		s.AddSuite(&PortsSuite{})
 		s.AddSuite(&ServiceSuite{})
 		s.AddSuite(&RunnerSuite{})
+		s.AddSuite(&TestContainerSuite{})
+		s.AddSuite(&TimelineSuite{})
+		s.AddSuite(&TimeoutsSuite{})
 		s.AddSuite(&UtilSuite{})
 	})
 }
@@ -1,22 +1,59 @@
 package nacelle
 
 import (
+	"fmt"
+	"sync"
 	"time"
 )
 
 type (
 	initializerMeta struct {
 		Initializer
-		name    string
-		timeout time.Duration
+		name        string
+		key         string
+		timeout     time.Duration
+		initialized bool
 	}
 
 	processMeta struct {
 		Process
-		name        string
-		priority    int
-		silentExit  bool
-		initTimeout time.Duration
+		name            string
+		key             string
+		priority        int
+		stopPriority    *int
+		silentExit      bool
+		job             *jobResult
+		initTimeout     time.Duration
+		readyTimeout    time.Duration
+		startTimeout    time.Duration
+		labels          []string
+		tags            map[string]string
+		configPrefix    string
+		replicas        int
+		replica         *Replica
+		progress        *ProgressReporter
+		restartPolicy   RestartPolicy
+		restarts        int
+		failures        failureLog
+		crashLoopPolicy *CrashLoopPolicy
+		livenessPolicy  *LivenessPolicy
+		restartTimes    []time.Time
+		abandoned       bool
+		initialized     bool
+		mutex           sync.Mutex
+		exitExpected    bool
+		exited          chan struct{}
+	}
+
+	// Replica describes one instance of a process registered with multiple
+	// replicas. A process can receive its replica by tagging a field with
+	// `service:"replica"`.
+	Replica struct {
+		// Index is the zero-based index of this instance.
+		Index int
+
+		set   *replicaSet
+		count int
 	}
 
 	// InitializerConfigFunc is a function used to append additional
@@ -37,11 +74,87 @@ func (m *initializerMeta) Name() string {
 }
 
 func (m *processMeta) Name() string {
-	if m.name == "" {
-		return "<unnamed>"
+	name := m.name
+	if name == "" {
+		name = "<unnamed>"
 	}
 
-	return m.name
+	if m.replica != nil {
+		return fmt.Sprintf("%s[%d]", name, m.replica.Index)
+	}
+
+	return name
+}
+
+// fields returns the given log fields along with the tags of the process.
+func (m *processMeta) fields(fields Fields) Fields {
+	if len(m.tags) == 0 {
+		return fields
+	}
+
+	merged := Fields{"tags": m.tags}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// tagsOf returns the tags of the given process meta, or nil if the given value
+// is the meta of an initializer.
+func tagsOf(initializer Initializer) map[string]string {
+	if process, ok := initializer.(*processMeta); ok {
+		return process.tags
+	}
+
+	return nil
+}
+
+// getStopPriority returns the stop priority of the process, which defaults
+// to its start priority.
+func (m *processMeta) getStopPriority() int {
+	if m.stopPriority != nil {
+		return *m.stopPriority
+	}
+
+	return m.priority
+}
+
+func (m *processMeta) hasLabel(label string) bool {
+	for _, l := range m.labels {
+		if l == label {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isExitExpected returns true if the process is being stopped by the runner
+// for a reason other than application shutdown (e.g. a restart or a scale down),
+// in which case the return of its Start method should not halt the application.
+func (m *processMeta) isExitExpected() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.exitExpected
+}
+
+func (m *processMeta) setExitExpected(exitExpected bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.exitExpected = exitExpected
+}
+
+func (m *processMeta) getExited() <-chan struct{} {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.exited
+}
+
+func (m *processMeta) setExited(exited chan struct{}) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.exited = exited
 }
 
 //
@@ -64,6 +177,72 @@ func WithPriority(priority int) ProcessConfigFunc {
 	return func(meta *processMeta) { meta.priority = priority }
 }
 
+// WithStopPriority assigns a stop priority to a process. During shutdown, processes
+// with a higher-valued stop priority are stopped before processes with a lower-valued
+// stop priority, and processes with the same stop priority are stopped in the order
+// of their start priority (highest to lowest) and then in the order of registration.
+// By default, the stop priority of a process is its start priority (see WithPriority),
+// so processes are stopped in the reverse of the order in which they were started.
+func WithStopPriority(priority int) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.stopPriority = &priority }
+}
+
+// WithProcessLabels attaches a set of labels to a process. Labels are used to
+// select a set of processes which should be operated on together, such as by
+// the RollingRestart method of the process runner.
+func WithProcessLabels(labels ...string) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.labels = append(meta.labels, labels...) }
+}
+
+// WithProcessTags attaches a set of key/value tags to a process (e.g. the subsystem,
+// team, or tier to which it belongs). Tags are reported by the Processes method of
+// the process runner, attached to the lifecycle events of the process, and added
+// to the fields of log messages describing a fatal error from the process. Tags
+// from multiple options are merged, and later values replace earlier ones.
+func WithProcessTags(tags map[string]string) ProcessConfigFunc {
+	return func(meta *processMeta) {
+		if meta.tags == nil {
+			meta.tags = map[string]string{}
+		}
+
+		for key, value := range tags {
+			meta.tags[key] = value
+		}
+	}
+}
+
+// WithProcessConfigPrefix gives a process a view of the application config which
+// is limited to the chunks registered under the given prefix (see PrefixedConfig).
+// This allows multiple instances of the same process type to be configured
+// independently within one application.
+func WithProcessConfigPrefix(prefix string) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.configPrefix = prefix }
+}
+
+// NewReplica creates a replica with a fixed index and replica count. This is
+// useful for testing processes which depend on their replica.
+func NewReplica(index, count int) *Replica {
+	return &Replica{Index: index, count: count}
+}
+
+// Count returns the current number of replicas of the process. This value
+// may change if the process runner is scaled via the Scale method.
+func (r *Replica) Count() int {
+	if r.set == nil {
+		return r.count
+	}
+
+	return r.set.size()
+}
+
+// WithReplicas sets the number of instances of a process which are created from
+// a process factory registered via RegisterProcessFactory. Each instance is
+// initialized, started, and stopped independently. The default is one instance.
+// This option has no effect on processes registered via RegisterProcess.
+func WithReplicas(replicas int) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.replicas = replicas }
+}
+
 // WithSilentExit allows a process to exit without causing the progrma to halt.
 // The default is the opposite, where the completion of any registered process
 // (even successful) causes a graceful shutdown of the other processes.
@@ -71,12 +250,54 @@ func WithSilentExit() ProcessConfigFunc {
 	return func(meta *processMeta) { meta.silentExit = true }
 }
 
-// WithInitializerTimeout sets the time limit for the initializer.
+// WithRestart sets the restart policy of a process. A process which exits in a way
+// covered by the policy is re-initialized and started again instead of causing a
+// graceful shutdown of the application. The default policy never restarts.
+func WithRestart(policy RestartPolicy) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.restartPolicy = policy }
+}
+
+// WithCrashLoopPolicy sets the crash loop policy of a process with a restart policy
+// (see WithRestart). A process which is restarted more often than the policy allows
+// is not restarted again, and the policy's escalation is applied.
+func WithCrashLoopPolicy(policy CrashLoopPolicy) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.crashLoopPolicy = &policy }
+}
+
+// WithLivenessCheck sets the policy with which the runner polls the HealthCheck
+// method of a running process (which must implement HealthChecker) in order to
+// detect a process which is running but wedged.
+func WithLivenessCheck(policy LivenessPolicy) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.livenessPolicy = &policy }
+}
+
+// WithInitializerTimeout sets the time limit for the initializer's Init method. An
+// initializer which does not finish in time fails with an error which names the
+// time limit and the last step reported via its progress reporter. The default is
+// no time limit.
 func WithInitializerTimeout(timeout time.Duration) InitializerConfigFunc {
 	return func(meta *initializerMeta) { meta.timeout = timeout }
 }
 
-// WithProcessInitTimeout sets the time limit for the process's Init method.
+// WithReadyTimeout sets the time limit for a process implementing ProcessReadyNotifier
+// to report that it is ready once started. A process which does not become ready in
+// time causes the application to shut down. The default is no time limit.
+func WithReadyTimeout(timeout time.Duration) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.readyTimeout = timeout }
+}
+
+// WithStartTimeout sets the start window of a process whose Start method is expected
+// to block until the process is stopped. If the Start method returns or panics within
+// the window, or if a process implementing ProcessReadyNotifier does not report that
+// it is ready within the window, startup fails and the application shuts down. The
+// runner does not start processes at the next priority until the window has passed.
+// The default is no start window.
+func WithStartTimeout(timeout time.Duration) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.startTimeout = timeout }
+}
+
+// WithProcessInitTimeout sets the time limit for the process's Init method. See
+// WithInitializerTimeout.
 func WithProcessInitTimeout(timeout time.Duration) ProcessConfigFunc {
 	return func(meta *processMeta) { meta.initTimeout = timeout }
 }
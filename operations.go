@@ -0,0 +1,118 @@
+package nacelle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Operations tracks the long-running operations (e.g. jobs, transactions, or
+	// exports) being performed by an application's processes. During a graceful
+	// shutdown, the process runner logs the operations which are still outstanding
+	// and cancels them once the grace period elapses (see WithOperationGracePeriod).
+	// The bootstrapper registers an instance to the service key "operations".
+	Operations struct {
+		mutex      sync.Mutex
+		operations map[*operation]struct{}
+	}
+
+	// Operation is a snapshot of an outstanding operation.
+	Operation struct {
+		ID      string
+		Started time.Time
+	}
+
+	operation struct {
+		id      string
+		started time.Time
+		cancel  func()
+	}
+)
+
+// NewOperations creates an empty operation registry.
+func NewOperations() *Operations {
+	return &Operations{
+		operations: map[*operation]struct{}{},
+	}
+}
+
+// Begin records the start of the operation with the given ID. The given cancel
+// function, which may be nil, is called if the operation is cancelled during
+// shutdown. The returned function must be called once the operation completes.
+func (o *Operations) Begin(id string, cancel func()) func() {
+	op := &operation{
+		id:      id,
+		started: time.Now(),
+		cancel:  cancel,
+	}
+
+	o.mutex.Lock()
+	o.operations[op] = struct{}{}
+	o.mutex.Unlock()
+
+	return func() {
+		o.mutex.Lock()
+		defer o.mutex.Unlock()
+
+		delete(o.operations, op)
+	}
+}
+
+// Outstanding returns a snapshot of each operation which has not completed, in
+// the order in which they started.
+func (o *Operations) Outstanding() []Operation {
+	operations := []Operation{}
+	for _, op := range o.outstanding() {
+		operations = append(operations, Operation{ID: op.id, Started: op.started})
+	}
+
+	return operations
+}
+
+// Cancel calls the cancel function of each operation which has not completed
+// and returns the number of operations cancelled. Cancelled operations remain
+// outstanding until they complete.
+func (o *Operations) Cancel() int {
+	cancelled := 0
+	for _, op := range o.outstanding() {
+		if op.cancel != nil {
+			op.cancel()
+			cancelled++
+		}
+	}
+
+	return cancelled
+}
+
+func (o *Operations) outstanding() []*operation {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	operations := []*operation{}
+	for op := range o.operations {
+		operations = append(operations, op)
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].started.Before(operations[j].started)
+	})
+
+	return operations
+}
+
+func (op Operation) String() string {
+	return fmt.Sprintf("%s running for %s", op.ID, time.Since(op.Started)/time.Second*time.Second)
+}
+
+// describeOperations returns a comma-separated description of the given operations.
+func describeOperations(operations []Operation) string {
+	descriptions := []string{}
+	for _, op := range operations {
+		descriptions = append(descriptions, op.String())
+	}
+
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,39 @@
+package nacelle
+
+import (
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type OperationsSuite struct{}
+
+func (s *OperationsSuite) TestBeginAndCancel(t sweet.T) {
+	var (
+		operations = NewOperations()
+		cancelled  = []string{}
+	)
+
+	done1 := operations.Begin("export-1", func() { cancelled = append(cancelled, "export-1") })
+	done2 := operations.Begin("export-2", nil)
+	done3 := operations.Begin("export-3", func() { cancelled = append(cancelled, "export-3") })
+
+	Expect(operationIDs(operations.Outstanding())).To(Equal([]string{"export-1", "export-2", "export-3"}))
+
+	done1()
+	Expect(operationIDs(operations.Outstanding())).To(Equal([]string{"export-2", "export-3"}))
+	Expect(operations.Cancel()).To(Equal(1))
+	Expect(cancelled).To(Equal([]string{"export-3"}))
+
+	done2()
+	done3()
+	Expect(operations.Outstanding()).To(BeEmpty())
+}
+
+func operationIDs(operations []Operation) []string {
+	ids := []string{}
+	for _, op := range operations {
+		ids = append(ids, op.ID)
+	}
+
+	return ids
+}
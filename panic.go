@@ -0,0 +1,46 @@
+package nacelle
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned in place of a panic in the Init method of an initializer
+// or the Init, Start, or Stop method of a process. The runner handles the error
+// like any other error returned by the method, so a panicking process causes the
+// application to shut down gracefully rather than crash. The error is wrapped
+// by the error sent on the runner's error channel (see errors.As).
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the panicking goroutine.
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic (%v)", e.Value)
+}
+
+// callSafely calls the given function and returns a PanicError if it panics.
+func callSafely(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: string(debug.Stack())}
+		}
+	}()
+
+	return f()
+}
+
+// errorFields returns the log fields describing the given error. The stack
+// trace is included if the error was caused by a panic.
+func errorFields(err error) Fields {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return Fields{"stack": panicErr.Stack}
+	}
+
+	return nil
+}
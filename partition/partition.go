@@ -0,0 +1,113 @@
+// Package partition provides helpers which allow the replicas of a process
+// registered via RegisterProcessFactory to split a keyspace deterministically.
+// Assignments are computed from the live replica count, so they rebalance
+// automatically when the process runner is scaled.
+package partition
+
+import (
+	"hash/fnv"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// Assigner determines which keys and partitions are owned by a replica.
+	Assigner interface {
+		// Owns returns true if the given key is assigned to the replica.
+		Owns(key string) bool
+
+		// Partitions returns the subset of the partitions [0, n) which are
+		// assigned to the replica.
+		Partitions(n int) []int
+	}
+
+	// Strategy maps a key onto the index of the replica which owns it.
+	Strategy func(key string, count int) int
+
+	assigner struct {
+		replica  *nacelle.Replica
+		strategy Strategy
+	}
+)
+
+// NewAssigner creates an assigner for the given replica using the given strategy.
+func NewAssigner(replica *nacelle.Replica, strategy Strategy) Assigner {
+	return &assigner{
+		replica:  replica,
+		strategy: strategy,
+	}
+}
+
+// NewModuloAssigner creates an assigner which uses modulo hashing.
+func NewModuloAssigner(replica *nacelle.Replica) Assigner {
+	return NewAssigner(replica, Modulo)
+}
+
+// NewRendezvousAssigner creates an assigner which uses rendezvous hashing.
+func NewRendezvousAssigner(replica *nacelle.Replica) Assigner {
+	return NewAssigner(replica, Rendezvous)
+}
+
+func (a *assigner) Owns(key string) bool {
+	count := a.replica.Count()
+	if count == 0 {
+		return false
+	}
+
+	return a.strategy(key, count) == a.replica.Index
+}
+
+func (a *assigner) Partitions(n int) []int {
+	partitions := []int{}
+	for i := 0; i < n; i++ {
+		if a.owns(i, n) {
+			partitions = append(partitions, i)
+		}
+	}
+
+	return partitions
+}
+
+func (a *assigner) owns(partition, n int) bool {
+	count := a.replica.Count()
+	if count == 0 {
+		return false
+	}
+
+	// Partitions are dealt round-robin so that every replica receives
+	// an even share regardless of the hashing strategy.
+	return partition%count == a.replica.Index
+}
+
+// Modulo assigns a key to the replica whose index is the hash of the key
+// modulo the replica count. This is cheap, but most keys move when the
+// replica count changes.
+func Modulo(key string, count int) int {
+	return int(hash(key) % uint64(count))
+}
+
+// Rendezvous assigns a key to the replica with the highest hash of the key
+// and replica index. Only the keys of added or removed replicas move when
+// the replica count changes.
+func Rendezvous(key string, count int) int {
+	var (
+		owner = 0
+		best  = uint64(0)
+	)
+
+	for i := 0; i < count; i++ {
+		if score := hash(key, byte(i), byte(i>>8)); i == 0 || score > best {
+			owner = i
+			best = score
+		}
+	}
+
+	return owner
+}
+
+func hash(key string, suffix ...byte) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write(suffix)
+	return h.Sum64()
+}
@@ -0,0 +1,51 @@
+package partition
+
+import (
+	"fmt"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type PartitionSuite struct{}
+
+func (s *PartitionSuite) TestExactlyOneOwner(t sweet.T) {
+	for _, strategy := range []Strategy{Modulo, Rendezvous} {
+		assigners := []Assigner{}
+		for i := 0; i < 5; i++ {
+			assigners = append(assigners, NewAssigner(nacelle.NewReplica(i, 5), strategy))
+		}
+
+		for i := 0; i < 100; i++ {
+			owners := 0
+			for _, assigner := range assigners {
+				if assigner.Owns(fmt.Sprintf("key-%d", i)) {
+					owners++
+				}
+			}
+
+			Expect(owners).To(Equal(1))
+		}
+	}
+}
+
+func (s *PartitionSuite) TestRendezvousStability(t sweet.T) {
+	for i := 0; i < 100; i++ {
+		var (
+			key    = fmt.Sprintf("key-%d", i)
+			before = Rendezvous(key, 4)
+			after  = Rendezvous(key, 5)
+		)
+
+		// Keys only move to the new replica
+		Expect(after == before || after == 4).To(BeTrue())
+	}
+}
+
+func (s *PartitionSuite) TestPartitions(t sweet.T) {
+	Expect(NewModuloAssigner(nacelle.NewReplica(0, 3)).Partitions(7)).To(Equal([]int{0, 3, 6}))
+	Expect(NewModuloAssigner(nacelle.NewReplica(1, 3)).Partitions(7)).To(Equal([]int{1, 4}))
+	Expect(NewModuloAssigner(nacelle.NewReplica(2, 3)).Partitions(7)).To(Equal([]int{2, 5}))
+	Expect(NewRendezvousAssigner(nacelle.NewReplica(0, 0)).Partitions(7)).To(BeEmpty())
+}
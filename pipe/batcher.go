@@ -0,0 +1,110 @@
+package pipe
+
+import (
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+)
+
+type (
+	// Batcher collects values and flushes them as a batch once the batch
+	// reaches a maximum size or once the flush interval has elapsed since
+	// the first value of the batch was added, whichever comes first.
+	Batcher struct {
+		size     int
+		interval time.Duration
+		flush    FlushFunc
+		clock    glock.Clock
+		batch    []interface{}
+		timer    chan struct{}
+		mutex    sync.Mutex
+	}
+
+	// FlushFunc is called with each batch collected by a batcher.
+	FlushFunc func(batch []interface{})
+)
+
+// NewBatcher creates a batcher with the given maximum batch size and flush interval.
+func NewBatcher(size int, interval time.Duration, flush FlushFunc) *Batcher {
+	return newBatcher(size, interval, flush, glock.NewRealClock())
+}
+
+func newBatcher(size int, interval time.Duration, flush FlushFunc, clock glock.Clock) *Batcher {
+	return &Batcher{
+		size:     size,
+		interval: interval,
+		flush:    flush,
+		clock:    clock,
+	}
+}
+
+// Add appends a value to the current batch. If the batch is full, it is
+// flushed synchronously before returning.
+func (b *Batcher) Add(value interface{}) {
+	b.mutex.Lock()
+	b.batch = append(b.batch, value)
+
+	if len(b.batch) >= b.size {
+		batch := b.takeBatch()
+		b.mutex.Unlock()
+		b.flush(batch)
+		return
+	}
+
+	if len(b.batch) == 1 {
+		b.startTimer()
+	}
+
+	b.mutex.Unlock()
+}
+
+// Flush synchronously flushes the current batch, if it is non-empty. This
+// should be called when the owner of the batcher is shutting down.
+func (b *Batcher) Flush() {
+	b.mutex.Lock()
+	batch := b.takeBatch()
+	b.mutex.Unlock()
+
+	if len(batch) > 0 {
+		b.flush(batch)
+	}
+}
+
+func (b *Batcher) startTimer() {
+	timer := make(chan struct{})
+	b.timer = timer
+
+	go func() {
+		select {
+		case <-b.clock.After(b.interval):
+		case <-timer:
+			return
+		}
+
+		b.mutex.Lock()
+		if b.timer != timer {
+			b.mutex.Unlock()
+			return
+		}
+
+		batch := b.takeBatch()
+		b.mutex.Unlock()
+
+		if len(batch) > 0 {
+			b.flush(batch)
+		}
+	}()
+}
+
+// takeBatch must be called while holding the batcher's lock.
+func (b *Batcher) takeBatch() []interface{} {
+	if b.timer != nil {
+		close(b.timer)
+		b.timer = nil
+	}
+
+	batch := b.batch
+	b.batch = nil
+	return batch
+}
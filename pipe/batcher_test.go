@@ -0,0 +1,52 @@
+package pipe
+
+import (
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type BatcherSuite struct{}
+
+func (s *BatcherSuite) TestFlushOnSize(t sweet.T) {
+	var (
+		batches = make(chan []interface{}, 2)
+		clock   = glock.NewMockClock()
+		batcher = newBatcher(3, time.Second, func(batch []interface{}) { batches <- batch }, clock)
+	)
+
+	batcher.Add(1)
+	batcher.Add(2)
+	Consistently(batches).ShouldNot(Receive())
+	batcher.Add(3)
+	Eventually(batches).Should(Receive(Equal([]interface{}{1, 2, 3})))
+}
+
+func (s *BatcherSuite) TestFlushOnInterval(t sweet.T) {
+	var (
+		batches = make(chan []interface{}, 2)
+		clock   = glock.NewMockClock()
+		batcher = newBatcher(3, time.Second, func(batch []interface{}) { batches <- batch }, clock)
+	)
+
+	batcher.Add(1)
+	batcher.Add(2)
+	clock.BlockingAdvance(time.Second)
+	Eventually(batches).Should(Receive(Equal([]interface{}{1, 2})))
+}
+
+func (s *BatcherSuite) TestFlush(t sweet.T) {
+	var (
+		batches = make(chan []interface{}, 2)
+		clock   = glock.NewMockClock()
+		batcher = newBatcher(3, time.Second, func(batch []interface{}) { batches <- batch }, clock)
+	)
+
+	batcher.Flush()
+	Consistently(batches).ShouldNot(Receive())
+	batcher.Add(1)
+	batcher.Flush()
+	Eventually(batches).Should(Receive(Equal([]interface{}{1})))
+}
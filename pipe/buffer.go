@@ -0,0 +1,115 @@
+package pipe
+
+import (
+	"sync"
+)
+
+type (
+	// DroppingBuffer is a queue with a fixed capacity which never blocks the
+	// writer. When the buffer is full, either the oldest value in the buffer
+	// or the value being pushed is dropped, depending on the drop policy.
+	DroppingBuffer struct {
+		values     []interface{}
+		capacity   int
+		dropOldest bool
+		dropped    uint64
+		signal     chan struct{}
+		mutex      sync.Mutex
+	}
+
+	// DropPolicy determines which value a full dropping buffer discards.
+	DropPolicy int
+)
+
+const (
+	// DropNewest discards the value being pushed into a full buffer.
+	DropNewest DropPolicy = iota
+
+	// DropOldest discards the oldest value in a full buffer.
+	DropOldest
+)
+
+// NewDroppingBuffer creates a dropping buffer with the given capacity and policy.
+func NewDroppingBuffer(capacity int, policy DropPolicy) *DroppingBuffer {
+	return &DroppingBuffer{
+		values:     make([]interface{}, 0, capacity),
+		capacity:   capacity,
+		dropOldest: policy == DropOldest,
+		signal:     make(chan struct{}, 1),
+	}
+}
+
+// Push adds a value to the buffer. Returns false if a value was dropped.
+func (b *DroppingBuffer) Push(value interface{}) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	defer b.notify()
+
+	if len(b.values) < b.capacity {
+		b.values = append(b.values, value)
+		return true
+	}
+
+	b.dropped++
+
+	if b.dropOldest && b.capacity > 0 {
+		b.values = append(b.values[1:], value)
+	}
+
+	return false
+}
+
+// Pop removes and returns the oldest value in the buffer. Returns false if
+// the buffer is empty.
+func (b *DroppingBuffer) Pop() (interface{}, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.values) == 0 {
+		return nil, false
+	}
+
+	value := b.values[0]
+	b.values = b.values[1:]
+	return value, true
+}
+
+// Drain removes and returns all values in the buffer.
+func (b *DroppingBuffer) Drain() []interface{} {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	values := b.values
+	b.values = make([]interface{}, 0, b.capacity)
+	return values
+}
+
+// Ready returns a channel which receives a value after a push to the buffer.
+// This can be used by a reader to wait for values without polling.
+func (b *DroppingBuffer) Ready() <-chan struct{} {
+	return b.signal
+}
+
+// Len returns the number of values in the buffer.
+func (b *DroppingBuffer) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return len(b.values)
+}
+
+// Dropped returns the number of values which have been dropped.
+func (b *DroppingBuffer) Dropped() uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.dropped
+}
+
+func (b *DroppingBuffer) notify() {
+	select {
+	case b.signal <- struct{}{}:
+	default:
+	}
+}
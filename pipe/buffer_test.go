@@ -0,0 +1,44 @@
+package pipe
+
+import (
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type BufferSuite struct{}
+
+func (s *BufferSuite) TestDropNewest(t sweet.T) {
+	buffer := NewDroppingBuffer(2, DropNewest)
+	Expect(buffer.Push(1)).To(BeTrue())
+	Expect(buffer.Push(2)).To(BeTrue())
+	Expect(buffer.Push(3)).To(BeFalse())
+	Expect(buffer.Dropped()).To(Equal(uint64(1)))
+	Expect(buffer.Drain()).To(Equal([]interface{}{1, 2}))
+	Expect(buffer.Len()).To(Equal(0))
+}
+
+func (s *BufferSuite) TestDropOldest(t sweet.T) {
+	buffer := NewDroppingBuffer(2, DropOldest)
+	Expect(buffer.Push(1)).To(BeTrue())
+	Expect(buffer.Push(2)).To(BeTrue())
+	Expect(buffer.Push(3)).To(BeFalse())
+	Expect(buffer.Dropped()).To(Equal(uint64(1)))
+
+	value, ok := buffer.Pop()
+	Expect(ok).To(BeTrue())
+	Expect(value).To(Equal(2))
+
+	value, ok = buffer.Pop()
+	Expect(ok).To(BeTrue())
+	Expect(value).To(Equal(3))
+
+	_, ok = buffer.Pop()
+	Expect(ok).To(BeFalse())
+}
+
+func (s *BufferSuite) TestReady(t sweet.T) {
+	buffer := NewDroppingBuffer(2, DropNewest)
+	Consistently(buffer.Ready()).ShouldNot(Receive())
+	buffer.Push(1)
+	Eventually(buffer.Ready()).Should(Receive())
+}
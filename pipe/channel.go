@@ -0,0 +1,99 @@
+// Package pipe provides backpressure-aware primitives for moving values
+// between goroutines: bounded channels which account for blocked and
+// rejected sends, buffers which drop values instead of blocking, and
+// collectors which flush batches on size or on an interval.
+package pipe
+
+import (
+	"sync/atomic"
+)
+
+type (
+	// BoundedChannel is a channel with a fixed capacity which keeps counts
+	// of the values sent through it and the sends which were rejected.
+	BoundedChannel struct {
+		ch       chan interface{}
+		sent     uint64
+		rejected uint64
+		blocked  uint64
+	}
+
+	// ChannelStats is a snapshot of the counters of a bounded channel.
+	ChannelStats struct {
+		Len      int
+		Cap      int
+		Sent     uint64
+		Rejected uint64
+		Blocked  uint64
+	}
+)
+
+// NewBoundedChannel creates a bounded channel with the given capacity.
+func NewBoundedChannel(capacity int) *BoundedChannel {
+	return &BoundedChannel{
+		ch: make(chan interface{}, capacity),
+	}
+}
+
+// Chan returns the channel from which values can be received.
+func (c *BoundedChannel) Chan() <-chan interface{} {
+	return c.ch
+}
+
+// Send writes the value to the channel. If the channel is full, this method
+// blocks until there is room or the given halt channel is closed. Returns
+// false if the value was not sent.
+func (c *BoundedChannel) Send(value interface{}, halt <-chan struct{}) bool {
+	if c.trySend(value) {
+		return true
+	}
+
+	atomic.AddUint64(&c.blocked, 1)
+
+	select {
+	case c.ch <- value:
+		atomic.AddUint64(&c.sent, 1)
+		return true
+	case <-halt:
+		atomic.AddUint64(&c.rejected, 1)
+		return false
+	}
+}
+
+// TrySend writes the value to the channel if there is room and returns
+// false without blocking otherwise. Failed sends are counted as rejected.
+func (c *BoundedChannel) TrySend(value interface{}) bool {
+	if c.trySend(value) {
+		return true
+	}
+
+	atomic.AddUint64(&c.rejected, 1)
+	return false
+}
+
+func (c *BoundedChannel) trySend(value interface{}) bool {
+	select {
+	case c.ch <- value:
+		atomic.AddUint64(&c.sent, 1)
+		return true
+	default:
+	}
+
+	return false
+}
+
+// Close closes the underlying channel. It is an error to send after close.
+func (c *BoundedChannel) Close() {
+	close(c.ch)
+}
+
+// Stats returns the current counters of the channel.
+func (c *BoundedChannel) Stats() ChannelStats {
+	return ChannelStats{
+		Len:      len(c.ch),
+		Cap:      cap(c.ch),
+		Sent:     atomic.LoadUint64(&c.sent),
+		Rejected: atomic.LoadUint64(&c.rejected),
+		Blocked:  atomic.LoadUint64(&c.blocked),
+	}
+}
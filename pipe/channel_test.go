@@ -0,0 +1,33 @@
+package pipe
+
+import (
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type ChannelSuite struct{}
+
+func (s *ChannelSuite) TestSend(t sweet.T) {
+	var (
+		ch   = NewBoundedChannel(2)
+		halt = make(chan struct{})
+	)
+
+	Expect(ch.TrySend(1)).To(BeTrue())
+	Expect(ch.Send(2, halt)).To(BeTrue())
+	Expect(ch.TrySend(3)).To(BeFalse())
+
+	close(halt)
+	Expect(ch.Send(4, halt)).To(BeFalse())
+
+	Expect(ch.Stats()).To(Equal(ChannelStats{
+		Len:      2,
+		Cap:      2,
+		Sent:     2,
+		Rejected: 2,
+		Blocked:  1,
+	}))
+
+	Eventually(ch.Chan()).Should(Receive(Equal(1)))
+	Eventually(ch.Chan()).Should(Receive(Equal(2)))
+}
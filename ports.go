@@ -0,0 +1,115 @@
+package nacelle
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+)
+
+type (
+	// Ports tracks the TCP ports bound by the listening processes of an
+	// application. A process which is configured to listen on port zero
+	// receives an ephemeral port from the operating system; the chosen port
+	// is published here so that it can be discovered by tests and by code
+	// which registers the application with a sidecar. Two processes which
+	// are configured to bind the same port are detected during boot and
+	// produce an error naming both owners.
+	Ports struct {
+		mutex  sync.RWMutex
+		owners map[int]interface{}
+		ports  map[interface{}]int
+	}
+)
+
+// NewPorts creates an empty port registry.
+func NewPorts() *Ports {
+	return &Ports{
+		owners: map[int]interface{}{},
+		ports:  map[interface{}]int{},
+	}
+}
+
+// Listen binds a TCP listener to the given port on all interfaces on behalf
+// of the given key (generally the config token of the listening process). If
+// the port is zero, an ephemeral port is chosen. It is an error to listen on a
+// port which has already been claimed by a different key. Listening again with
+// the same key (e.g. after a process restart) replaces the previous claim.
+func (p *Ports) Listen(key interface{}, port int) (*net.TCPListener, error) {
+	return p.ListenHost(key, "", port)
+}
+
+// ListenHost behaves like Listen, but binds the listener to the given host. An
+// empty host binds the listener to all interfaces.
+func (p *Ports) ListenHost(key interface{}, host string, port int) (*net.TCPListener, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if owner, ok := p.owners[port]; ok && port != 0 && owner != key {
+		return nil, fmt.Errorf(
+			"cannot bind %s to port %d, already claimed by %s",
+			serializePortKey(key),
+			port,
+			serializePortKey(owner),
+		)
+	}
+
+	addr, err := net.ResolveTCPAddr("tcp", listenAddr(host, port))
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.ListenTCP("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind %s to port %d (%s)", serializePortKey(key), port, err.Error())
+	}
+
+	if previous, ok := p.ports[key]; ok {
+		delete(p.owners, previous)
+	}
+
+	chosen := listener.Addr().(*net.TCPAddr).Port
+	p.owners[chosen] = key
+	p.ports[key] = chosen
+	return listener, nil
+}
+
+// Port returns the port claimed by the given key. The second return value is
+// false if no port has been claimed by that key.
+func (p *Ports) Port(key interface{}) (int, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	port, ok := p.ports[key]
+	return port, ok
+}
+
+// Ports returns a map from each serialized key to the port it has claimed.
+func (p *Ports) Ports() map[string]int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	ports := map[string]int{}
+	for key, port := range p.ports {
+		ports[serializePortKey(key)] = port
+	}
+
+	return ports
+}
+
+// serializePortKey formats a key for display. Config tokens are generally
+// named string types which carry a readable value, so the value is preferred
+// over the type name used by serializeKey.
+func serializePortKey(key interface{}) string {
+	return fmt.Sprintf("%v", key)
+}
+
+// listenAddr returns the address of the given host and port. An empty host is
+// the address of all interfaces.
+func listenAddr(host string, port int) string {
+	if host == "" {
+		host = "0.0.0.0"
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
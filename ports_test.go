@@ -0,0 +1,54 @@
+package nacelle
+
+import (
+	"net"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type PortsSuite struct{}
+
+func (s *PortsSuite) TestEphemeralPort(t sweet.T) {
+	ports := NewPorts()
+
+	listener, err := ports.Listen("a", 0)
+	Expect(err).To(BeNil())
+	defer listener.Close()
+
+	port, ok := ports.Port("a")
+	Expect(ok).To(BeTrue())
+	Expect(port).To(Equal(listener.Addr().(*net.TCPAddr).Port))
+	Expect(ports.Ports()).To(Equal(map[string]int{"a": port}))
+
+	_, ok = ports.Port("b")
+	Expect(ok).To(BeFalse())
+}
+
+func (s *PortsSuite) TestCollision(t sweet.T) {
+	ports := NewPorts()
+
+	listener, err := ports.Listen("a", 0)
+	Expect(err).To(BeNil())
+	defer listener.Close()
+
+	port, _ := ports.Port("a")
+	_, err = ports.Listen("b", port)
+	Expect(err).To(MatchError(ContainSubstring("already claimed by a")))
+}
+
+func (s *PortsSuite) TestRelisten(t sweet.T) {
+	ports := NewPorts()
+
+	listener, err := ports.Listen("a", 0)
+	Expect(err).To(BeNil())
+	port, _ := ports.Port("a")
+	listener.Close()
+
+	listener, err = ports.Listen("a", port)
+	Expect(err).To(BeNil())
+	defer listener.Close()
+
+	relistened, _ := ports.Port("a")
+	Expect(relistened).To(Equal(port))
+}
@@ -1,5 +1,7 @@
 package nacelle
 
+import "context"
+
 type (
 	// Process is a monitored worker. It is meant for long-running
 	// parts of a program (servers and background workers) which can
@@ -27,6 +29,97 @@ type (
 		Stop() error
 	}
 
+	// ContextProcess is a Process whose lifecycle methods accept a context.
+	// The context passed to Init and Start is canceled by the process runner
+	// when the application begins to shut down (and the context passed to
+	// Start is also canceled when the process is stopped individually, e.g.
+	// by a restart or a scale down). This allows the process to pass the
+	// context directly to context-aware libraries instead of maintaining a
+	// bespoke halt channel. A ContextProcess is registered to a process runner
+	// via the RegisterContextProcess method.
+	ContextProcess interface {
+		// Init configures the process. See Process#Init.
+		Init(ctx context.Context, config Config) error
+
+		// Start begins doing work. This method should return once the given
+		// context is canceled. See Process#Start.
+		Start(ctx context.Context) error
+
+		// Stop interrupts the routine running the Start method. The given
+		// context is not canceled by the runner. See Process#Stop.
+		Stop(ctx context.Context) error
+	}
+
+	// ProcessReadyNotifier is implemented by processes which are not able to do
+	// useful work as soon as their Start method is called (e.g. a server which
+	// must first warm a cache). The process runner waits for each such process
+	// at a priority to report that it is ready before starting the processes at
+	// the next priority. See WithReadyTimeout.
+	ProcessReadyNotifier interface {
+		// Ready returns a channel which is closed once the process is serving.
+		Ready() <-chan struct{}
+	}
+
+	// Drainer is implemented by processes which should stop accepting new work
+	// and finish in-flight work before being stopped (e.g. a server which waits
+	// for open requests to complete). During a graceful shutdown, the process
+	// runner calls Drain concurrently on every running process before calling
+	// Stop on any process. See WithDrainTimeout.
+	Drainer interface {
+		Drain() error
+	}
+
+	// InFlightReporter is implemented by drainers which can report how much
+	// work remains (e.g. open requests, connections, or unacknowledged messages).
+	// While processes drain, the process runner periodically logs the amount of
+	// remaining work along with an estimate of when draining will complete. See
+	// WithProgressInterval and WithDrainReporter.
+	InFlightReporter interface {
+		InFlight() int
+	}
+
+	// ShutdownAware is implemented by services which should prepare for shutdown
+	// before processes stop (e.g. a connection pool which stops opening new
+	// connections, or a cache which starts flushing asynchronously). During a
+	// graceful shutdown, the process runner calls ShuttingDown on each service
+	// registered to its container which implements this interface before any
+	// process is drained. The method must not block, as the shutdown does not
+	// proceed until it returns.
+	ShutdownAware interface {
+		ShuttingDown()
+	}
+
+	// Finalizer is implemented by processes and initializers which must release
+	// resources (e.g. flush buffers, close connections, or sync logs) once the
+	// application has stopped. The process runner calls Finalize after the Start
+	// method of every process has returned, first on processes in reverse priority
+	// order and then on initializers in reverse order of registration. Only those
+	// whose Init method succeeded are finalized. A replica removed by Scale is
+	// finalized once it has stopped.
+	Finalizer interface {
+		Finalize() error
+	}
+
+	// SelfTester is implemented by processes and initializers which can verify
+	// that they are able to do their work (e.g. by checking connectivity to a
+	// remote service or probing for a required permission) without doing it.
+	// Self-tests are run by the SelfTest method of the process runner, which
+	// is used as a pre-deploy check (see Bootstrapper.SelfTest).
+	SelfTester interface {
+		SelfTest(ctx context.Context) error
+	}
+
+	// Rollbacker is implemented by initializers which acquire resources during
+	// Init (e.g. temporary directories, leader locks, or service registrations)
+	// which must be released if the application fails to start. If the Init
+	// method of an initializer fails, the process runner calls Rollback on each
+	// initializer which has already been initialized, in reverse order of
+	// registration, before any initializer is finalized. The initializer whose
+	// Init method failed is not rolled back.
+	Rollbacker interface {
+		Rollback() error
+	}
+
 	// Initializer is the init-only portion of a Process. This is meant
 	// to do things like setting up global services (e.g. remote connections)
 	// which can be used by processes.
@@ -0,0 +1,198 @@
+package process
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/efritz/glock"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// Command supervises an external executable as part of the application
+	// lifecycle. Each line the executable writes to stdout or stderr is logged
+	// with fields identifying the command and the stream. If the executable
+	// exits, it is restarted according to the configured restart policy. On
+	// shutdown, the executable is sent SIGTERM and is killed if it does not
+	// exit within the shutdown timeout.
+	Command struct {
+		Logger          nacelle.Logger `service:"logger"`
+		configToken     interface{}
+		clock           glock.Clock
+		halt            chan struct{}
+		once            *sync.Once
+		path            string
+		args            []string
+		env             []string
+		restartPolicy   nacelle.RestartPolicy
+		shutdownTimeout time.Duration
+	}
+)
+
+// maxCommandLineSize is the length of the longest line of output which is
+// logged. Output following a longer line is discarded.
+const maxCommandLineSize = 1024 * 1024
+
+var ErrBadCommandConfig = errors.New("command config not registered properly")
+
+func NewCommand(configs ...CommandConfigFunc) *Command {
+	return newCommand(glock.NewRealClock(), configs...)
+}
+
+func newCommand(clock glock.Clock, configs ...CommandConfigFunc) *Command {
+	options := getCommandOptions(configs)
+
+	return &Command{
+		configToken: options.configToken,
+		clock:       clock,
+		halt:        make(chan struct{}),
+		once:        &sync.Once{},
+	}
+}
+
+func (c *Command) Init(config nacelle.Config) error {
+	commandConfig := &CommandConfig{}
+	if err := config.Fetch(c.configToken, commandConfig); err != nil {
+		return ErrBadCommandConfig
+	}
+
+	c.path = commandConfig.CommandPath
+	c.args = commandConfig.CommandArgs
+	c.env = commandConfig.CommandEnv
+	c.restartPolicy = commandConfig.CommandRestartPolicy
+	c.shutdownTimeout = commandConfig.CommandShutdownTimeout
+	c.halt = make(chan struct{})
+	c.once = &sync.Once{}
+	return nil
+}
+
+func (c *Command) Start() error {
+	for {
+		halted, err := c.run()
+		if halted {
+			return nil
+		}
+
+		if err != nil {
+			c.Logger.Warning("Command %s exited with an error (%s)", c.path, err.Error())
+		} else {
+			c.Logger.Info("Command %s exited", c.path)
+		}
+
+		if !c.restartPolicy.ShouldRestart(err) {
+			return err
+		}
+
+		select {
+		case <-c.halt:
+			return nil
+		case <-c.clock.After(c.restartPolicy.Backoff):
+		}
+
+		c.Logger.Info("Restarting command %s", c.path)
+	}
+}
+
+func (c *Command) Stop() error {
+	c.once.Do(func() { close(c.halt) })
+	return nil
+}
+
+// run starts the command and blocks until it exits. Returns true if the
+// command was terminated because the process was stopped.
+func (c *Command) run() (bool, error) {
+	var (
+		stdoutReader, stdoutWriter = io.Pipe()
+		stderrReader, stderrWriter = io.Pipe()
+	)
+
+	cmd := exec.Command(c.path, c.args...)
+	cmd.Env = append(os.Environ(), c.env...)
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+
+	// A child of the command which inherits its output streams would otherwise
+	// block Wait after the command itself has exited
+	cmd.WaitDelay = c.shutdownTimeout
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go c.capture(stdoutReader, "stdout", nacelle.LevelInfo, wg)
+	go c.capture(stderrReader, "stderr", nacelle.LevelWarning, wg)
+
+	closeOutput := func() {
+		stdoutWriter.Close()
+		stderrWriter.Close()
+		wg.Wait()
+	}
+
+	c.Logger.Info("Starting command %s", c.path)
+
+	if err := cmd.Start(); err != nil {
+		closeOutput()
+		return false, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		// Wait returns once all output has been written to the pipes, after
+		// which the remaining output is logged
+		err := cmd.Wait()
+		closeOutput()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return false, err
+	case <-c.halt:
+	}
+
+	c.Logger.Info("Sending SIGTERM to command %s", c.path)
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		cmd.Process.Kill()
+	}
+
+	select {
+	case <-done:
+	case <-c.clock.After(c.shutdownTimeout):
+		c.Logger.Warning("Command %s did not exit within timeout, killing", c.path)
+		cmd.Process.Kill()
+		<-done
+	}
+
+	return true, nil
+}
+
+func (c *Command) capture(r io.Reader, stream string, level nacelle.LogLevel, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	fields := nacelle.Fields{
+		"command": filepath.Base(c.path),
+		"stream":  stream,
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxCommandLineSize)
+
+	for scanner.Scan() {
+		c.Logger.LogWithFields(level, fields, "%s", scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		c.Logger.Warning("Failed to read %s of command %s (%s)", stream, c.path, err.Error())
+
+		// Discard the remaining output so that the command does not block
+		// writing to the pipe
+		io.Copy(io.Discard, r)
+	}
+}
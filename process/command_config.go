@@ -0,0 +1,53 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	CommandConfig struct {
+		CommandPath               string   `env:"command_path" required:"true"`
+		CommandArgs               []string `env:"command_args"`
+		CommandEnv                []string `env:"command_env"`
+		RawCommandRestartPolicy   string   `env:"command_restart_policy" default:"never"`
+		RawCommandRestartDelay    int      `env:"command_restart_delay" default:"1"`
+		RawCommandShutdownTimeout int      `env:"command_shutdown_timeout" default:"10"`
+
+		CommandRestartPolicy   nacelle.RestartPolicy
+		CommandShutdownTimeout time.Duration
+	}
+
+	commandConfigToken string
+)
+
+var (
+	CommandConfigToken      = MakeCommandConfigToken("default")
+	ErrIllegalRestartPolicy = errors.New("illegal command restart policy")
+
+	restartConditions = map[string]nacelle.RestartCondition{
+		"never":      nacelle.RestartNever,
+		"on-failure": nacelle.RestartOnFailure,
+		"always":     nacelle.RestartAlways,
+	}
+)
+
+func MakeCommandConfigToken(name string) interface{} {
+	return commandConfigToken(fmt.Sprintf("nacelle-process-command-%s", name))
+}
+
+func (c *CommandConfig) PostLoad() error {
+	condition, ok := restartConditions[c.RawCommandRestartPolicy]
+	if !ok {
+		return ErrIllegalRestartPolicy
+	}
+
+	// Commands are restarted after a fixed delay without an attempt limit
+	delay := time.Duration(c.RawCommandRestartDelay) * time.Second
+	c.CommandRestartPolicy = nacelle.RestartPolicy{Condition: condition, Backoff: delay, MaxBackoff: delay}
+	c.CommandShutdownTimeout = time.Duration(c.RawCommandShutdownTimeout) * time.Second
+	return nil
+}
@@ -0,0 +1,28 @@
+package process
+
+type (
+	commandOptions struct {
+		configToken interface{}
+	}
+
+	// CommandConfigFunc is a function used to configure an instance of a Command.
+	CommandConfigFunc func(*commandOptions)
+)
+
+// WithCommandConfigToken sets the config token to use. This is useful if an application
+// has multiple Command processes running with different configuration tags.
+func WithCommandConfigToken(token interface{}) CommandConfigFunc {
+	return func(o *commandOptions) { o.configToken = token }
+}
+
+func getCommandOptions(configs []CommandConfigFunc) *commandOptions {
+	options := &commandOptions{
+		configToken: CommandConfigToken,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
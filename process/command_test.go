@@ -0,0 +1,92 @@
+package process
+
+import (
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle/log"
+)
+
+type CommandSuite struct{}
+
+func (s *CommandSuite) TestExit(t sweet.T) {
+	command := makeCommand(glock.NewMockClock())
+
+	err := command.Init(makeConfig(CommandConfigToken, &CommandConfig{
+		CommandPath: "sh",
+		CommandArgs: []string{"-c", "echo hello; echo oops >&2"},
+	}))
+
+	Expect(err).To(BeNil())
+	Expect(command.Start()).To(BeNil())
+}
+
+func (s *CommandSuite) TestExitError(t sweet.T) {
+	command := makeCommand(glock.NewMockClock())
+
+	err := command.Init(makeConfig(CommandConfigToken, &CommandConfig{
+		CommandPath: "sh",
+		CommandArgs: []string{"-c", "exit 3"},
+	}))
+
+	Expect(err).To(BeNil())
+	Expect(command.Start()).To(MatchError("exit status 3"))
+}
+
+func (s *CommandSuite) TestLongLine(t sweet.T) {
+	command := makeCommand(glock.NewMockClock())
+
+	err := command.Init(makeConfig(CommandConfigToken, &CommandConfig{
+		CommandPath: "sh",
+		CommandArgs: []string{"-c", "head -c 2000000 /dev/zero | tr '\\0' a; echo; echo done"},
+	}))
+
+	// Output following a line which is too long to log is discarded
+	Expect(err).To(BeNil())
+	Expect(command.Start()).To(BeNil())
+}
+
+func (s *CommandSuite) TestStop(t sweet.T) {
+	var (
+		command = makeCommand(glock.NewRealClock())
+		errChan = make(chan error)
+	)
+
+	err := command.Init(makeConfig(CommandConfigToken, &CommandConfig{
+		CommandPath: "sleep",
+		CommandArgs: []string{"30"},
+	}))
+
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- command.Start()
+	}()
+
+	Consistently(errChan, time.Millisecond*100).ShouldNot(Receive())
+	command.Stop()
+	Eventually(errChan, time.Second*5).Should(Receive(BeNil()))
+}
+
+func (s *CommandSuite) TestIllegalRestartPolicy(t sweet.T) {
+	c := &CommandConfig{RawCommandRestartPolicy: "sometimes"}
+	Expect(c.PostLoad()).To(Equal(ErrIllegalRestartPolicy))
+}
+
+func (s *CommandSuite) TestBadConfig(t sweet.T) {
+	command := NewCommand()
+	err := command.Init(makeConfig(CommandConfigToken, &emptyConfig{}))
+	Expect(err).To(Equal(ErrBadCommandConfig))
+}
+
+//
+// Helpers
+
+func makeCommand(clock glock.Clock) *Command {
+	command := newCommand(clock)
+	command.Logger = log.NewNilLogger()
+	return command
+}
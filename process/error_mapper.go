@@ -0,0 +1,266 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	// ErrorKind classifies an application error independently of the protocol
+	// over which it is reported.
+	ErrorKind string
+
+	// AppError is an error with a kind which an ErrorMapper translates into a
+	// protocol-specific status.
+	AppError struct {
+		Kind    ErrorKind
+		Message string
+		Err     error
+	}
+
+	// ErrorMapping describes how errors of a kind are reported.
+	ErrorMapping struct {
+		HTTPStatus int
+		GRPCCode   codes.Code
+		Title      string
+	}
+
+	// ErrorMapper translates application errors into HTTP statuses, gRPC codes,
+	// and problem+json bodies, so that error translation is defined once per
+	// application rather than once per handler. The mapper is safe for concurrent
+	// use. The gRPC server translates the errors returned by its handlers with
+	// the mapper registered to the service container under the key error-mapper,
+	// if any. HTTP handlers opt in by being wrapped with Handler.
+	ErrorMapper struct {
+		mutex    sync.RWMutex
+		kinds    map[ErrorKind]ErrorMapping
+		targets  []errorTarget
+		fallback ErrorMapping
+	}
+
+	errorTarget struct {
+		target  error
+		mapping ErrorMapping
+	}
+
+	// Problem is a problem details body as described by RFC 7807.
+	Problem struct {
+		Type   string `json:"type,omitempty"`
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+		Detail string `json:"detail,omitempty"`
+
+		// Errors describes each invalid field of a request which could not be
+		// bound (see Bind).
+		Errors []FieldError `json:"errors,omitempty"`
+	}
+
+	// ErrorHandlerFunc is an HTTP handler which returns an error in place of
+	// writing an error response (see ErrorMapper.Handler).
+	ErrorHandlerFunc func(http.ResponseWriter, *http.Request) error
+)
+
+const (
+	KindInvalid          = ErrorKind("invalid")
+	KindNotFound         = ErrorKind("not-found")
+	KindConflict         = ErrorKind("conflict")
+	KindUnauthenticated  = ErrorKind("unauthenticated")
+	KindPermissionDenied = ErrorKind("permission-denied")
+	KindRateLimited      = ErrorKind("rate-limited")
+	KindUnavailable      = ErrorKind("unavailable")
+	KindTimeout          = ErrorKind("timeout")
+	KindInternal         = ErrorKind("internal")
+
+	// ErrorMapperServiceKey is the key of the error mapper used by the gRPC server.
+	ErrorMapperServiceKey = "error-mapper"
+
+	problemContentType = "application/problem+json"
+
+	// statusClientClosedRequest is the non-standard status commonly reported
+	// when a client cancels a request before it is served.
+	statusClientClosedRequest = 499
+)
+
+var defaultErrorMappings = map[ErrorKind]ErrorMapping{
+	KindInvalid:          {http.StatusBadRequest, codes.InvalidArgument, "Invalid request"},
+	KindNotFound:         {http.StatusNotFound, codes.NotFound, "Not found"},
+	KindConflict:         {http.StatusConflict, codes.AlreadyExists, "Conflict"},
+	KindUnauthenticated:  {http.StatusUnauthorized, codes.Unauthenticated, "Unauthenticated"},
+	KindPermissionDenied: {http.StatusForbidden, codes.PermissionDenied, "Permission denied"},
+	KindRateLimited:      {http.StatusTooManyRequests, codes.ResourceExhausted, "Rate limited"},
+	KindUnavailable:      {http.StatusServiceUnavailable, codes.Unavailable, "Unavailable"},
+	KindTimeout:          {http.StatusGatewayTimeout, codes.DeadlineExceeded, "Timeout"},
+	KindInternal:         {http.StatusInternalServerError, codes.Internal, "Internal error"},
+}
+
+// NewError creates an application error of the given kind.
+func NewError(kind ErrorKind, format string, args ...interface{}) error {
+	return &AppError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// WrapError creates an application error of the given kind which wraps the given
+// error. The message of the application error is the message of the wrapped error.
+func WrapError(kind ErrorKind, err error) error {
+	return &AppError{Kind: kind, Message: err.Error(), Err: err}
+}
+
+func (e *AppError) Error() string {
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// NewErrorMapper creates an error mapper with a mapping for each of the error
+// kinds defined by this package. Errors which match no mapping are reported as
+// internal errors. Context deadline errors are reported as timeouts and context
+// cancellation errors as cancelled requests.
+func NewErrorMapper() *ErrorMapper {
+	m := &ErrorMapper{
+		kinds:    map[ErrorKind]ErrorMapping{},
+		fallback: defaultErrorMappings[KindInternal],
+	}
+
+	for kind, mapping := range defaultErrorMappings {
+		m.kinds[kind] = mapping
+	}
+
+	m.RegisterError(context.DeadlineExceeded, defaultErrorMappings[KindTimeout])
+	m.RegisterError(context.Canceled, ErrorMapping{statusClientClosedRequest, codes.Canceled, "Canceled"})
+	return m
+}
+
+// RegisterKind sets the mapping of an error kind, replacing any existing mapping.
+func (m *ErrorMapper) RegisterKind(kind ErrorKind, mapping ErrorMapping) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.kinds[kind] = mapping
+}
+
+// RegisterError sets the mapping of errors which match the given target error (as
+// determined by errors.Is). Target errors take precedence over error kinds and are
+// checked in the reverse order of registration, so a later registration overrides
+// an earlier one.
+func (m *ErrorMapper) RegisterError(target error, mapping ErrorMapping) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.targets = append(m.targets, errorTarget{target: target, mapping: mapping})
+}
+
+// Map returns the mapping of the given error.
+func (m *ErrorMapper) Map(err error) ErrorMapping {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for i := len(m.targets) - 1; i >= 0; i-- {
+		if errors.Is(err, m.targets[i].target) {
+			return m.targets[i].mapping
+		}
+	}
+
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		if mapping, ok := m.kinds[appErr.Kind]; ok {
+			return mapping
+		}
+	}
+
+	return m.fallback
+}
+
+// HTTPStatus returns the HTTP status of the given error.
+func (m *ErrorMapper) HTTPStatus(err error) int {
+	return m.Map(err).HTTPStatus
+}
+
+// GRPCError returns a gRPC status error with the code of the given error. An
+// error which already carries a gRPC status is returned unchanged.
+func (m *ErrorMapper) GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.Error(m.Map(err).GRPCCode, m.detail(err))
+}
+
+// Problem returns the problem details body of the given error.
+func (m *ErrorMapper) Problem(err error) Problem {
+	mapping := m.Map(err)
+
+	problem := Problem{
+		Title:  mapping.Title,
+		Status: mapping.HTTPStatus,
+		Detail: m.detail(err),
+	}
+
+	var bindErr *BindError
+	if errors.As(err, &bindErr) {
+		problem.Errors = bindErr.Fields
+	}
+
+	return problem
+}
+
+// WriteProblem writes the problem details body of the given error to the given
+// response writer with the content type application/problem+json.
+func (m *ErrorMapper) WriteProblem(w http.ResponseWriter, err error) {
+	problem := m.Problem(err)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// Handler creates an HTTP handler which calls the given function and writes the
+// problem details body of the error it returns, if any.
+func (m *ErrorMapper) Handler(f ErrorHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := f(w, r); err != nil {
+			m.WriteProblem(w, err)
+		}
+	})
+}
+
+// UnaryServerInterceptor creates a gRPC interceptor which translates the errors
+// returned by unary handlers.
+func (m *ErrorMapper) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, m.GRPCError(err)
+	}
+}
+
+// StreamServerInterceptor creates a gRPC interceptor which translates the errors
+// returned by stream handlers.
+func (m *ErrorMapper) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return m.GRPCError(handler(srv, stream))
+	}
+}
+
+// detail returns the message of an application error, which is written by the
+// application to be shown to a client. The messages of other errors may expose
+// internal details, so the title of the mapping is used instead.
+func (m *ErrorMapper) detail(err error) string {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+
+	return m.Map(err).Title
+}
@@ -0,0 +1,91 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ErrorMapperSuite struct{}
+
+func (s *ErrorMapperSuite) TestMap(t sweet.T) {
+	mapper := NewErrorMapper()
+
+	Expect(mapper.HTTPStatus(NewError(KindNotFound, "no user %d", 42))).To(Equal(http.StatusNotFound))
+	Expect(mapper.HTTPStatus(fmt.Errorf("wrapped (%w)", NewError(KindConflict, "utoh")))).To(Equal(http.StatusConflict))
+	Expect(mapper.HTTPStatus(NewError(ErrorKind("unknown"), "utoh"))).To(Equal(http.StatusInternalServerError))
+	Expect(mapper.HTTPStatus(errors.New("utoh"))).To(Equal(http.StatusInternalServerError))
+	Expect(mapper.HTTPStatus(context.DeadlineExceeded)).To(Equal(http.StatusGatewayTimeout))
+	Expect(mapper.Map(context.Canceled).GRPCCode).To(Equal(codes.Canceled))
+}
+
+func (s *ErrorMapperSuite) TestRegister(t sweet.T) {
+	var (
+		mapper   = NewErrorMapper()
+		errGone  = errors.New("gone")
+		mapping1 = ErrorMapping{http.StatusGone, codes.NotFound, "Gone"}
+		mapping2 = ErrorMapping{http.StatusTeapot, codes.Unknown, "Teapot"}
+	)
+
+	mapper.RegisterError(errGone, mapping1)
+	mapper.RegisterKind(KindInvalid, mapping2)
+
+	Expect(mapper.Map(fmt.Errorf("wrapped (%w)", errGone))).To(Equal(mapping1))
+	Expect(mapper.Map(NewError(KindInvalid, "utoh"))).To(Equal(mapping2))
+
+	// Target errors take precedence over kinds
+	Expect(mapper.Map(WrapError(KindInvalid, errGone))).To(Equal(mapping1))
+}
+
+func (s *ErrorMapperSuite) TestGRPCError(t sweet.T) {
+	mapper := NewErrorMapper()
+	Expect(mapper.GRPCError(nil)).To(BeNil())
+
+	st, _ := status.FromError(mapper.GRPCError(NewError(KindPermissionDenied, "no access to %s", "foo")))
+	Expect(st.Code()).To(Equal(codes.PermissionDenied))
+	Expect(st.Message()).To(Equal("no access to foo"))
+
+	// Messages of other errors are not exposed
+	st, _ = status.FromError(mapper.GRPCError(errors.New("secret")))
+	Expect(st.Code()).To(Equal(codes.Internal))
+	Expect(st.Message()).To(Equal("Internal error"))
+
+	err := status.Error(codes.Aborted, "aborted")
+	Expect(mapper.GRPCError(err)).To(BeIdenticalTo(err))
+}
+
+func (s *ErrorMapperSuite) TestHandler(t sweet.T) {
+	handler := NewErrorMapper().Handler(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+
+		return NewError(KindNotFound, "no page %s", r.URL.Path)
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/ok", nil))
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/missing", nil))
+	Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	Expect(recorder.Header().Get("Content-Type")).To(Equal("application/problem+json"))
+
+	problem := Problem{}
+	Expect(json.Unmarshal(recorder.Body.Bytes(), &problem)).To(BeNil())
+	Expect(problem).To(Equal(Problem{
+		Title:  "Not found",
+		Status: http.StatusNotFound,
+		Detail: "no page /missing",
+	}))
+}
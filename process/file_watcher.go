@@ -0,0 +1,146 @@
+package process
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// FileWatcher watches a set of configured paths for changes and invokes a
+	// handler with the set of changed paths. Events are debounced so that a
+	// burst of writes to the same files results in a single handler call.
+	FileWatcher struct {
+		Logger      nacelle.Logger            `service:"logger"`
+		Container   *nacelle.ServiceContainer `service:"container"`
+		configToken interface{}
+		handler     FileWatcherHandler
+		clock       glock.Clock
+		watcher     *fsnotify.Watcher
+		paths       []string
+		debounce    time.Duration
+		halt        chan struct{}
+		once        *sync.Once
+	}
+
+	// FileWatcherHandler is invoked by a FileWatcher with changed paths.
+	FileWatcherHandler interface {
+		Init(nacelle.Config, *FileWatcher) error
+
+		// Handle is called with the sorted set of paths which changed during
+		// the last debounce window. An error returned from this method is
+		// fatal to the watcher.
+		Handle(paths []string) error
+	}
+)
+
+var ErrBadFileWatcherConfig = errors.New("file watcher config not registered properly")
+
+func NewFileWatcher(handler FileWatcherHandler, configs ...FileWatcherConfigFunc) *FileWatcher {
+	return newFileWatcher(handler, glock.NewRealClock(), configs...)
+}
+
+func newFileWatcher(handler FileWatcherHandler, clock glock.Clock, configs ...FileWatcherConfigFunc) *FileWatcher {
+	options := getFileWatcherOptions(configs)
+
+	return &FileWatcher{
+		configToken: options.configToken,
+		handler:     handler,
+		clock:       clock,
+		halt:        make(chan struct{}),
+		once:        &sync.Once{},
+	}
+}
+
+func (w *FileWatcher) Init(config nacelle.Config) (err error) {
+	watcherConfig := &FileWatcherConfig{}
+	if err = config.Fetch(w.configToken, watcherConfig); err != nil {
+		return ErrBadFileWatcherConfig
+	}
+
+	w.paths = watcherConfig.FileWatcherPaths
+	w.debounce = watcherConfig.FileWatcherDebounce
+	w.halt = make(chan struct{})
+	w.once = &sync.Once{}
+
+	w.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range w.paths {
+		if err := w.watcher.Add(path); err != nil {
+			w.watcher.Close()
+			return err
+		}
+	}
+
+	if err := w.Container.Inject(w.handler); err != nil {
+		w.watcher.Close()
+		return err
+	}
+
+	return w.handler.Init(config, w)
+}
+
+func (w *FileWatcher) Start() error {
+	defer w.watcher.Close()
+
+	var (
+		changed = map[string]struct{}{}
+		timer   <-chan time.Time
+	)
+
+	w.Logger.Info("Watching %d paths for changes", len(w.paths))
+
+	for {
+		select {
+		case <-w.halt:
+			w.Logger.Info("No longer watching paths for changes")
+			return nil
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if len(changed) == 0 {
+				timer = w.clock.After(w.debounce)
+			}
+
+			changed[event.Name] = struct{}{}
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			w.Logger.Warning("Error watching paths for changes (%s)", err.Error())
+
+		case <-timer:
+			paths := []string{}
+			for path := range changed {
+				paths = append(paths, path)
+			}
+
+			sort.Strings(paths)
+			changed = map[string]struct{}{}
+			timer = nil
+
+			if err := w.handler.Handle(paths); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *FileWatcher) Stop() error {
+	w.once.Do(func() { close(w.halt) })
+	return nil
+}
@@ -0,0 +1,28 @@
+package process
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	FileWatcherConfig struct {
+		FileWatcherPaths         []string `env:"file_watcher_paths" required:"true"`
+		RawFileWatcherDebounceMS int      `env:"file_watcher_debounce_ms" default:"250"`
+
+		FileWatcherDebounce time.Duration
+	}
+
+	fileWatcherConfigToken string
+)
+
+var FileWatcherConfigToken = MakeFileWatcherConfigToken("default")
+
+func MakeFileWatcherConfigToken(name string) interface{} {
+	return fileWatcherConfigToken(fmt.Sprintf("nacelle-process-file-watcher-%s", name))
+}
+
+func (c *FileWatcherConfig) PostLoad() error {
+	c.FileWatcherDebounce = time.Duration(c.RawFileWatcherDebounceMS) * time.Millisecond
+	return nil
+}
@@ -0,0 +1,28 @@
+package process
+
+type (
+	fileWatcherOptions struct {
+		configToken interface{}
+	}
+
+	// FileWatcherConfigFunc is a function used to configure an instance of a FileWatcher.
+	FileWatcherConfigFunc func(*fileWatcherOptions)
+)
+
+// WithFileWatcherConfigToken sets the config token to use. This is useful if an application
+// has multiple FileWatcher processes running with different configuration tags.
+func WithFileWatcherConfigToken(token interface{}) FileWatcherConfigFunc {
+	return func(o *fileWatcherOptions) { o.configToken = token }
+}
+
+func getFileWatcherOptions(configs []FileWatcherConfigFunc) *fileWatcherOptions {
+	options := &fileWatcherOptions{
+		configToken: FileWatcherConfigToken,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
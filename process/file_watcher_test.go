@@ -0,0 +1,136 @@
+package process
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+	"github.com/efritz/nacelle/log"
+)
+
+type FileWatcherSuite struct{}
+
+func (s *FileWatcherSuite) TestWatch(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-file-watcher")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		handler = newMockFileWatcherHandler()
+		clock   = glock.NewMockClock()
+		watcher = makeFileWatcher(handler, clock)
+		errChan = make(chan error)
+	)
+
+	err = watcher.Init(makeConfig(FileWatcherConfigToken, &FileWatcherConfig{FileWatcherPaths: []string{dir}}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- watcher.Start()
+	}()
+
+	Expect(ioutil.WriteFile(filepath.Join(dir, "a"), []byte("a"), 0644)).To(BeNil())
+	Expect(ioutil.WriteFile(filepath.Join(dir, "b"), []byte("b"), 0644)).To(BeNil())
+
+	Eventually(func() bool {
+		clock.Advance(time.Second)
+
+		select {
+		case paths := <-handler.paths:
+			Expect(paths).To(ContainElement(filepath.Join(dir, "a")))
+			return true
+		default:
+			return false
+		}
+	}).Should(BeTrue())
+
+	watcher.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *FileWatcherSuite) TestHandlerError(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-file-watcher")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		handler = newMockFileWatcherHandler()
+		clock   = glock.NewMockClock()
+		watcher = makeFileWatcher(handler, clock)
+		errChan = make(chan error)
+	)
+
+	handler.err = errors.New("utoh")
+
+	err = watcher.Init(makeConfig(FileWatcherConfigToken, &FileWatcherConfig{FileWatcherPaths: []string{dir}}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- watcher.Start()
+	}()
+
+	Expect(ioutil.WriteFile(filepath.Join(dir, "a"), []byte("a"), 0644)).To(BeNil())
+
+	Eventually(func() error {
+		clock.Advance(time.Second)
+
+		select {
+		case err := <-errChan:
+			return err
+		default:
+			return nil
+		}
+	}).Should(MatchError("utoh"))
+}
+
+func (s *FileWatcherSuite) TestMissingPath(t sweet.T) {
+	watcher := makeFileWatcher(newMockFileWatcherHandler(), glock.NewMockClock())
+	err := watcher.Init(makeConfig(FileWatcherConfigToken, &FileWatcherConfig{FileWatcherPaths: []string{"/does/not/exist"}}))
+	Expect(err).NotTo(BeNil())
+}
+
+func (s *FileWatcherSuite) TestBadConfig(t sweet.T) {
+	watcher := NewFileWatcher(newMockFileWatcherHandler())
+	err := watcher.Init(makeConfig(FileWatcherConfigToken, &emptyConfig{}))
+	Expect(err).To(Equal(ErrBadFileWatcherConfig))
+}
+
+//
+// Helpers
+
+func makeFileWatcher(handler FileWatcherHandler, clock glock.Clock) *FileWatcher {
+	watcher := newFileWatcher(handler, clock)
+	watcher.Logger = log.NewNilLogger()
+	watcher.Container = nacelle.NewServiceContainer()
+	return watcher
+}
+
+//
+// Mocks
+
+type mockFileWatcherHandler struct {
+	paths chan []string
+	err   error
+}
+
+func newMockFileWatcherHandler() *mockFileWatcherHandler {
+	return &mockFileWatcherHandler{
+		paths: make(chan []string, 10),
+	}
+}
+
+func (h *mockFileWatcherHandler) Init(config nacelle.Config, watcher *FileWatcher) error {
+	return nil
+}
+
+func (h *mockFileWatcherHandler) Handle(paths []string) error {
+	h.paths <- paths
+	return h.err
+}
@@ -1,11 +1,15 @@
 package process
 
 import (
+	"context"
 	"errors"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/efritz/nacelle"
 )
@@ -14,6 +18,8 @@ type (
 	GRPCServer struct {
 		Logger        nacelle.Logger            `service:"logger"`
 		Container     *nacelle.ServiceContainer `service:"container"`
+		Ports         *nacelle.Ports            `service:"ports" optional:"true"`
+		ErrorMapper   *ErrorMapper              `service:"error-mapper" optional:"true"`
 		configToken   interface{}
 		initializer   GRPCServerInitializer
 		listener      *net.TCPListener
@@ -21,6 +27,8 @@ type (
 		once          *sync.Once
 		port          int
 		serverOptions []grpc.ServerOption
+		timeouts      nacelle.Timeouts
+		inFlight      int64
 	}
 
 	GRPCServerInitializer interface {
@@ -53,7 +61,7 @@ func (s *GRPCServer) Init(config nacelle.Config) (err error) {
 		return ErrBadGRPCConfig
 	}
 
-	s.listener, err = makeListener(grpcConfig.GRPCPort)
+	s.listener, err = makeListener(s.Ports, s.configToken, "", grpcConfig.GRPCPort)
 	if err != nil {
 		return
 	}
@@ -62,8 +70,10 @@ func (s *GRPCServer) Init(config nacelle.Config) (err error) {
 		return err
 	}
 
-	s.port = grpcConfig.GRPCPort
-	s.server = grpc.NewServer(s.serverOptions...)
+	s.timeouts = nacelle.ResolveTimeouts(s.Container, grpcConfig.Timeouts)
+	s.port = s.listener.Addr().(*net.TCPAddr).Port
+	s.server = grpc.NewServer(s.getServerOptions()...)
+	s.once = &sync.Once{}
 	err = s.initializer.Init(config, s.server)
 	return
 }
@@ -81,11 +91,94 @@ func (s *GRPCServer) Start() error {
 	return nil
 }
 
+// Drain stops the server from accepting new connections and blocks until all
+// pending RPCs have completed or the shutdown timeout elapses.
+func (s *GRPCServer) Drain() error {
+	return s.Stop()
+}
+
+// InFlight returns the number of RPCs currently being served.
+func (s *GRPCServer) InFlight() int {
+	return int(atomic.LoadInt64(&s.inFlight))
+}
+
 func (s *GRPCServer) Stop() error {
 	s.once.Do(func() {
 		s.Logger.Info("Shutting down gRPC server")
-		s.server.GracefulStop()
+		s.stop()
 	})
 
 	return nil
 }
+
+// stop gracefully stops the server. If the pending RPCs do not finish within
+// the shutdown timeout, the server is stopped forcefully.
+func (s *GRPCServer) stop() {
+	if s.timeouts.Shutdown == 0 {
+		s.server.GracefulStop()
+		return
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		s.server.GracefulStop()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(s.timeouts.Shutdown):
+		s.Logger.Warning("gRPC server did not stop gracefully within %s", s.timeouts.Shutdown)
+		s.server.Stop()
+		<-done
+	}
+}
+
+// getServerOptions returns the configured server options along with options which
+// apply the resolved timeouts, interceptors which track the number of RPCs being
+// served, and interceptors which translate handler errors if an error mapper has
+// been injected. The configured options are applied last so that they take
+// precedence.
+func (s *GRPCServer) getServerOptions() []grpc.ServerOption {
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.countUnaryInFlight),
+		grpc.ChainStreamInterceptor(s.countStreamInFlight),
+	}
+
+	if s.timeouts.Dial != 0 {
+		options = append(options, grpc.ConnectionTimeout(s.timeouts.Dial))
+	}
+
+	if s.timeouts.Idle != 0 {
+		options = append(options, grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: s.timeouts.Idle,
+		}))
+	}
+
+	options = append(options, s.serverOptions...)
+
+	if s.ErrorMapper == nil {
+		return options
+	}
+
+	return append(
+		options,
+		grpc.ChainUnaryInterceptor(s.ErrorMapper.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(s.ErrorMapper.StreamServerInterceptor()),
+	)
+}
+
+func (s *GRPCServer) countUnaryInFlight(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	atomic.AddInt64(&s.inFlight, 1)
+	defer atomic.AddInt64(&s.inFlight, -1)
+
+	return handler(ctx, req)
+}
+
+func (s *GRPCServer) countStreamInFlight(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	atomic.AddInt64(&s.inFlight, 1)
+	defer atomic.AddInt64(&s.inFlight, -1)
+
+	return handler(srv, stream)
+}
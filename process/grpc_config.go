@@ -2,11 +2,19 @@ package process
 
 import (
 	"fmt"
+
+	"github.com/efritz/nacelle"
 )
 
 type (
 	GRPCConfig struct {
-		GRPCPort int `env:"grpc_port" default:"6000"`
+		GRPCPort           int `env:"grpc_port" default:"6000"`
+		RawIdleTimeout     int `env:"grpc_idle_timeout"`
+		RawShutdownTimeout int `env:"grpc_shutdown_timeout"`
+
+		// Timeouts holds the gRPC-specific overrides of the shared
+		// timeouts. A zero value inherits the shared timeout.
+		Timeouts nacelle.Timeouts
 	}
 
 	grpcConfigToken string
@@ -17,3 +25,13 @@ var GRPCConfigToken = MakeGRPCConfigToken("default")
 func MakeGRPCConfigToken(name string) interface{} {
 	return grpcConfigToken(fmt.Sprintf("nacelle-process-grpc-%s", name))
 }
+
+func (c *GRPCConfig) PostLoad() error {
+	timeouts, err := nacelle.MakeTimeouts(0, 0, 0, c.RawIdleTimeout, c.RawShutdownTimeout)
+	if err != nil {
+		return err
+	}
+
+	c.Timeouts = timeouts
+	return nil
+}
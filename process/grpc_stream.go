@@ -0,0 +1,187 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/efritz/glock"
+	"google.golang.org/grpc"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// GRPCStreamClient maintains a long-lived streaming gRPC client connection.
+	// A stream is opened by the spec and each message received on the stream is
+	// dispatched to the spec's handler. If the stream is closed or fails, it is
+	// re-opened after an exponential backoff.
+	GRPCStreamClient struct {
+		Logger         nacelle.Logger            `service:"logger"`
+		Container      *nacelle.ServiceContainer `service:"container"`
+		configToken    interface{}
+		spec           GRPCStreamSpec
+		clock          glock.Clock
+		dialOptions    []grpc.DialOption
+		conn           *grpc.ClientConn
+		ctx            context.Context
+		cancel         context.CancelFunc
+		addr           string
+		initialBackoff time.Duration
+		maxBackoff     time.Duration
+		messages       uint64
+		subscriptions  uint64
+		failures       uint64
+	}
+
+	// GRPCStreamSpec opens streams and handles the messages received on them.
+	GRPCStreamSpec interface {
+		Init(nacelle.Config, *GRPCStreamClient) error
+
+		// Subscribe opens a stream on the given connection and returns a
+		// function which blocks until the next message of the stream is
+		// received. The given context is cancelled when the client stops.
+		Subscribe(context.Context, *grpc.ClientConn) (GRPCRecvFunc, error)
+
+		// Handle processes a message received on the stream. An error
+		// returned from this method is fatal to the client.
+		Handle(context.Context, interface{}) error
+	}
+
+	// GRPCRecvFunc receives the next message of a stream.
+	GRPCRecvFunc func() (interface{}, error)
+
+	// GRPCStreamStats is a snapshot of the counters of a GRPC stream client.
+	GRPCStreamStats struct {
+		Messages      uint64
+		Subscriptions uint64
+		Failures      uint64
+	}
+)
+
+var ErrBadGRPCStreamConfig = errors.New("gRPC stream config not registered properly")
+
+func NewGRPCStreamClient(spec GRPCStreamSpec, configs ...GRPCStreamClientConfigFunc) *GRPCStreamClient {
+	return newGRPCStreamClient(spec, glock.NewRealClock(), configs...)
+}
+
+func newGRPCStreamClient(spec GRPCStreamSpec, clock glock.Clock, configs ...GRPCStreamClientConfigFunc) *GRPCStreamClient {
+	options := getGRPCStreamOptions(configs)
+
+	return &GRPCStreamClient{
+		configToken: options.configToken,
+		spec:        spec,
+		clock:       clock,
+		dialOptions: options.dialOptions,
+	}
+}
+
+func (c *GRPCStreamClient) Init(config nacelle.Config) (err error) {
+	streamConfig := &GRPCStreamConfig{}
+	if err = config.Fetch(c.configToken, streamConfig); err != nil {
+		return ErrBadGRPCStreamConfig
+	}
+
+	c.addr = streamConfig.GRPCStreamAddr
+	c.initialBackoff = streamConfig.GRPCStreamInitialBackoff
+	c.maxBackoff = streamConfig.GRPCStreamMaxBackoff
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	c.conn, err = grpc.Dial(c.addr, c.dialOptions...)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Container.Inject(c.spec); err != nil {
+		return err
+	}
+
+	return c.spec.Init(config, c)
+}
+
+func (c *GRPCStreamClient) Start() error {
+	defer c.conn.Close()
+
+	backoff := c.initialBackoff
+
+	for {
+		received, err := c.consume()
+		if err != nil {
+			return err
+		}
+
+		if received {
+			backoff = c.initialBackoff
+		}
+
+		select {
+		case <-c.ctx.Done():
+			c.Logger.Info("No longer consuming gRPC stream from %s", c.addr)
+			return nil
+		case <-c.clock.After(backoff):
+		}
+
+		if backoff *= 2; backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// consume opens a stream and dispatches its messages until the stream fails.
+// Returns true if any message was received. An error is returned only if the
+// handler fails.
+func (c *GRPCStreamClient) consume() (bool, error) {
+	atomic.AddUint64(&c.subscriptions, 1)
+
+	recv, err := c.spec.Subscribe(c.ctx, c.conn)
+	if err != nil {
+		atomic.AddUint64(&c.failures, 1)
+		c.logFailure("Failed to open gRPC stream to %s (%s)", err)
+		return false, nil
+	}
+
+	c.Logger.Info("Consuming gRPC stream from %s", c.addr)
+
+	received := false
+	for {
+		message, err := recv()
+		if err != nil {
+			atomic.AddUint64(&c.failures, 1)
+			c.logFailure("gRPC stream from %s closed (%s)", err)
+			return received, nil
+		}
+
+		received = true
+		atomic.AddUint64(&c.messages, 1)
+
+		if err := c.spec.Handle(c.ctx, message); err != nil {
+			return received, err
+		}
+	}
+}
+
+func (c *GRPCStreamClient) logFailure(format string, err error) {
+	if c.ctx.Err() != nil {
+		return
+	}
+
+	c.Logger.Warning(format, c.addr, err.Error())
+}
+
+func (c *GRPCStreamClient) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	return nil
+}
+
+// Stats returns the current counters of the client.
+func (c *GRPCStreamClient) Stats() GRPCStreamStats {
+	return GRPCStreamStats{
+		Messages:      atomic.LoadUint64(&c.messages),
+		Subscriptions: atomic.LoadUint64(&c.subscriptions),
+		Failures:      atomic.LoadUint64(&c.failures),
+	}
+}
@@ -0,0 +1,39 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type (
+	GRPCStreamConfig struct {
+		GRPCStreamAddr              string `env:"grpc_stream_addr" required:"true"`
+		RawGRPCStreamInitialBackoff int    `env:"grpc_stream_initial_backoff" default:"1"`
+		RawGRPCStreamMaxBackoff     int    `env:"grpc_stream_max_backoff" default:"30"`
+
+		GRPCStreamInitialBackoff time.Duration
+		GRPCStreamMaxBackoff     time.Duration
+	}
+
+	grpcStreamConfigToken string
+)
+
+var (
+	GRPCStreamConfigToken = MakeGRPCStreamConfigToken("default")
+	ErrBadBackoffConfig   = errors.New("initial backoff must not be greater than max backoff")
+)
+
+func MakeGRPCStreamConfigToken(name string) interface{} {
+	return grpcStreamConfigToken(fmt.Sprintf("nacelle-process-grpc-stream-%s", name))
+}
+
+func (c *GRPCStreamConfig) PostLoad() error {
+	if c.RawGRPCStreamInitialBackoff > c.RawGRPCStreamMaxBackoff {
+		return ErrBadBackoffConfig
+	}
+
+	c.GRPCStreamInitialBackoff = time.Duration(c.RawGRPCStreamInitialBackoff) * time.Second
+	c.GRPCStreamMaxBackoff = time.Duration(c.RawGRPCStreamMaxBackoff) * time.Second
+	return nil
+}
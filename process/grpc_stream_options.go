@@ -0,0 +1,42 @@
+package process
+
+import "google.golang.org/grpc"
+
+type (
+	grpcStreamOptions struct {
+		configToken interface{}
+		dialOptions []grpc.DialOption
+	}
+
+	// GRPCStreamClientConfigFunc is a function used to configure an instance
+	// of a GRPC stream client.
+	GRPCStreamClientConfigFunc func(*grpcStreamOptions)
+)
+
+// WithGRPCStreamConfigToken sets the config token to use. This is useful if an application
+// has multiple GRPC stream clients running with different configuration tags.
+func WithGRPCStreamConfigToken(token interface{}) GRPCStreamClientConfigFunc {
+	return func(o *grpcStreamOptions) { o.configToken = token }
+}
+
+// WithGRPCDialOptions sets grpc options on the underlying client connection. If
+// no options are supplied, the connection is made with grpc.WithInsecure.
+func WithGRPCDialOptions(options ...grpc.DialOption) GRPCStreamClientConfigFunc {
+	return func(o *grpcStreamOptions) { o.dialOptions = append(o.dialOptions, options...) }
+}
+
+func getGRPCStreamOptions(configs []GRPCStreamClientConfigFunc) *grpcStreamOptions {
+	options := &grpcStreamOptions{
+		configToken: GRPCStreamConfigToken,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	if len(options.dialOptions) == 0 {
+		options.dialOptions = []grpc.DialOption{grpc.WithInsecure()}
+	}
+
+	return options
+}
@@ -0,0 +1,135 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/efritz/nacelle"
+	"github.com/efritz/nacelle/log"
+)
+
+type GRPCStreamSuite struct{}
+
+func (s *GRPCStreamSuite) TestConsumeAndResubscribe(t sweet.T) {
+	var (
+		spec     = newMockStreamSpec()
+		clock    = glock.NewMockClock()
+		client   = makeGRPCStreamClient(spec, clock)
+		messages = make(chan interface{}, 10)
+		errChan  = make(chan error)
+	)
+
+	spec.handle = func(ctx context.Context, message interface{}) error {
+		messages <- message
+		return nil
+	}
+
+	err := client.Init(makeConfig(GRPCStreamConfigToken, &GRPCStreamConfig{
+		GRPCStreamAddr: "localhost:0",
+	}))
+
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- client.Start()
+	}()
+
+	spec.stream <- "a"
+	spec.stream <- "b"
+	Eventually(messages).Should(Receive(Equal("a")))
+	Eventually(messages).Should(Receive(Equal("b")))
+
+	// Stream fails, resubscribe after backoff
+	spec.stream <- errors.New("disconnected")
+	Eventually(func() uint64 { return client.Stats().Failures }).Should(Equal(uint64(1)))
+	clock.BlockingAdvance(time.Second)
+	Eventually(func() uint64 { return client.Stats().Subscriptions }).Should(Equal(uint64(2)))
+
+	spec.stream <- "c"
+	Eventually(messages).Should(Receive(Equal("c")))
+	Expect(client.Stats().Messages).To(Equal(uint64(3)))
+
+	client.Stop()
+	spec.stream <- errors.New("cancelled")
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *GRPCStreamSuite) TestHandlerError(t sweet.T) {
+	var (
+		spec    = newMockStreamSpec()
+		client  = makeGRPCStreamClient(spec, glock.NewMockClock())
+		errChan = make(chan error)
+	)
+
+	spec.handle = func(ctx context.Context, message interface{}) error {
+		return errors.New("utoh")
+	}
+
+	err := client.Init(makeConfig(GRPCStreamConfigToken, &GRPCStreamConfig{
+		GRPCStreamAddr: "localhost:0",
+	}))
+
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- client.Start()
+	}()
+
+	spec.stream <- "a"
+	Eventually(errChan).Should(Receive(MatchError("utoh")))
+}
+
+func (s *GRPCStreamSuite) TestBadConfig(t sweet.T) {
+	client := NewGRPCStreamClient(newMockStreamSpec())
+	err := client.Init(makeConfig(GRPCStreamConfigToken, &emptyConfig{}))
+	Expect(err).To(Equal(ErrBadGRPCStreamConfig))
+}
+
+//
+// Helpers
+
+func makeGRPCStreamClient(spec GRPCStreamSpec, clock glock.Clock) *GRPCStreamClient {
+	client := newGRPCStreamClient(spec, clock)
+	client.Logger = log.NewNilLogger()
+	client.Container = nacelle.NewServiceContainer()
+	return client
+}
+
+//
+// Mocks
+
+type mockStreamSpec struct {
+	stream chan interface{}
+	handle func(context.Context, interface{}) error
+}
+
+func newMockStreamSpec() *mockStreamSpec {
+	return &mockStreamSpec{
+		stream: make(chan interface{}),
+	}
+}
+
+func (s *mockStreamSpec) Init(config nacelle.Config, client *GRPCStreamClient) error {
+	return nil
+}
+
+func (s *mockStreamSpec) Subscribe(ctx context.Context, conn *grpc.ClientConn) (GRPCRecvFunc, error) {
+	return func() (interface{}, error) {
+		value := <-s.stream
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+
+		return value, nil
+	}, nil
+}
+
+func (s *mockStreamSpec) Handle(ctx context.Context, message interface{}) error {
+	return s.handle(ctx, message)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/aphistic/sweet"
 	. "github.com/onsi/gomega"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/efritz/nacelle"
 	"github.com/efritz/nacelle/log"
@@ -45,6 +47,36 @@ func (s *GRPCSuite) TestServeAndStop(t sweet.T) {
 	Expect(resp.GetText()).To(Equal("FOOBAR"))
 }
 
+func (s *GRPCSuite) TestErrorMapper(t sweet.T) {
+	server := makeGRPCServer(func(config nacelle.Config, server *grpc.Server) error {
+		internal.RegisterTestServiceServer(server, &notFoundService{})
+
+		return nil
+	})
+
+	server.ErrorMapper = NewErrorMapper()
+
+	os.Setenv("GRPC_PORT", "0")
+	defer os.Clearenv()
+
+	err := server.Init(makeConfig(GRPCConfigToken, &GRPCConfig{}))
+	Expect(err).To(BeNil())
+
+	go server.Start()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", getDynamicPort(server.listener)), grpc.WithInsecure())
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	client := internal.NewTestServiceClient(conn)
+
+	_, err = client.ToUpper(context.Background(), &internal.UpperRequest{Text: "foobar"})
+	st, _ := status.FromError(err)
+	Expect(st.Code()).To(Equal(codes.NotFound))
+	Expect(st.Message()).To(Equal("no text foobar"))
+}
+
 func (s *GRPCSuite) TestBadConfig(t sweet.T) {
 	server := makeGRPCServer(func(config nacelle.Config, server *grpc.Server) error {
 		return nil
@@ -95,6 +127,12 @@ func (us *upperService) ToUpper(ctx context.Context, r *internal.UpperRequest) (
 	return &internal.UpperResponse{Text: strings.ToUpper(r.GetText())}, nil
 }
 
+type notFoundService struct{}
+
+func (ns *notFoundService) ToUpper(ctx context.Context, r *internal.UpperRequest) (*internal.UpperResponse, error) {
+	return nil, NewError(KindNotFound, "no text %s", r.GetText())
+}
+
 //
 // Bad Injection
 
@@ -0,0 +1,94 @@
+package process
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/efritz/nacelle"
+)
+
+type healthInitializer struct {
+	Health       *nacelle.Health           `service:"health"`
+	Runner       *nacelle.ProcessRunner    `service:"runner"`
+	KillSwitches *nacelle.KillSwitches     `service:"killswitches" optional:"true"`
+	Container    *nacelle.ServiceContainer `service:"container" optional:"true"`
+}
+
+// NewHealthServer creates an HTTP server which reports the health of the
+// application. The endpoint /healthz responds with 503 while the health
+// tracker reports a problem (suitable for a liveness probe) and the endpoint
+// /readyz responds with 503 unless the process runner reports that all
+// processes are running and healthy (suitable for a readiness probe). The
+// endpoint /killswitchz lists the features whose kill switches are engaged
+// (see nacelle.KillSwitches), one per line. The endpoint /servicez lists the
+// key and concrete type of each service registered to the container. The
+// server requires the services "health" and "runner", which are registered
+// by the bootstrapper. The server reads its HTTPConfig like any other HTTP
+// server, so a separate config token (see WithHTTPConfigToken) or config
+// prefix (see nacelle.WithProcessConfigPrefix) can be used to serve it on a
+// different port than the application.
+func NewHealthServer(configs ...HTTPServerConfigFunc) *HTTPServer {
+	return NewHTTPServer(&healthInitializer{}, append([]HTTPServerConfigFunc{WithDebugEndpoints()}, configs...)...)
+}
+
+func (i *healthInitializer) Init(config nacelle.Config, server *http.Server) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", i.serveHealth)
+	mux.HandleFunc("/readyz", i.serveReady)
+	mux.HandleFunc("/killswitchz", i.serveKillSwitches)
+	mux.HandleFunc("/servicez", i.serveServices)
+	server.Handler = mux
+	return nil
+}
+
+func (i *healthInitializer) serveHealth(w http.ResponseWriter, r *http.Request) {
+	if err := i.Health.Err(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+func (i *healthInitializer) serveReady(w http.ResponseWriter, r *http.Request) {
+	status := i.Runner.Status()
+	if status != nacelle.StatusHealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, status)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, status)
+}
+
+func (i *healthInitializer) serveKillSwitches(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+
+	if i.KillSwitches == nil {
+		return
+	}
+
+	for _, feature := range i.KillSwitches.Killed() {
+		fmt.Fprintln(w, feature)
+	}
+}
+
+func (i *healthInitializer) serveServices(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+
+	if i.Container == nil {
+		return
+	}
+
+	for _, description := range i.Container.Describe() {
+		if description.Type == "" {
+			fmt.Fprintf(w, "%s (unconstructed factory)\n", description.Key)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s %s\n", description.Key, description.Type)
+	}
+}
@@ -0,0 +1,85 @@
+package process
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+)
+
+type HealthSuite struct{}
+
+func (s *HealthSuite) TestHealthz(t sweet.T) {
+	var (
+		container   = nacelle.NewServiceContainer()
+		health      = nacelle.NewHealth(container)
+		initializer = &healthInitializer{Health: health, Runner: nacelle.NewProcessRunner(container)}
+		server      = &http.Server{}
+	)
+
+	Expect(initializer.Init(nil, server)).To(BeNil())
+
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/healthz", nil))
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+
+	health.AddReason("consumer")
+
+	recorder = httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/healthz", nil))
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(Equal("unhealthy services (reasons: consumer)\n"))
+}
+
+func (s *HealthSuite) TestReadyz(t sweet.T) {
+	var (
+		container   = nacelle.NewServiceContainer()
+		initializer = &healthInitializer{Health: nacelle.NewHealth(container), Runner: nacelle.NewProcessRunner(container)}
+		server      = &http.Server{}
+	)
+
+	Expect(initializer.Init(nil, server)).To(BeNil())
+
+	// Runner has not started
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/readyz", nil))
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(Equal("starting\n"))
+}
+
+func (s *HealthSuite) TestKillSwitchz(t sweet.T) {
+	var (
+		container   = nacelle.NewServiceContainer()
+		switches    = nacelle.NewKillSwitches("search", "checkout")
+		initializer = &healthInitializer{Health: nacelle.NewHealth(container), Runner: nacelle.NewProcessRunner(container), KillSwitches: switches}
+		server      = &http.Server{}
+	)
+
+	Expect(initializer.Init(nil, server)).To(BeNil())
+
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/killswitchz", nil))
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("checkout\nsearch\n"))
+}
+
+func (s *HealthSuite) TestServicez(t sweet.T) {
+	var (
+		container   = nacelle.NewServiceContainer()
+		initializer = &healthInitializer{Health: nacelle.NewHealth(container), Runner: nacelle.NewProcessRunner(container), Container: container}
+		server      = &http.Server{}
+	)
+
+	container.MustSet("ports", nacelle.NewPorts())
+	container.MustSetFactory("lazy", func(c *nacelle.ServiceContainer) (interface{}, error) { return nil, nil })
+
+	Expect(initializer.Init(nil, server)).To(BeNil())
+
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/servicez", nil))
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("container *nacelle.ServiceContainer\nlazy (unconstructed factory)\nports *nacelle.Ports\n"))
+}
@@ -3,9 +3,11 @@ package process
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/efritz/nacelle"
@@ -15,7 +17,9 @@ type (
 	HTTPServer struct {
 		Logger          nacelle.Logger            `service:"logger"`
 		Container       *nacelle.ServiceContainer `service:"container"`
+		Ports           *nacelle.Ports            `service:"ports" optional:"true"`
 		configToken     interface{}
+		debug           bool
 		initializer     HTTPServerInitializer
 		listener        *net.TCPListener
 		server          *http.Server
@@ -24,6 +28,8 @@ type (
 		certFile        string
 		keyFile         string
 		shutdownTimeout time.Duration
+		shutdown        chan struct{}
+		inFlight        int64
 	}
 
 	HTTPServerInitializer interface {
@@ -44,6 +50,7 @@ func NewHTTPServer(initializer HTTPServerInitializer, configs ...HTTPServerConfi
 
 	return &HTTPServer{
 		configToken: options.configToken,
+		debug:       options.debug,
 		initializer: initializer,
 		once:        &sync.Once{},
 	}
@@ -55,22 +62,42 @@ func (s *HTTPServer) Init(config nacelle.Config) (err error) {
 		return ErrBadHTTPConfig
 	}
 
-	s.listener, err = makeListener(httpConfig.HTTPPort)
+	if nacelle.IsProduction() {
+		if err := s.checkHardening(httpConfig); err != nil {
+			return err
+		}
+	}
+
+	s.listener, err = makeListener(s.Ports, s.configToken, httpConfig.HTTPHost, httpConfig.HTTPPort)
 	if err != nil {
 		return err
 	}
 
-	s.server = &http.Server{}
-	s.port = httpConfig.HTTPPort
+	timeouts := nacelle.ResolveTimeouts(s.Container, httpConfig.Timeouts)
+
+	s.server = &http.Server{
+		ReadTimeout:  timeouts.Read,
+		WriteTimeout: timeouts.Write,
+		IdleTimeout:  timeouts.Idle,
+	}
+
+	s.once = &sync.Once{}
+	s.shutdown = make(chan struct{})
+	s.port = s.listener.Addr().(*net.TCPAddr).Port
 	s.certFile = httpConfig.HTTPCertFile
 	s.keyFile = httpConfig.HTTPKeyFile
-	s.shutdownTimeout = httpConfig.ShutdownTimeout
+	s.shutdownTimeout = timeouts.Shutdown
 
 	if err := s.Container.Inject(s.initializer); err != nil {
 		return err
 	}
 
-	return s.initializer.Init(config, s.server)
+	if err := s.initializer.Init(config, s.server); err != nil {
+		return err
+	}
+
+	s.server.Handler = s.countInFlight(s.server.Handler)
+	return nil
 }
 
 func (s *HTTPServer) Start() error {
@@ -83,6 +110,7 @@ func (s *HTTPServer) Start() error {
 			return err
 		}
 
+		<-s.shutdown
 		s.Logger.Info("No longer serving HTTP on port %d", s.port)
 		return nil
 	}
@@ -92,12 +120,26 @@ func (s *HTTPServer) Start() error {
 		return err
 	}
 
+	<-s.shutdown
 	s.Logger.Info("No longer serving HTTP/TLS on port %d", s.port)
 	return nil
 }
 
+// Drain stops the server from accepting new connections and blocks until all
+// in-flight requests have completed or the shutdown timeout elapses.
+func (s *HTTPServer) Drain() error {
+	return s.Stop()
+}
+
+// InFlight returns the number of requests currently being served.
+func (s *HTTPServer) InFlight() int {
+	return int(atomic.LoadInt64(&s.inFlight))
+}
+
 func (s *HTTPServer) Stop() (err error) {
 	s.once.Do(func() {
+		defer close(s.shutdown)
+
 		ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 		defer cancel()
 
@@ -107,3 +149,38 @@ func (s *HTTPServer) Stop() (err error) {
 
 	return
 }
+
+// checkHardening returns an error if the given config violates the invariants
+// of production mode: debug endpoints must be bound to localhost, and other
+// endpoints must be served over TLS unless they are bound to localhost.
+func (s *HTTPServer) checkHardening(c *HTTPConfig) error {
+	if isLoopback(c.HTTPHost) {
+		return nil
+	}
+
+	if s.debug {
+		return fmt.Errorf("debug endpoints must be bound to localhost in production (host is %q)", c.HTTPHost)
+	}
+
+	if c.HTTPCertFile == "" {
+		return fmt.Errorf("TLS is required on public listeners in production")
+	}
+
+	return nil
+}
+
+// countInFlight wraps the given handler so that the server tracks the number
+// of requests being served. A nil handler is the default serve mux, as with an
+// http.Server.
+func (s *HTTPServer) countInFlight(handler http.Handler) http.Handler {
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&s.inFlight, 1)
+		defer atomic.AddInt64(&s.inFlight, -1)
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,264 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	// BindError describes each field of a request which could not be bound.
+	BindError struct {
+		Fields []FieldError `json:"fields"`
+	}
+
+	// FieldError describes a field of a request which could not be bound.
+	FieldError struct {
+		// Field is the name of the query parameter, form value, or JSON body
+		// field.
+		Field string `json:"field"`
+
+		// Source is one of query, form, or body.
+		Source  string `json:"source"`
+		Message string `json:"message"`
+	}
+
+	// RequestValidator is implemented by request structs which perform validation
+	// beyond the required tag. The Validate method is called by Bind once every
+	// field has been bound.
+	RequestValidator interface {
+		Validate() error
+	}
+)
+
+const (
+	queryTag    = "query"
+	formTag     = "form"
+	jsonTag     = "json"
+	defaultTag  = "default"
+	requiredTag = "required"
+
+	maxBindBodySize = 10 << 20
+)
+
+// Bind populates the given request struct from the given request. A field tagged
+// with `query:"name"` is read from the URL query and a field tagged `form:"name"`
+// is read from the form body. Values are coerced as config values are read from
+// the environment: the value is parsed as JSON and, failing that, is treated as a
+// string. A slice field is populated from every value of its parameter. If the
+// request has a JSON body, it is decoded into the struct. As with config structs,
+// a field tagged `default:"value"` is given that value when none is supplied, and
+// a field tagged `required:"true"` must be supplied (a required JSON field must be
+// non-zero). If the struct implements RequestValidator, its Validate method is
+// then called. The returned error is an application error of the kind invalid
+// which wraps a BindError listing each invalid field, so that an error mapper
+// responds with a 400 and the field details.
+func Bind(r *http.Request, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target %T is not a pointer to a struct", target)
+	}
+
+	bindErr := &BindError{}
+
+	if isJSONRequest(r) {
+		if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBindBodySize)).Decode(target); err != nil {
+			bindErr.add("", "body", fmt.Sprintf("malformed JSON body (%s)", err.Error()))
+			return bindErr.wrap()
+		}
+	}
+
+	var form url.Values
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		if err := r.ParseForm(); err != nil {
+			bindErr.add("", "form", fmt.Sprintf("malformed form body (%s)", err.Error()))
+			return bindErr.wrap()
+		}
+
+		form = r.PostForm
+	}
+
+	bindFields(value.Elem(), r.URL.Query(), form, bindErr)
+
+	if len(bindErr.Fields) == 0 {
+		if validator, ok := target.(RequestValidator); ok {
+			if err := validator.Validate(); err != nil {
+				return &AppError{Kind: KindInvalid, Message: err.Error(), Err: err}
+			}
+		}
+	}
+
+	return bindErr.wrap()
+}
+
+// BindOrReject calls Bind and, if binding fails, writes the problem details body of
+// the error with a default error mapper (see ErrorMapper.WriteProblem). False is
+// returned if the handler should not continue.
+func BindOrReject(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	if err := Bind(r, target); err != nil {
+		NewErrorMapper().WriteProblem(w, err)
+		return false
+	}
+
+	return true
+}
+
+func (e *BindError) Error() string {
+	messages := []string{}
+	for _, field := range e.Fields {
+		if field.Field == "" {
+			messages = append(messages, field.Message)
+			continue
+		}
+
+		messages = append(messages, fmt.Sprintf("%s %s: %s", field.Source, field.Field, field.Message))
+	}
+
+	return fmt.Sprintf("invalid request (%s)", strings.Join(messages, "; "))
+}
+
+func (e *BindError) add(field, source, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Source: source, Message: message})
+}
+
+func (e *BindError) wrap() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	return &AppError{Kind: KindInvalid, Message: e.Error(), Err: e}
+}
+
+func bindFields(value reflect.Value, query, form url.Values, bindErr *BindError) {
+	t := value.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		var (
+			fieldType  = t.Field(i)
+			fieldValue = value.Field(i)
+		)
+
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		name, source, values := fieldValues(fieldType, query, form)
+		if name == "" {
+			continue
+		}
+
+		if len(values) == 0 {
+			if err := bindDefault(fieldType, fieldValue, source != "body"); err != "" {
+				bindErr.add(name, source, err)
+			}
+
+			continue
+		}
+
+		if err := coerceValues(values, fieldValue); err != nil {
+			bindErr.add(name, source, "value cannot be coerced into the expected type")
+		}
+	}
+}
+
+// fieldValues returns the parameter name, source, and supplied values of the given
+// field. The values of a JSON body field are not returned, as they are decoded with
+// the body. An empty name is returned for a field which is not bound.
+func fieldValues(fieldType reflect.StructField, query, form url.Values) (string, string, []string) {
+	if name := fieldType.Tag.Get(queryTag); name != "" {
+		return name, "query", query[name]
+	}
+
+	if name := fieldType.Tag.Get(formTag); name != "" {
+		return name, "form", form[name]
+	}
+
+	if name := strings.Split(fieldType.Tag.Get(jsonTag), ",")[0]; name != "" && name != "-" {
+		return name, "body", nil
+	}
+
+	return "", "", nil
+}
+
+// bindDefault applies the default and required tags to a field for which no value
+// was supplied. Unless the value of the field is known to be absent, a non-zero
+// value is treated as supplied. A non-empty message is returned on failure.
+func bindDefault(fieldType reflect.StructField, fieldValue reflect.Value, absent bool) string {
+	if !absent && !fieldValue.IsZero() {
+		return ""
+	}
+
+	if required := fieldType.Tag.Get(requiredTag); required != "" {
+		val, err := strconv.ParseBool(required)
+		if err != nil {
+			return "field has an invalid required tag"
+		}
+
+		if val {
+			return "no value supplied"
+		}
+	}
+
+	if defaultValue := fieldType.Tag.Get(defaultTag); defaultValue != "" {
+		if err := coerceValues([]string{defaultValue}, fieldValue); err != nil {
+			return "default value cannot be coerced into the expected type"
+		}
+	}
+
+	return ""
+}
+
+func coerceValues(values []string, fieldValue reflect.Value) error {
+	if fieldValue.Kind() != reflect.Slice || fieldValue.Type().Elem().Kind() == reflect.Uint8 {
+		return coerce(values[len(values)-1], fieldValue)
+	}
+
+	// A single value may be a JSON list
+	if len(values) == 1 && coerce(values[0], fieldValue) == nil {
+		return nil
+	}
+
+	slice := reflect.MakeSlice(fieldValue.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := coerce(value, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	fieldValue.Set(slice)
+	return nil
+}
+
+// coerce parses the given value as JSON into the given field and, failing that,
+// as a JSON string if the field is a string.
+func coerce(value string, fieldValue reflect.Value) error {
+	target := reflect.New(fieldValue.Type())
+
+	if err := json.Unmarshal([]byte(value), target.Interface()); err != nil {
+		if fieldValue.Kind() != reflect.String {
+			return err
+		}
+
+		quoted, _ := json.Marshal(value)
+		if err := json.Unmarshal(quoted, target.Interface()); err != nil {
+			return err
+		}
+	}
+
+	fieldValue.Set(target.Elem())
+	return nil
+}
+
+func isJSONRequest(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,124 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type HTTPBindSuite struct{}
+
+type (
+	searchRequest struct {
+		Query string   `query:"q" required:"true"`
+		Page  int      `query:"page" default:"1"`
+		Tags  []string `query:"tag"`
+		Token string   `form:"token"`
+	}
+
+	createRequest struct {
+		Name  string `json:"name" required:"true"`
+		Count int    `json:"count" default:"10"`
+		Admin bool   `json:"-"`
+	}
+
+	validatedRequest struct {
+		Min int `query:"min"`
+		Max int `query:"max"`
+	}
+)
+
+func (r *validatedRequest) Validate() error {
+	if r.Min > r.Max {
+		return errors.New("min exceeds max")
+	}
+
+	return nil
+}
+
+func (s *HTTPBindSuite) TestBindQuery(t sweet.T) {
+	request := &searchRequest{}
+	Expect(Bind(httptest.NewRequest("GET", "/search?q=foo&tag=a&tag=b", nil), request)).To(BeNil())
+	Expect(request).To(Equal(&searchRequest{Query: "foo", Page: 1, Tags: []string{"a", "b"}}))
+
+	request = &searchRequest{}
+	Expect(Bind(httptest.NewRequest("GET", "/search?q=42&page=3&tag=%5B%22a%22%2C%22b%22%5D", nil), request)).To(BeNil())
+	Expect(request).To(Equal(&searchRequest{Query: "42", Page: 3, Tags: []string{"a", "b"}}))
+}
+
+func (s *HTTPBindSuite) TestBindForm(t sweet.T) {
+	r := httptest.NewRequest("POST", "/search?q=foo", strings.NewReader("token=secret"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	request := &searchRequest{}
+	Expect(Bind(r, request)).To(BeNil())
+	Expect(request.Token).To(Equal("secret"))
+}
+
+func (s *HTTPBindSuite) TestBindJSON(t sweet.T) {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name": "foo", "admin": true}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	request := &createRequest{}
+	Expect(Bind(r, request)).To(BeNil())
+	Expect(request).To(Equal(&createRequest{Name: "foo", Count: 10}))
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{"count": 3`))
+	r.Header.Set("Content-Type", "application/json")
+	Expect(Bind(r, &createRequest{})).To(MatchError(ContainSubstring("malformed JSON body")))
+}
+
+func (s *HTTPBindSuite) TestBindErrors(t sweet.T) {
+	err := Bind(httptest.NewRequest("GET", "/search?page=last", nil), &searchRequest{})
+	Expect(err).To(MatchError("invalid request (query q: no value supplied; query page: value cannot be coerced into the expected type)"))
+
+	var bindErr *BindError
+	Expect(errors.As(err, &bindErr)).To(BeTrue())
+	Expect(bindErr.Fields).To(Equal([]FieldError{
+		{Field: "q", Source: "query", Message: "no value supplied"},
+		{Field: "page", Source: "query", Message: "value cannot be coerced into the expected type"},
+	}))
+
+	Expect(Bind(httptest.NewRequest("GET", "/", nil), searchRequest{})).To(MatchError("bind target process.searchRequest is not a pointer to a struct"))
+}
+
+func (s *HTTPBindSuite) TestBindValidate(t sweet.T) {
+	Expect(Bind(httptest.NewRequest("GET", "/?min=1&max=2", nil), &validatedRequest{})).To(BeNil())
+
+	err := Bind(httptest.NewRequest("GET", "/?min=3&max=2", nil), &validatedRequest{})
+	Expect(err).To(MatchError("min exceeds max"))
+	Expect(NewErrorMapper().HTTPStatus(err)).To(Equal(http.StatusBadRequest))
+}
+
+func (s *HTTPBindSuite) TestBindOrReject(t sweet.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &searchRequest{}
+		if !BindOrReject(w, r, request) {
+			return
+		}
+
+		fmt.Fprint(w, request.Query)
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/search?q=foo", nil))
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("foo"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/search", nil))
+	Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	Expect(recorder.Header().Get("Content-Type")).To(Equal("application/problem+json"))
+
+	problem := Problem{}
+	Expect(json.Unmarshal(recorder.Body.Bytes(), &problem)).To(BeNil())
+	Expect(problem.Errors).To(Equal([]FieldError{
+		{Field: "q", Source: "query", Message: "no value supplied"},
+	}))
+}
@@ -3,17 +3,24 @@ package process
 import (
 	"errors"
 	"fmt"
-	"time"
+
+	"github.com/efritz/nacelle"
 )
 
 type (
 	HTTPConfig struct {
+		HTTPHost           string `env:"http_host"`
 		HTTPPort           int    `env:"http_port" default:"5000"`
 		HTTPCertFile       string `env:"http_cert_file"`
 		HTTPKeyFile        string `env:"http_key_file"`
-		RawShutdownTimeout int    `env:"http_shutdown_timeout" default:"5"`
-
-		ShutdownTimeout time.Duration
+		RawReadTimeout     int    `env:"http_read_timeout"`
+		RawWriteTimeout    int    `env:"http_write_timeout"`
+		RawIdleTimeout     int    `env:"http_idle_timeout"`
+		RawShutdownTimeout int    `env:"http_shutdown_timeout"`
+
+		// Timeouts holds the HTTP-specific overrides of the shared
+		// timeouts. A zero value inherits the shared timeout.
+		Timeouts nacelle.Timeouts
 	}
 
 	httpConfigToken string
@@ -33,6 +40,11 @@ func (c *HTTPConfig) PostLoad() error {
 		return ErrBadCertConfig
 	}
 
-	c.ShutdownTimeout = time.Duration(c.RawShutdownTimeout) * time.Second
+	timeouts, err := nacelle.MakeTimeouts(0, c.RawReadTimeout, c.RawWriteTimeout, c.RawIdleTimeout, c.RawShutdownTimeout)
+	if err != nil {
+		return err
+	}
+
+	c.Timeouts = timeouts
 	return nil
 }
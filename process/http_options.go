@@ -3,6 +3,7 @@ package process
 type (
 	httpOptions struct {
 		configToken interface{}
+		debug       bool
 	}
 
 	// HTTPServerConfigFunc is a function used to configure an instance of
@@ -16,6 +17,13 @@ func WithHTTPConfigToken(token interface{}) HTTPServerConfigFunc {
 	return func(o *httpOptions) { o.configToken = token }
 }
 
+// WithDebugEndpoints marks the server as serving debug endpoints (e.g. health
+// checks or profiling), which must be bound to localhost in production mode
+// (see nacelle.EnvProduction).
+func WithDebugEndpoints() HTTPServerConfigFunc {
+	return func(o *httpOptions) { o.debug = true }
+}
+
 func getHTTPOptions(configs []HTTPServerConfigFunc) *httpOptions {
 	options := &httpOptions{
 		configToken: HTTPConfigToken,
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/aphistic/sweet"
 	. "github.com/onsi/gomega"
@@ -55,6 +56,25 @@ func (s *HTTPSuite) TestServeAndStop(t sweet.T) {
 	Expect(data).To(Equal([]byte("bar")))
 }
 
+func (s *HTTPSuite) TestPublishPort(t sweet.T) {
+	server := makeHTTPServer(func(config nacelle.Config, server *http.Server) error {
+		return nil
+	})
+
+	server.Ports = nacelle.NewPorts()
+
+	os.Setenv("HTTP_PORT", "0")
+	defer os.Clearenv()
+
+	err := server.Init(makeConfig(HTTPConfigToken, &HTTPConfig{}))
+	Expect(err).To(BeNil())
+	defer server.listener.Close()
+
+	port, ok := server.Ports.Port(HTTPConfigToken)
+	Expect(ok).To(BeTrue())
+	Expect(port).To(Equal(getDynamicPort(server.listener)))
+}
+
 func (s *HTTPSuite) TestBadConfig(t sweet.T) {
 	server := makeHTTPServer(func(config nacelle.Config, server *http.Server) error {
 		return nil
@@ -87,6 +107,98 @@ func (s *HTTPSuite) TestInitError(t sweet.T) {
 	Expect(err).To(MatchError("utoh"))
 }
 
+func (s *HTTPSuite) TestDrain(t sweet.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		drained = make(chan error)
+		stopped = make(chan error)
+	)
+
+	server := makeHTTPServer(func(config nacelle.Config, server *http.Server) error {
+		server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})
+
+		return nil
+	})
+
+	os.Setenv("HTTP_PORT", "0")
+	defer os.Clearenv()
+
+	err := server.Init(makeConfig(HTTPConfigToken, &HTTPConfig{}))
+	Expect(err).To(BeNil())
+
+	go func() { stopped <- server.Start() }()
+
+	go func() {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d/", getDynamicPort(server.listener)))
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	Eventually(started).Should(BeClosed())
+	Expect(server.InFlight()).To(Equal(1))
+
+	// Draining waits on the open request
+	go func() { drained <- server.Drain() }()
+	Consistently(drained).ShouldNot(Receive())
+	Consistently(stopped).ShouldNot(Receive())
+
+	close(release)
+	Eventually(drained, time.Second*2).Should(Receive(BeNil()))
+	Eventually(stopped).Should(Receive(BeNil()))
+	Expect(server.InFlight()).To(Equal(0))
+}
+
+func (s *HTTPSuite) TestProductionHardening(t sweet.T) {
+	initializer := func(config nacelle.Config, server *http.Server) error { return nil }
+
+	os.Setenv("NACELLE_ENV", "production")
+	os.Setenv("HTTP_PORT", "0")
+	defer os.Clearenv()
+
+	server := makeHTTPServer(initializer)
+	Expect(server.Init(makeConfig(HTTPConfigToken, &HTTPConfig{}))).To(MatchError("TLS is required on public listeners in production"))
+
+	server = NewHTTPServer(HTTPServerInitializerFunc(initializer), WithDebugEndpoints())
+	server.Logger = log.NewNilLogger()
+	Expect(server.Init(makeConfig(HTTPConfigToken, &HTTPConfig{}))).To(MatchError(`debug endpoints must be bound to localhost in production (host is "")`))
+
+	os.Setenv("HTTP_HOST", "127.0.0.1")
+	Expect(server.Init(makeConfig(HTTPConfigToken, &HTTPConfig{}))).To(BeNil())
+	defer server.listener.Close()
+}
+
+func (s *HTTPSuite) TestTimeouts(t sweet.T) {
+	server := makeHTTPServer(func(config nacelle.Config, server *http.Server) error {
+		return nil
+	})
+
+	server.Container = nacelle.NewServiceContainer()
+	server.Container.Set("timeouts", &nacelle.Timeouts{
+		Read:     time.Second * 10,
+		Write:    time.Second * 10,
+		Shutdown: time.Second * 20,
+	})
+
+	os.Setenv("HTTP_PORT", "0")
+	os.Setenv("HTTP_WRITE_TIMEOUT", "30")
+	defer os.Clearenv()
+
+	err := server.Init(makeConfig(HTTPConfigToken, &HTTPConfig{}))
+	Expect(err).To(BeNil())
+	defer server.listener.Close()
+
+	Expect(server.server.ReadTimeout).To(Equal(time.Second * 10))
+	Expect(server.server.WriteTimeout).To(Equal(time.Second * 30))
+	Expect(server.server.IdleTimeout).To(BeZero())
+	Expect(server.shutdownTimeout).To(Equal(time.Second * 20))
+}
+
 //
 // Helpers
 
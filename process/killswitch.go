@@ -0,0 +1,78 @@
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/efritz/nacelle"
+)
+
+type killSwitchReloader struct {
+	KillSwitches *nacelle.KillSwitches  `service:"killswitches"`
+	Logger       nacelle.Logger         `service:"logger"`
+	Runner       *nacelle.ProcessRunner `service:"runner" optional:"true"`
+	paths        []string
+}
+
+// NewKillSwitchWatcher creates a file watcher which reloads the kill switches
+// registered to the service container from its watched files whenever they change
+// (see KillSwitches.Reload). The files are also read once when the watcher is
+// initialized, replacing the kill switches populated from the application config.
+// A file which cannot be read during a reload is logged and the previous state of
+// the kill switches is retained.
+func NewKillSwitchWatcher(configs ...FileWatcherConfigFunc) *FileWatcher {
+	return NewFileWatcher(&killSwitchReloader{}, configs...)
+}
+
+// KillSwitchMiddleware wraps an HTTP handler so that it responds with a 503 while
+// the kill switch of the given feature is engaged.
+func KillSwitchMiddleware(switches *nacelle.KillSwitches, feature string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !switches.Enabled(feature) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "feature %s is disabled\n", feature)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (r *killSwitchReloader) Init(config nacelle.Config, watcher *FileWatcher) error {
+	r.paths = watcher.paths
+	return r.reload()
+}
+
+func (r *killSwitchReloader) Handle(paths []string) error {
+	if err := r.reload(); err != nil {
+		r.Logger.Warning("Failed to reload kill switches (%s)", err.Error())
+	}
+
+	return nil
+}
+
+func (r *killSwitchReloader) reload() error {
+	contents := [][]byte{}
+	for _, path := range r.paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		contents = append(contents, content)
+	}
+
+	if err := r.KillSwitches.Reload(bytes.NewReader(bytes.Join(contents, []byte("\n")))); err != nil {
+		return err
+	}
+
+	r.Logger.InfoWithFields(r.KillSwitches.Fields(), "Reloaded kill switches (%d disabled)", len(r.KillSwitches.Killed()))
+
+	if r.Runner != nil {
+		r.Runner.RecordReload("kill switches")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package process
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+	"github.com/efritz/nacelle/log"
+)
+
+type KillSwitchSuite struct{}
+
+func (s *KillSwitchSuite) TestMiddleware(t sweet.T) {
+	var (
+		switches = nacelle.NewKillSwitches()
+		handler  = KillSwitchMiddleware(switches, "checkout", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/checkout", nil))
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+	switches.Kill("checkout")
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/checkout", nil))
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(Equal("feature checkout is disabled\n"))
+}
+
+func (s *KillSwitchSuite) TestReloader(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-killswitch")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		path     = filepath.Join(dir, "switches")
+		switches = nacelle.NewKillSwitches("checkout")
+		reloader = &killSwitchReloader{KillSwitches: switches, Logger: log.NewNilLogger()}
+	)
+
+	Expect(ioutil.WriteFile(path, []byte("search\n"), 0644)).To(BeNil())
+	Expect(reloader.Init(nil, &FileWatcher{paths: []string{path}})).To(BeNil())
+	Expect(switches.Killed()).To(Equal([]string{"search"}))
+
+	Expect(ioutil.WriteFile(path, []byte("search\nexport\n"), 0644)).To(BeNil())
+	Expect(reloader.Handle([]string{path})).To(BeNil())
+	Expect(switches.Killed()).To(Equal([]string{"export", "search"}))
+
+	// Unreadable files retain the previous state
+	Expect(os.Remove(path)).To(BeNil())
+	Expect(reloader.Handle([]string{path})).To(BeNil())
+	Expect(switches.Killed()).To(Equal([]string{"export", "search"}))
+}
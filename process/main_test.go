@@ -16,9 +16,19 @@ func TestMain(m *testing.M) {
 	sweet.Run(m, func(s *sweet.S) {
 		s.RegisterPlugin(junit.NewPlugin())
 
+		s.AddSuite(&CommandSuite{})
 		s.AddSuite(&ConfigSuite{})
+		s.AddSuite(&ErrorMapperSuite{})
 		s.AddSuite(&HTTPSuite{})
+		s.AddSuite(&HTTPBindSuite{})
 		s.AddSuite(&GRPCSuite{})
+		s.AddSuite(&HealthSuite{})
+		s.AddSuite(&KillSwitchSuite{})
+		s.AddSuite(&GRPCStreamSuite{})
+		s.AddSuite(&FileWatcherSuite{})
+		s.AddSuite(&RuntimeMetricsSuite{})
+		s.AddSuite(&SpoolSuite{})
+		s.AddSuite(&SupervisorSuite{})
 		s.AddSuite(&WorkerSuite{})
 	})
 }
@@ -0,0 +1,167 @@
+package process
+
+import (
+	"errors"
+	"math"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// RuntimeMetrics periodically samples the Go runtime metrics (see the
+	// runtime/metrics package), which cover the garbage collector, the heap
+	// and other memory classes, and scheduler latencies, and hands each set
+	// of samples to a reporter. Integer and float metrics are reported under
+	// their runtime/metrics name. Histogram metrics (e.g. /sched/latencies)
+	// are summarized by the keys name.p50, name.p90, name.p99, and name.max.
+	RuntimeMetrics struct {
+		Logger      nacelle.Logger `service:"logger"`
+		configToken interface{}
+		reporter    RuntimeMetricsReporter
+		clock       glock.Clock
+		halt        chan struct{}
+		once        *sync.Once
+		interval    time.Duration
+		samples     []metrics.Sample
+	}
+
+	// RuntimeMetricsReporter receives a set of runtime metric samples. A
+	// reporter may, for example, publish the samples to a metrics backend or
+	// snapshot them into a nacelle.FlightRecorder.
+	RuntimeMetricsReporter func(samples nacelle.Fields)
+)
+
+var (
+	ErrBadRuntimeMetricsConfig = errors.New("runtime metrics config not registered properly")
+
+	runtimeMetricsQuantiles = map[string]float64{
+		"p50": 0.5,
+		"p90": 0.9,
+		"p99": 0.99,
+	}
+)
+
+func NewRuntimeMetrics(configs ...RuntimeMetricsConfigFunc) *RuntimeMetrics {
+	return newRuntimeMetrics(glock.NewRealClock(), configs...)
+}
+
+func newRuntimeMetrics(clock glock.Clock, configs ...RuntimeMetricsConfigFunc) *RuntimeMetrics {
+	options := getRuntimeMetricsOptions(configs)
+
+	return &RuntimeMetrics{
+		configToken: options.configToken,
+		reporter:    options.reporter,
+		clock:       clock,
+		halt:        make(chan struct{}),
+		once:        &sync.Once{},
+	}
+}
+
+func (m *RuntimeMetrics) Init(config nacelle.Config) error {
+	runtimeMetricsConfig := &RuntimeMetricsConfig{}
+	if err := config.Fetch(m.configToken, runtimeMetricsConfig); err != nil {
+		return ErrBadRuntimeMetricsConfig
+	}
+
+	m.interval = runtimeMetricsConfig.RuntimeMetricsInterval
+	m.halt = make(chan struct{})
+	m.once = &sync.Once{}
+	m.samples = []metrics.Sample{}
+
+	for _, description := range metrics.All() {
+		m.samples = append(m.samples, metrics.Sample{Name: description.Name})
+	}
+
+	if m.reporter == nil {
+		m.reporter = func(samples nacelle.Fields) {
+			m.Logger.DebugWithFields(samples, "Sampled runtime metrics")
+		}
+	}
+
+	return nil
+}
+
+func (m *RuntimeMetrics) Start() error {
+	defer m.Stop()
+
+	for {
+		select {
+		case <-m.halt:
+			return nil
+		case <-m.clock.After(m.interval):
+		}
+
+		m.reporter(m.Sample())
+	}
+}
+
+func (m *RuntimeMetrics) Stop() (err error) {
+	m.once.Do(func() { close(m.halt) })
+	return
+}
+
+// Sample reads the current value of each supported runtime metric.
+func (m *RuntimeMetrics) Sample() nacelle.Fields {
+	metrics.Read(m.samples)
+
+	fields := nacelle.Fields{}
+	for _, sample := range m.samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			fields[sample.Name] = sample.Value.Uint64()
+
+		case metrics.KindFloat64:
+			fields[sample.Name] = sample.Value.Float64()
+
+		case metrics.KindFloat64Histogram:
+			histogram := sample.Value.Float64Histogram()
+
+			for suffix, quantile := range runtimeMetricsQuantiles {
+				fields[sample.Name+"."+suffix] = histogramQuantile(histogram, quantile)
+			}
+
+			fields[sample.Name+".max"] = histogramQuantile(histogram, 1)
+		}
+	}
+
+	return fields
+}
+
+// histogramQuantile approximates the given quantile of the histogram by the
+// upper bound of the bucket in which it falls. Infinite bounds are replaced
+// by the finite bound on the other side of the bucket.
+func histogramQuantile(histogram *metrics.Float64Histogram, quantile float64) float64 {
+	total := uint64(0)
+	for _, count := range histogram.Counts {
+		total += count
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	var (
+		threshold = uint64(math.Ceil(float64(total) * quantile))
+		seen      = uint64(0)
+	)
+
+	for i, count := range histogram.Counts {
+		seen += count
+		if seen < threshold || count == 0 {
+			continue
+		}
+
+		if bound := histogram.Buckets[i+1]; !math.IsInf(bound, 1) {
+			return bound
+		}
+
+		return histogram.Buckets[i]
+	}
+
+	return 0
+}
@@ -0,0 +1,35 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type (
+	RuntimeMetricsConfig struct {
+		RawRuntimeMetricsInterval int `env:"runtime_metrics_interval" default:"15"`
+
+		RuntimeMetricsInterval time.Duration
+	}
+
+	runtimeMetricsConfigToken string
+)
+
+var (
+	RuntimeMetricsConfigToken        = MakeRuntimeMetricsConfigToken("default")
+	ErrIllegalRuntimeMetricsInterval = errors.New("runtime metrics interval must be positive")
+)
+
+func MakeRuntimeMetricsConfigToken(name string) interface{} {
+	return runtimeMetricsConfigToken(fmt.Sprintf("nacelle-process-runtime-metrics-%s", name))
+}
+
+func (c *RuntimeMetricsConfig) PostLoad() error {
+	if c.RawRuntimeMetricsInterval <= 0 {
+		return ErrIllegalRuntimeMetricsInterval
+	}
+
+	c.RuntimeMetricsInterval = time.Duration(c.RawRuntimeMetricsInterval) * time.Second
+	return nil
+}
@@ -0,0 +1,37 @@
+package process
+
+type (
+	runtimeMetricsOptions struct {
+		configToken interface{}
+		reporter    RuntimeMetricsReporter
+	}
+
+	// RuntimeMetricsConfigFunc is a function used to configure an instance of
+	// a RuntimeMetrics process.
+	RuntimeMetricsConfigFunc func(*runtimeMetricsOptions)
+)
+
+// WithRuntimeMetricsConfigToken sets the config token to use. This is useful if an
+// application has multiple RuntimeMetrics processes running with different configuration
+// tags.
+func WithRuntimeMetricsConfigToken(token interface{}) RuntimeMetricsConfigFunc {
+	return func(o *runtimeMetricsOptions) { o.configToken = token }
+}
+
+// WithRuntimeMetricsReporter sets the function which receives each set of samples.
+// By default, the samples are logged at the debug level.
+func WithRuntimeMetricsReporter(reporter RuntimeMetricsReporter) RuntimeMetricsConfigFunc {
+	return func(o *runtimeMetricsOptions) { o.reporter = reporter }
+}
+
+func getRuntimeMetricsOptions(configs []RuntimeMetricsConfigFunc) *runtimeMetricsOptions {
+	options := &runtimeMetricsOptions{
+		configToken: RuntimeMetricsConfigToken,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
@@ -0,0 +1,62 @@
+package process
+
+import (
+	"math"
+	"runtime/metrics"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	"github.com/efritz/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type RuntimeMetricsSuite struct{}
+
+func (s *RuntimeMetricsSuite) TestReport(t sweet.T) {
+	var (
+		clock       = glock.NewMockClock()
+		sampleChan  = make(chan nacelle.Fields)
+		errChan     = make(chan error)
+		reporter    = func(samples nacelle.Fields) { sampleChan <- samples }
+		runtimeProc = newRuntimeMetrics(clock, WithRuntimeMetricsReporter(reporter))
+	)
+
+	err := runtimeProc.Init(makeConfig(RuntimeMetricsConfigToken, &RuntimeMetricsConfig{}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- runtimeProc.Start()
+	}()
+
+	clock.BlockingAdvance(time.Second * 15)
+
+	var samples nacelle.Fields
+	Eventually(sampleChan).Should(Receive(&samples))
+	Expect(samples).To(HaveKey("/gc/cycles/total:gc-cycles"))
+	Expect(samples).To(HaveKey("/sched/goroutines:goroutines"))
+	Expect(samples).To(HaveKey("/sched/latencies:seconds.p99"))
+	Expect(samples).To(HaveKey("/sched/latencies:seconds.max"))
+	Consistently(sampleChan).ShouldNot(Receive())
+
+	runtimeProc.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *RuntimeMetricsSuite) TestBadConfig(t sweet.T) {
+	runtimeProc := NewRuntimeMetrics()
+	err := runtimeProc.Init(makeConfig(RuntimeMetricsConfigToken, &emptyConfig{}))
+	Expect(err).To(Equal(ErrBadRuntimeMetricsConfig))
+}
+
+func (s *RuntimeMetricsSuite) TestHistogramQuantile(t sweet.T) {
+	histogram := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 0, 8, 1},
+		Buckets: []float64{0, 1, 2, 3, math.Inf(1)},
+	}
+
+	Expect(histogramQuantile(histogram, 0.1)).To(Equal(1.0))
+	Expect(histogramQuantile(histogram, 0.5)).To(Equal(3.0))
+	Expect(histogramQuantile(histogram, 1)).To(Equal(3.0))
+	Expect(histogramQuantile(&metrics.Float64Histogram{}, 0.5)).To(Equal(0.0))
+}
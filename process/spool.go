@@ -0,0 +1,182 @@
+package process
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// Spool scans a spool directory on an interval and hands each file found
+	// to a handler. A file is claimed by atomically renaming it into a work
+	// directory, so that multiple instances may share a spool directory. After
+	// the handler succeeds, the file is moved to the success directory (or is
+	// removed if no success directory is configured). After the handler fails
+	// the configured number of times, the file is moved to the failure directory.
+	Spool struct {
+		Logger       nacelle.Logger            `service:"logger"`
+		Container    *nacelle.ServiceContainer `service:"container"`
+		configToken  interface{}
+		handler      SpoolHandler
+		clock        glock.Clock
+		halt         chan struct{}
+		once         *sync.Once
+		dir          string
+		workDir      string
+		successDir   string
+		failureDir   string
+		maxAttempts  int
+		scanInterval time.Duration
+	}
+
+	// SpoolHandler processes files claimed by a Spool.
+	SpoolHandler interface {
+		Init(nacelle.Config, *Spool) error
+
+		// Handle processes the file at the given path. The file is located
+		// in the spool's work directory for the duration of the call.
+		Handle(path string) error
+	}
+)
+
+var ErrBadSpoolConfig = errors.New("spool config not registered properly")
+
+func NewSpool(handler SpoolHandler, configs ...SpoolConfigFunc) *Spool {
+	return newSpool(handler, glock.NewRealClock(), configs...)
+}
+
+func newSpool(handler SpoolHandler, clock glock.Clock, configs ...SpoolConfigFunc) *Spool {
+	options := getSpoolOptions(configs)
+
+	return &Spool{
+		configToken: options.configToken,
+		handler:     handler,
+		clock:       clock,
+		halt:        make(chan struct{}),
+		once:        &sync.Once{},
+	}
+}
+
+func (s *Spool) Init(config nacelle.Config) error {
+	spoolConfig := &SpoolConfig{}
+	if err := config.Fetch(s.configToken, spoolConfig); err != nil {
+		return ErrBadSpoolConfig
+	}
+
+	s.dir = spoolConfig.SpoolDir
+	s.workDir = spoolConfig.SpoolWorkDir
+	s.successDir = spoolConfig.SpoolSuccessDir
+	s.failureDir = spoolConfig.SpoolFailureDir
+	s.maxAttempts = spoolConfig.SpoolMaxAttempts
+	s.scanInterval = spoolConfig.SpoolScanInterval
+	s.halt = make(chan struct{})
+	s.once = &sync.Once{}
+
+	for _, dir := range []string{s.workDir, s.successDir, s.failureDir} {
+		if dir == "" {
+			continue
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Container.Inject(s.handler); err != nil {
+		return err
+	}
+
+	return s.handler.Init(config, s)
+}
+
+func (s *Spool) Start() error {
+	defer s.Stop()
+
+	s.Logger.Info("Scanning spool directory %s", s.dir)
+
+	for {
+		if err := s.scan(); err != nil {
+			return err
+		}
+
+		select {
+		case <-s.halt:
+			s.Logger.Info("No longer scanning spool directory %s", s.dir)
+			return nil
+		case <-s.clock.After(s.scanInterval):
+		}
+	}
+}
+
+func (s *Spool) Stop() error {
+	s.once.Do(func() { close(s.halt) })
+	return nil
+}
+
+func (s *Spool) scan() error {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		select {
+		case <-s.halt:
+			return nil
+		default:
+		}
+
+		if err := s.process(info.Name()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Spool) process(name string) error {
+	path := filepath.Join(s.workDir, name)
+
+	if err := os.Rename(filepath.Join(s.dir, name), path); err != nil {
+		// Claimed by another instance
+		s.Logger.Debug("Failed to claim spool file %s (%s)", name, err.Error())
+		return nil
+	}
+
+	var err error
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		if err = s.handler.Handle(path); err == nil {
+			break
+		}
+
+		s.Logger.Warning(
+			"Failed to handle spool file %s on attempt %d of %d (%s)",
+			name,
+			attempt,
+			s.maxAttempts,
+			err.Error(),
+		)
+	}
+
+	if err != nil {
+		return os.Rename(path, filepath.Join(s.failureDir, name))
+	}
+
+	if s.successDir == "" {
+		return os.Remove(path)
+	}
+
+	return os.Rename(path, filepath.Join(s.successDir, name))
+}
@@ -0,0 +1,49 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+type (
+	SpoolConfig struct {
+		SpoolDir             string `env:"spool_dir" required:"true"`
+		SpoolWorkDir         string `env:"spool_work_dir"`
+		SpoolSuccessDir      string `env:"spool_success_dir"`
+		SpoolFailureDir      string `env:"spool_failure_dir"`
+		SpoolMaxAttempts     int    `env:"spool_max_attempts" default:"3"`
+		RawSpoolScanInterval int    `env:"spool_scan_interval" default:"5"`
+
+		SpoolScanInterval time.Duration
+	}
+
+	spoolConfigToken string
+)
+
+var (
+	SpoolConfigToken        = MakeSpoolConfigToken("default")
+	ErrIllegalSpoolAttempts = errors.New("spool max attempts must be positive")
+)
+
+func MakeSpoolConfigToken(name string) interface{} {
+	return spoolConfigToken(fmt.Sprintf("nacelle-process-spool-%s", name))
+}
+
+func (c *SpoolConfig) PostLoad() error {
+	if c.SpoolMaxAttempts <= 0 {
+		return ErrIllegalSpoolAttempts
+	}
+
+	if c.SpoolWorkDir == "" {
+		c.SpoolWorkDir = filepath.Join(c.SpoolDir, ".processing")
+	}
+
+	if c.SpoolFailureDir == "" {
+		c.SpoolFailureDir = filepath.Join(c.SpoolDir, ".failed")
+	}
+
+	c.SpoolScanInterval = time.Duration(c.RawSpoolScanInterval) * time.Second
+	return nil
+}
@@ -0,0 +1,28 @@
+package process
+
+type (
+	spoolOptions struct {
+		configToken interface{}
+	}
+
+	// SpoolConfigFunc is a function used to configure an instance of a Spool.
+	SpoolConfigFunc func(*spoolOptions)
+)
+
+// WithSpoolConfigToken sets the config token to use. This is useful if an application
+// has multiple Spool processes running with different configuration tags.
+func WithSpoolConfigToken(token interface{}) SpoolConfigFunc {
+	return func(o *spoolOptions) { o.configToken = token }
+}
+
+func getSpoolOptions(configs []SpoolConfigFunc) *spoolOptions {
+	options := &spoolOptions{
+		configToken: SpoolConfigToken,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
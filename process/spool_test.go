@@ -0,0 +1,85 @@
+package process
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+	"github.com/efritz/nacelle/log"
+)
+
+type SpoolSuite struct{}
+
+func (s *SpoolSuite) TestProcess(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-spool")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		handler = &mockSpoolHandler{failures: map[string]int{"b": 1, "c": 5}}
+		spool   = makeSpool(handler)
+	)
+
+	for _, name := range []string{"a", "b", "c"} {
+		Expect(ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644)).To(BeNil())
+	}
+
+	err = spool.Init(makeConfig(SpoolConfigToken, &SpoolConfig{
+		SpoolDir:        dir,
+		SpoolSuccessDir: filepath.Join(dir, "done"),
+	}))
+
+	Expect(err).To(BeNil())
+	Expect(spool.scan()).To(BeNil())
+
+	Expect(filepath.Join(dir, "done", "a")).To(BeAnExistingFile())
+	Expect(filepath.Join(dir, "done", "b")).To(BeAnExistingFile())
+	Expect(filepath.Join(dir, ".failed", "c")).To(BeAnExistingFile())
+	Expect(handler.handled).To(Equal([]string{"a", "b", "b", "c", "c", "c"}))
+}
+
+func (s *SpoolSuite) TestBadConfig(t sweet.T) {
+	spool := NewSpool(&mockSpoolHandler{})
+	err := spool.Init(makeConfig(SpoolConfigToken, &emptyConfig{}))
+	Expect(err).To(Equal(ErrBadSpoolConfig))
+}
+
+//
+// Helpers
+
+func makeSpool(handler SpoolHandler) *Spool {
+	spool := newSpool(handler, glock.NewMockClock())
+	spool.Logger = log.NewNilLogger()
+	spool.Container = nacelle.NewServiceContainer()
+	return spool
+}
+
+//
+// Mocks
+
+type mockSpoolHandler struct {
+	failures map[string]int
+	handled  []string
+}
+
+func (h *mockSpoolHandler) Init(config nacelle.Config, spool *Spool) error {
+	return nil
+}
+
+func (h *mockSpoolHandler) Handle(path string) error {
+	name := filepath.Base(path)
+	h.handled = append(h.handled, name)
+
+	if h.failures[name] > 0 {
+		h.failures[name]--
+		return errors.New("utoh")
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+
+	"github.com/efritz/nacelle"
+)
+
+type (
+	// Supervisor is a process which owns a set of child processes and restarts
+	// them according to its own strategy when they exit, so that the failure of
+	// one subsystem does not restart the unrelated processes of the application.
+	// A supervisor may itself be the child of another supervisor. If children are
+	// restarted more often than the supervisor's restart intensity allows, the
+	// supervisor stops its remaining children and returns an error, escalating
+	// the failure to its own parent.
+	Supervisor struct {
+		Logger      nacelle.Logger            `service:"logger"`
+		Container   *nacelle.ServiceContainer `service:"container"`
+		children    []*supervisedChild
+		strategy    SupervisorStrategy
+		maxRestarts int
+		window      time.Duration
+		clock       glock.Clock
+		config      nacelle.Config
+		restarts    []time.Time
+		halt        chan struct{}
+		once        *sync.Once
+	}
+
+	// SupervisorStrategy determines which children are restarted when a child
+	// of a supervisor exits.
+	SupervisorStrategy int
+
+	supervisedChild struct {
+		name    string
+		process nacelle.Process
+		running bool
+	}
+
+	childExit struct {
+		child *supervisedChild
+		err   error
+	}
+)
+
+const (
+	// OneForOne restarts only the child which exited.
+	OneForOne SupervisorStrategy = iota
+
+	// OneForAll stops all other children and restarts every child, in order
+	// of registration, when any child exits.
+	OneForAll
+)
+
+func NewSupervisor(configs ...SupervisorConfigFunc) *Supervisor {
+	return newSupervisor(glock.NewRealClock(), configs...)
+}
+
+func newSupervisor(clock glock.Clock, configs ...SupervisorConfigFunc) *Supervisor {
+	options := getSupervisorOptions(configs)
+
+	return &Supervisor{
+		strategy:    options.strategy,
+		maxRestarts: options.maxRestarts,
+		window:      options.window,
+		clock:       clock,
+		halt:        make(chan struct{}),
+		once:        &sync.Once{},
+	}
+}
+
+// RegisterChild registers a process to be supervised. Children are initialized
+// and started in order of registration and are stopped in the reverse order.
+// Children must be registered before the supervisor is initialized.
+func (s *Supervisor) RegisterChild(name string, process nacelle.Process) {
+	s.children = append(s.children, &supervisedChild{
+		name:    name,
+		process: process,
+	})
+}
+
+func (s *Supervisor) Init(config nacelle.Config) error {
+	s.config = config
+	s.restarts = nil
+	s.halt = make(chan struct{})
+	s.once = &sync.Once{}
+
+	for _, child := range s.children {
+		if err := s.Container.Inject(child.process); err != nil {
+			return fmt.Errorf("failed to inject services into %s (%s)", child.name, err.Error())
+		}
+
+		if err := child.process.Init(config); err != nil {
+			return fmt.Errorf("failed to initialize %s (%s)", child.name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s *Supervisor) Start() error {
+	defer s.Stop()
+
+	exits := make(chan childExit, len(s.children))
+	for _, child := range s.children {
+		s.startChild(child, exits)
+	}
+
+	for {
+		select {
+		case <-s.halt:
+			s.stopChildren(exits)
+			return nil
+
+		case exit := <-exits:
+			exit.child.running = false
+
+			if s.isHalted() {
+				s.stopChildren(exits)
+				return nil
+			}
+
+			if err := s.handleExit(exit, exits); err != nil {
+				s.stopChildren(exits)
+				return err
+			}
+		}
+	}
+}
+
+func (s *Supervisor) Stop() (err error) {
+	s.once.Do(func() { close(s.halt) })
+	return
+}
+
+// handleExit restarts children according to the supervisor's strategy after
+// the given child has exited. An error is returned if the restart intensity is
+// exceeded or if a child could not be reinitialized.
+func (s *Supervisor) handleExit(exit childExit, exits chan childExit) error {
+	reason := "exited"
+	if exit.err != nil {
+		reason = fmt.Sprintf("failed (%s)", exit.err.Error())
+	}
+
+	if !s.allowRestart() {
+		return fmt.Errorf(
+			"%s %s after %d restarts within %s, giving up",
+			exit.child.name,
+			reason,
+			s.maxRestarts,
+			s.window,
+		)
+	}
+
+	s.Logger.Warning("Supervised process %s %s, restarting", exit.child.name, reason)
+
+	children := []*supervisedChild{exit.child}
+	if s.strategy == OneForAll {
+		s.stopChildren(exits)
+		children = s.children
+	}
+
+	for _, child := range children {
+		if err := child.process.Init(s.config); err != nil {
+			return fmt.Errorf("failed to reinitialize %s (%s)", child.name, err.Error())
+		}
+
+		s.startChild(child, exits)
+	}
+
+	return nil
+}
+
+// allowRestart records a restart and returns false if the number of restarts
+// within the window exceeds the restart intensity.
+func (s *Supervisor) allowRestart() bool {
+	now := s.clock.Now()
+
+	restarts := []time.Time{}
+	for _, t := range s.restarts {
+		if now.Sub(t) < s.window {
+			restarts = append(restarts, t)
+		}
+	}
+
+	if len(restarts) >= s.maxRestarts {
+		s.restarts = restarts
+		return false
+	}
+
+	s.restarts = append(restarts, now)
+	return true
+}
+
+func (s *Supervisor) startChild(child *supervisedChild, exits chan<- childExit) {
+	s.Logger.Debug("Starting supervised process %s", child.name)
+	child.running = true
+
+	go func() {
+		exits <- childExit{child, child.process.Start()}
+	}()
+}
+
+// stopChildren stops each running child in the reverse order of registration
+// and blocks until they have all exited.
+func (s *Supervisor) stopChildren(exits <-chan childExit) {
+	running := 0
+	for i := len(s.children) - 1; i >= 0; i-- {
+		child := s.children[i]
+		if !child.running {
+			continue
+		}
+
+		running++
+		s.Logger.Debug("Stopping supervised process %s", child.name)
+
+		if err := child.process.Stop(); err != nil {
+			s.Logger.Error("Supervised process %s returned error from stop (%s)", child.name, err.Error())
+		}
+	}
+
+	for ; running > 0; running-- {
+		exit := <-exits
+		exit.child.running = false
+
+		if exit.err != nil {
+			s.Logger.Warning("Supervised process %s returned an error while being stopped (%s)", exit.child.name, exit.err.Error())
+		}
+	}
+}
+
+func (s *Supervisor) isHalted() bool {
+	select {
+	case <-s.halt:
+		return true
+	default:
+		return false
+	}
+}
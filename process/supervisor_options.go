@@ -0,0 +1,44 @@
+package process
+
+import "time"
+
+type (
+	supervisorOptions struct {
+		strategy    SupervisorStrategy
+		maxRestarts int
+		window      time.Duration
+	}
+
+	// SupervisorConfigFunc is a function used to configure an instance of a Supervisor.
+	SupervisorConfigFunc func(*supervisorOptions)
+)
+
+// WithSupervisorStrategy sets the strategy used to restart children. The default
+// is OneForOne.
+func WithSupervisorStrategy(strategy SupervisorStrategy) SupervisorConfigFunc {
+	return func(o *supervisorOptions) { o.strategy = strategy }
+}
+
+// WithSupervisorRestartIntensity sets the number of restarts allowed within the
+// given window. The supervisor gives up once a child exits after this many
+// restarts within the window. The default is five restarts within one minute.
+func WithSupervisorRestartIntensity(maxRestarts int, window time.Duration) SupervisorConfigFunc {
+	return func(o *supervisorOptions) {
+		o.maxRestarts = maxRestarts
+		o.window = window
+	}
+}
+
+func getSupervisorOptions(configs []SupervisorConfigFunc) *supervisorOptions {
+	options := &supervisorOptions{
+		strategy:    OneForOne,
+		maxRestarts: 5,
+		window:      time.Minute,
+	}
+
+	for _, f := range configs {
+		f(options)
+	}
+
+	return options
+}
@@ -0,0 +1,217 @@
+package process
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+
+	"github.com/efritz/nacelle"
+	"github.com/efritz/nacelle/log"
+)
+
+type SupervisorSuite struct{}
+
+func (s *SupervisorSuite) TestOneForOne(t sweet.T) {
+	var (
+		supervisor = makeSupervisor()
+		a          = newSupervisedProcess("a")
+		b          = newSupervisedProcess("b")
+		errChan    = make(chan error)
+	)
+
+	supervisor.RegisterChild("a", a)
+	supervisor.RegisterChild("b", b)
+	Expect(supervisor.Init(makeConfig("unused", &emptyConfig{}))).To(BeNil())
+
+	go func() {
+		errChan <- supervisor.Start()
+	}()
+
+	Eventually(a.starts).Should(Receive())
+	Eventually(b.starts).Should(Receive())
+
+	// Only the failed child is restarted
+	a.fail(errors.New("utoh"))
+	Eventually(a.starts).Should(Receive())
+	Consistently(b.starts).ShouldNot(Receive())
+	Expect(a.getInits()).To(Equal(2))
+	Expect(b.getInits()).To(Equal(1))
+
+	supervisor.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *SupervisorSuite) TestOneForAll(t sweet.T) {
+	var (
+		supervisor = makeSupervisor(WithSupervisorStrategy(OneForAll))
+		a          = newSupervisedProcess("a")
+		b          = newSupervisedProcess("b")
+		errChan    = make(chan error)
+	)
+
+	supervisor.RegisterChild("a", a)
+	supervisor.RegisterChild("b", b)
+	Expect(supervisor.Init(makeConfig("unused", &emptyConfig{}))).To(BeNil())
+
+	go func() {
+		errChan <- supervisor.Start()
+	}()
+
+	Eventually(a.starts).Should(Receive())
+	Eventually(b.starts).Should(Receive())
+
+	// Every child is restarted
+	b.fail(errors.New("utoh"))
+	Eventually(a.starts).Should(Receive())
+	Eventually(b.starts).Should(Receive())
+	Expect(a.getInits()).To(Equal(2))
+	Expect(b.getInits()).To(Equal(2))
+
+	supervisor.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *SupervisorSuite) TestRestartIntensity(t sweet.T) {
+	var (
+		supervisor = makeSupervisor(WithSupervisorRestartIntensity(2, time.Minute))
+		a          = newSupervisedProcess("a")
+		b          = newSupervisedProcess("b")
+		errChan    = make(chan error)
+	)
+
+	supervisor.RegisterChild("a", a)
+	supervisor.RegisterChild("b", b)
+	Expect(supervisor.Init(makeConfig("unused", &emptyConfig{}))).To(BeNil())
+
+	go func() {
+		errChan <- supervisor.Start()
+	}()
+
+	Eventually(b.starts).Should(Receive())
+
+	for i := 0; i < 2; i++ {
+		Eventually(a.starts).Should(Receive())
+		a.fail(errors.New("utoh"))
+	}
+
+	Eventually(a.starts).Should(Receive())
+	a.fail(errors.New("utoh"))
+
+	// Gives up and stops the other children
+	Eventually(errChan).Should(Receive(MatchError("a failed (utoh) after 2 restarts within 1m0s, giving up")))
+	Expect(b.getStops()).To(Equal(1))
+}
+
+func (s *SupervisorSuite) TestRestartIntensityWindow(t sweet.T) {
+	var (
+		clock      = glock.NewMockClock()
+		supervisor = makeSupervisorWithClock(clock, WithSupervisorRestartIntensity(1, time.Minute))
+	)
+
+	Expect(supervisor.allowRestart()).To(BeTrue())
+	Expect(supervisor.allowRestart()).To(BeFalse())
+	clock.Advance(time.Minute)
+	Expect(supervisor.allowRestart()).To(BeTrue())
+}
+
+func (s *SupervisorSuite) TestInitError(t sweet.T) {
+	var (
+		supervisor = makeSupervisor()
+		a          = newSupervisedProcess("a")
+	)
+
+	a.initErr = errors.New("utoh")
+	supervisor.RegisterChild("a", a)
+	Expect(supervisor.Init(makeConfig("unused", &emptyConfig{}))).To(MatchError("failed to initialize a (utoh)"))
+}
+
+//
+// Helpers
+
+func makeSupervisor(configs ...SupervisorConfigFunc) *Supervisor {
+	return makeSupervisorWithClock(glock.NewRealClock(), configs...)
+}
+
+func makeSupervisorWithClock(clock glock.Clock, configs ...SupervisorConfigFunc) *Supervisor {
+	supervisor := newSupervisor(clock, configs...)
+	supervisor.Logger = log.NewNilLogger()
+	supervisor.Container = nacelle.NewServiceContainer()
+	return supervisor
+}
+
+//
+// Mocks
+
+type supervisedProcess struct {
+	name    string
+	initErr error
+	starts  chan struct{}
+	mutex   sync.Mutex
+	inits   int
+	stops   int
+	exit    chan error
+}
+
+func newSupervisedProcess(name string) *supervisedProcess {
+	return &supervisedProcess{
+		name:   name,
+		starts: make(chan struct{}, 10),
+	}
+}
+
+func (p *supervisedProcess) Init(config nacelle.Config) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.inits++
+	p.exit = make(chan error, 1)
+	return p.initErr
+}
+
+func (p *supervisedProcess) Start() error {
+	p.starts <- struct{}{}
+	return <-p.getExit()
+}
+
+func (p *supervisedProcess) Stop() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stops++
+
+	select {
+	case p.exit <- nil:
+	default:
+	}
+
+	return nil
+}
+
+func (p *supervisedProcess) fail(err error) {
+	p.getExit() <- err
+}
+
+func (p *supervisedProcess) getExit() chan error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.exit
+}
+
+func (p *supervisedProcess) getInits() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.inits
+}
+
+func (p *supervisedProcess) getStops() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.stops
+}
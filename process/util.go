@@ -1,15 +1,39 @@
 package process
 
 import (
-	"fmt"
 	"net"
+	"strconv"
+
+	"github.com/efritz/nacelle"
 )
 
-func makeListener(port int) (*net.TCPListener, error) {
-	addr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("0.0.0.0:%d", port))
+// makeListener binds a listener to the given host and port. An empty host binds
+// the listener to all interfaces. If a port registry is available, the port is
+// claimed on behalf of the given key so that collisions are reported and ephemeral
+// ports are published.
+func makeListener(ports *nacelle.Ports, key interface{}, host string, port int) (*net.TCPListener, error) {
+	if ports != nil {
+		return ports.ListenHost(key, host, port)
+	}
+
+	if host == "" {
+		host = "0.0.0.0"
+	}
+
+	addr, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
 	if err != nil {
 		return nil, err
 	}
 
 	return net.ListenTCP("tcp", addr)
 }
+
+// isLoopback returns true if the given host only accepts local connections.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -1,8 +1,10 @@
 package process
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/efritz/glock"
@@ -11,40 +13,152 @@ import (
 )
 
 type (
+	// Worker calls the Tick method of a WorkerSpec on an interval. The interval
+	// is measured from the return of one Tick to the start of the next with a
+	// timer, which uses the monotonic clock, so ticks are not repeated when the
+	// wall clock jumps backwards. If the wall clock jumps forward (or the host is
+	// suspended) by at least one interval while the worker waits, the tick is
+	// missed and is handled according to the worker's MissedTickPolicy.
+	//
+	// A worker configured with execution windows (see the worker_execution_windows
+	// config value) only begins a tick while one of its windows is open, and pauses
+	// until the next window opens otherwise. A tick which is in progress when its
+	// window closes is not interrupted. A window which has closed again by the time
+	// the worker wakes for it (e.g. a window within the hour skipped by a DST
+	// transition) is also handled according to the MissedTickPolicy.
 	Worker struct {
-		Container    *nacelle.ServiceContainer `service:"container"`
-		configToken  interface{}
-		spec         WorkerSpec
-		clock        glock.Clock
-		halt         chan struct{}
-		once         *sync.Once
-		tickInterval time.Duration
+		Container       *nacelle.ServiceContainer `service:"container"`
+		KillSwitches    *nacelle.KillSwitches     `service:"killswitches" optional:"true"`
+		configToken     interface{}
+		killSwitch      string
+		spec            workerSpecInitializer
+		tick            func(context.Context) (TickResult, error)
+		adaptive        bool
+		clock           glock.Clock
+		halt            chan struct{}
+		once            *sync.Once
+		ctx             context.Context
+		cancel          func()
+		tickInterval    time.Duration
+		maxTickInterval time.Duration
+		windows         []ExecutionWindow
+		missedTick      MissedTickPolicy
+		running         int32
 	}
 
 	WorkerSpec interface {
 		Init(nacelle.Config, *Worker) error
-		Tick() error
+
+		// Tick performs one unit of work. The given context is canceled once
+		// the worker is stopped, and should be passed to any blocking calls.
+		Tick(ctx context.Context) error
+	}
+
+	// AdaptiveWorkerSpec is a WorkerSpec whose ticks report whether or not they
+	// found work (see NewAdaptiveWorker).
+	AdaptiveWorkerSpec interface {
+		Init(nacelle.Config, *Worker) error
+
+		// Tick performs one unit of work. See WorkerSpec.
+		Tick(ctx context.Context) (TickResult, error)
+	}
+
+	// TickResult reports whether a tick of an adaptive worker found work.
+	TickResult int
+
+	// MissedTickPolicy determines how a worker handles a tick or an execution
+	// window which was missed while the worker was waiting for it (see the
+	// worker_missed_tick_policy config value).
+	MissedTickPolicy int
+
+	workerSpecInitializer interface {
+		Init(nacelle.Config, *Worker) error
 	}
 )
 
+const (
+	// TickIdle indicates that a tick found no work, and the adaptive worker
+	// should wait longer before the next tick.
+	TickIdle TickResult = iota
+
+	// TickWorkFound indicates that a tick found work, and the adaptive worker
+	// should tick again after its minimum interval.
+	TickWorkFound
+)
+
+const (
+	// MissedTickSkip drops a missed tick and waits for the next one, or for the
+	// next opening of an execution window.
+	MissedTickSkip MissedTickPolicy = iota
+
+	// MissedTickRunImmediately runs a tick as soon as the worker wakes, even if
+	// the window in which it was due has since closed. Several missed ticks are
+	// coalesced into one.
+	MissedTickRunImmediately
+)
+
+// minimumIdleInterval is the first interval an adaptive worker waits after
+// an idle tick when its tick interval is zero.
+const minimumIdleInterval = time.Second
+
+// killSwitchPollInterval is the minimum interval at which a worker whose kill
+// switch is engaged checks whether it has been restored.
+const killSwitchPollInterval = time.Second
+
 var ErrBadWorkerConfig = errors.New("worker config not registered properly")
 
 func NewWorker(spec WorkerSpec, configs ...WorkerConfigFunc) *Worker {
-	return newWorker(spec, glock.NewRealClock())
+	return newWorker(spec, glock.NewRealClock(), configs...)
+}
+
+// NewAdaptiveWorker creates a worker whose interval adapts to the availability
+// of work. After a tick which finds work, the worker waits for its configured
+// tick interval. After each consecutive idle tick, the interval is doubled up to
+// the configured max tick interval. This reduces the load of polling a quiet
+// queue while staying responsive under load.
+func NewAdaptiveWorker(spec AdaptiveWorkerSpec, configs ...WorkerConfigFunc) *Worker {
+	return newAdaptiveWorker(spec, glock.NewRealClock(), configs...)
 }
 
 func newWorker(spec WorkerSpec, clock glock.Clock, configs ...WorkerConfigFunc) *Worker {
+	tick := func(ctx context.Context) (TickResult, error) {
+		return TickWorkFound, spec.Tick(ctx)
+	}
+
+	return makeWorker(spec, tick, false, clock, configs)
+}
+
+func newAdaptiveWorker(spec AdaptiveWorkerSpec, clock glock.Clock, configs ...WorkerConfigFunc) *Worker {
+	return makeWorker(spec, spec.Tick, true, clock, configs)
+}
+
+func makeWorker(
+	spec workerSpecInitializer,
+	tick func(context.Context) (TickResult, error),
+	adaptive bool,
+	clock glock.Clock,
+	configs []WorkerConfigFunc,
+) *Worker {
 	options := getWorkerOptions(configs)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Worker{
 		configToken: options.configToken,
+		killSwitch:  options.killSwitch,
 		spec:        spec,
+		tick:        tick,
+		adaptive:    adaptive,
 		clock:       clock,
 		halt:        make(chan struct{}),
 		once:        &sync.Once{},
+		ctx:         ctx,
+		cancel:      cancel,
 	}
 }
 
+// IsDone returns true if the worker has been stopped.
+//
+// Deprecated: Use the context passed to Tick instead.
 func (w *Worker) IsDone() bool {
 	select {
 	case <-w.HaltChan():
@@ -54,6 +168,9 @@ func (w *Worker) IsDone() bool {
 	}
 }
 
+// HaltChan returns a channel which is closed once the worker has been stopped.
+//
+// Deprecated: Use the context passed to Tick instead.
 func (w *Worker) HaltChan() <-chan struct{} {
 	return w.halt
 }
@@ -65,6 +182,12 @@ func (w *Worker) Init(config nacelle.Config) error {
 	}
 
 	w.tickInterval = workerConfig.WorkerTickInterval
+	w.maxTickInterval = workerConfig.WorkerMaxTickInterval
+	w.windows = workerConfig.WorkerExecutionWindows
+	w.missedTick = workerConfig.WorkerMissedTickPolicy
+	w.halt = make(chan struct{})
+	w.once = &sync.Once{}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
 
 	if err := w.Container.Inject(w.spec); err != nil {
 		return err
@@ -74,25 +197,109 @@ func (w *Worker) Init(config nacelle.Config) error {
 }
 
 func (w *Worker) Start() error {
+	atomic.StoreInt32(&w.running, 1)
+	defer atomic.StoreInt32(&w.running, 0)
 	defer w.Stop()
 
+	var (
+		interval      = w.tickInterval
+		waitingWindow = false
+	)
+
 loop:
 	for {
+		// Wall-clock times (without a monotonic reading) are compared so that
+		// a jump of the wall clock during the wait is observed
+		started := w.clock.Now().Round(0)
+
 		select {
 		case <-w.halt:
 			break loop
-		case <-w.clock.After(w.tickInterval):
+		case <-w.clock.After(interval):
+		}
+
+		var (
+			now     = w.clock.Now().Round(0)
+			elapsed = now.Sub(started)
+		)
+
+		if wait := untilWindowOpens(w.windows, now); wait > 0 {
+			// The window has closed again before the worker woke for it
+			missed := waitingWindow && elapsed >= interval
+
+			if !missed || w.missedTick == MissedTickSkip {
+				interval, waitingWindow = wait, true
+				continue
+			}
+		} else if !waitingWindow && missedTick(interval, elapsed) && w.missedTick == MissedTickSkip {
+			continue
+		}
+
+		waitingWindow = false
+
+		if w.killed() {
+			interval = w.tickInterval
+			if interval < killSwitchPollInterval {
+				interval = killSwitchPollInterval
+			}
+
+			continue
 		}
 
-		if err := w.spec.Tick(); err != nil {
+		result, err := w.tick(w.ctx)
+		if err != nil {
 			return err
 		}
+
+		interval = w.nextInterval(interval, result)
 	}
 
 	return nil
 }
 
 func (w *Worker) Stop() (err error) {
-	w.once.Do(func() { close(w.halt) })
+	w.once.Do(func() {
+		close(w.halt)
+		w.cancel()
+	})
 	return
 }
+
+// CheckCleanup returns an error if the worker's tick loop is still running (see
+// nacelle.VerifyCleanup).
+func (w *Worker) CheckCleanup() error {
+	if atomic.LoadInt32(&w.running) != 0 {
+		return errors.New("tick loop is still running")
+	}
+
+	return nil
+}
+
+// killed returns true if the worker's kill switch is engaged.
+func (w *Worker) killed() bool {
+	return w.killSwitch != "" && w.KillSwitches != nil && !w.KillSwitches.Enabled(w.killSwitch)
+}
+
+// missedTick returns true if at least one further tick was due during a wait of
+// the given interval which took the given (wall-clock) duration.
+func missedTick(interval, elapsed time.Duration) bool {
+	return interval > 0 && elapsed >= interval*2
+}
+
+// nextInterval returns the interval to wait after a tick with the given result.
+func (w *Worker) nextInterval(interval time.Duration, result TickResult) time.Duration {
+	if !w.adaptive || result == TickWorkFound {
+		return w.tickInterval
+	}
+
+	interval *= 2
+	if interval == 0 {
+		interval = minimumIdleInterval
+	}
+
+	if interval > w.maxTickInterval {
+		interval = w.maxTickInterval
+	}
+
+	return interval
+}
@@ -7,15 +7,29 @@ import (
 
 type (
 	WorkerConfig struct {
-		RawWorkerTickInterval int `env:"worker_tick_interval" default:"0"`
+		RawWorkerTickInterval      int    `env:"worker_tick_interval" default:"0"`
+		RawWorkerMaxTickInterval   int    `env:"worker_max_tick_interval" default:"60"`
+		RawWorkerExecutionWindows  string `env:"worker_execution_windows"`
+		RawWorkerExecutionTimezone string `env:"worker_execution_timezone" default:"UTC"`
+		RawWorkerMissedTickPolicy  string `env:"worker_missed_tick_policy" default:"skip"`
 
-		WorkerTickInterval time.Duration
+		WorkerTickInterval     time.Duration
+		WorkerMaxTickInterval  time.Duration
+		WorkerExecutionWindows []ExecutionWindow
+		WorkerMissedTickPolicy MissedTickPolicy
 	}
 
 	workerConfigToken string
 )
 
-var WorkerConfigToken = MakeWorkerConfigToken("default")
+var (
+	WorkerConfigToken = MakeWorkerConfigToken("default")
+
+	missedTickPolicies = map[string]MissedTickPolicy{
+		"skip":            MissedTickSkip,
+		"run-immediately": MissedTickRunImmediately,
+	}
+)
 
 func MakeWorkerConfigToken(name string) interface{} {
 	return workerConfigToken(fmt.Sprintf("nacelle-process-worker-%s", name))
@@ -23,5 +37,29 @@ func MakeWorkerConfigToken(name string) interface{} {
 
 func (c *WorkerConfig) PostLoad() error {
 	c.WorkerTickInterval = time.Duration(c.RawWorkerTickInterval) * time.Second
+	c.WorkerMaxTickInterval = time.Duration(c.RawWorkerMaxTickInterval) * time.Second
+
+	// The max tick interval only applies to adaptive workers
+	if c.WorkerMaxTickInterval < c.WorkerTickInterval {
+		c.WorkerMaxTickInterval = c.WorkerTickInterval
+	}
+
+	location, err := time.LoadLocation(c.RawWorkerExecutionTimezone)
+	if err != nil {
+		return fmt.Errorf("illegal execution timezone `%s`", c.RawWorkerExecutionTimezone)
+	}
+
+	windows, err := parseExecutionWindows(c.RawWorkerExecutionWindows, location)
+	if err != nil {
+		return err
+	}
+
+	policy, ok := missedTickPolicies[c.RawWorkerMissedTickPolicy]
+	if !ok {
+		return fmt.Errorf("illegal missed tick policy `%s`", c.RawWorkerMissedTickPolicy)
+	}
+
+	c.WorkerExecutionWindows = windows
+	c.WorkerMissedTickPolicy = policy
 	return nil
 }
@@ -3,6 +3,7 @@ package process
 type (
 	workerOptions struct {
 		configToken interface{}
+		killSwitch  string
 	}
 
 	// WorkerConfigFunc is a function used to configure an instance of a Worker.
@@ -15,6 +16,13 @@ func WithWorkerConfigToken(token interface{}) WorkerConfigFunc {
 	return func(o *workerOptions) { o.configToken = token }
 }
 
+// WithWorkerKillSwitch sets the feature whose kill switch pauses the worker. While
+// the kill switch is engaged, the worker skips its ticks. The kill switches are
+// read from the service container (see nacelle.KillSwitches).
+func WithWorkerKillSwitch(feature string) WorkerConfigFunc {
+	return func(o *workerOptions) { o.killSwitch = feature }
+}
+
 func getWorkerOptions(configs []WorkerConfigFunc) *workerOptions {
 	options := &workerOptions{
 		configToken: WorkerConfigToken,
@@ -1,7 +1,9 @@
 package process
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/aphistic/sweet"
@@ -23,7 +25,7 @@ func (s *WorkerSuite) TestRunAndStop(t sweet.T) {
 
 	defer close(tickChan)
 
-	spec.tick = func() error {
+	spec.tick = func(ctx context.Context) error {
 		tickChan <- struct{}{}
 		return nil
 	}
@@ -47,6 +49,363 @@ func (s *WorkerSuite) TestRunAndStop(t sweet.T) {
 	Eventually(errChan).Should(Receive(BeNil()))
 }
 
+func (s *WorkerSuite) TestCheckCleanup(t sweet.T) {
+	var (
+		spec    = newMockWorkerSpec()
+		clock   = glock.NewMockClock()
+		worker  = newWorker(spec, clock)
+		errChan = make(chan error)
+	)
+
+	err := worker.Init(makeConfig(WorkerConfigToken, &WorkerConfig{RawWorkerTickInterval: 5}))
+	Expect(err).To(BeNil())
+	Expect(worker.CheckCleanup()).To(BeNil())
+
+	go func() {
+		errChan <- worker.Start()
+	}()
+
+	Eventually(worker.CheckCleanup).Should(MatchError("tick loop is still running"))
+	worker.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+	Expect(worker.CheckCleanup()).To(BeNil())
+}
+
+func (s *WorkerSuite) TestTickContextCanceledOnStop(t sweet.T) {
+	var (
+		spec     = newMockWorkerSpec()
+		clock    = glock.NewMockClock()
+		worker   = newWorker(spec, clock)
+		tickChan = make(chan struct{})
+		errChan  = make(chan error)
+	)
+
+	spec.tick = func(ctx context.Context) error {
+		close(tickChan)
+		<-ctx.Done()
+		return nil
+	}
+
+	err := worker.Init(makeConfig(WorkerConfigToken, &WorkerConfig{}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- worker.Start()
+	}()
+
+	clock.BlockingAdvance(0)
+	Eventually(tickChan).Should(BeClosed())
+
+	// Stop unblocks the in-progress tick
+	worker.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *WorkerSuite) TestAdaptiveInterval(t sweet.T) {
+	var (
+		spec     = newMockAdaptiveWorkerSpec()
+		clock    = glock.NewMockClock()
+		worker   = newAdaptiveWorker(spec, clock)
+		results  = make(chan TickResult, 1)
+		tickChan = make(chan struct{}, 1)
+		errChan  = make(chan error)
+	)
+
+	spec.tick = func(ctx context.Context) (TickResult, error) {
+		tickChan <- struct{}{}
+		return <-results, nil
+	}
+
+	os.Setenv("WORKER_TICK_INTERVAL", "1")
+	os.Setenv("WORKER_MAX_TICK_INTERVAL", "4")
+	defer os.Unsetenv("WORKER_TICK_INTERVAL")
+	defer os.Unsetenv("WORKER_MAX_TICK_INTERVAL")
+
+	err := worker.Init(makeConfig(WorkerConfigToken, &WorkerConfig{}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- worker.Start()
+	}()
+
+	// Idle ticks back off exponentially up to the max
+	for _, interval := range []int{1, 2, 4, 4} {
+		results <- TickIdle
+		clock.BlockingAdvance(time.Second * time.Duration(interval-1))
+		Consistently(tickChan).ShouldNot(Receive())
+		clock.BlockingAdvance(time.Second)
+		Eventually(tickChan).Should(Receive())
+	}
+
+	// Finding work resets the interval
+	results <- TickWorkFound
+	clock.BlockingAdvance(time.Second * 4)
+	Eventually(tickChan).Should(Receive())
+
+	results <- TickIdle
+	clock.BlockingAdvance(time.Second)
+	Eventually(tickChan).Should(Receive())
+
+	results <- TickIdle
+	worker.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *WorkerSuite) TestExecutionWindow(t sweet.T) {
+	var (
+		spec     = newMockWorkerSpec()
+		clock    = glock.NewMockClockAt(time.Date(2018, 1, 1, 1, 0, 0, 0, time.UTC))
+		worker   = newWorker(spec, clock)
+		tickChan = make(chan struct{}, 1)
+		errChan  = make(chan error)
+	)
+
+	spec.tick = func(ctx context.Context) error {
+		tickChan <- struct{}{}
+		return nil
+	}
+
+	os.Setenv("WORKER_TICK_INTERVAL", "60")
+	os.Setenv("WORKER_EXECUTION_WINDOWS", "02:00-04:00")
+	defer os.Unsetenv("WORKER_TICK_INTERVAL")
+	defer os.Unsetenv("WORKER_EXECUTION_WINDOWS")
+
+	err := worker.Init(makeConfig(WorkerConfigToken, &WorkerConfig{}))
+	Expect(err).To(BeNil())
+
+	go func() {
+		errChan <- worker.Start()
+	}()
+
+	// Paused until the window opens
+	clock.BlockingAdvance(time.Minute)
+	Consistently(tickChan).ShouldNot(Receive())
+	clock.BlockingAdvance(time.Minute * 58)
+	Consistently(tickChan).ShouldNot(Receive())
+	clock.BlockingAdvance(time.Minute)
+	Eventually(tickChan).Should(Receive())
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(tickChan).Should(Receive())
+
+	// Paused again once the window closes
+	clock.BlockingAdvance(time.Hour * 2)
+	Consistently(tickChan).ShouldNot(Receive())
+	clock.BlockingAdvance(time.Hour * 22)
+	Eventually(tickChan).Should(Receive())
+
+	worker.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *WorkerSuite) TestMissedTick(t sweet.T) {
+	for _, policy := range []string{"skip", "run-immediately"} {
+		var (
+			spec     = newMockWorkerSpec()
+			clock    = glock.NewMockClock()
+			worker   = newWorker(spec, clock)
+			tickChan = make(chan struct{}, 1)
+			errChan  = make(chan error)
+		)
+
+		spec.tick = func(ctx context.Context) error {
+			tickChan <- struct{}{}
+			return nil
+		}
+
+		os.Setenv("WORKER_TICK_INTERVAL", "5")
+		os.Setenv("WORKER_MISSED_TICK_POLICY", policy)
+
+		err := worker.Init(makeConfig(WorkerConfigToken, &WorkerConfig{}))
+		Expect(err).To(BeNil())
+
+		go func() {
+			errChan <- worker.Start()
+		}()
+
+		// The wall clock jumps past two ticks while waiting for the first
+		clock.BlockingAdvance(time.Second * 15)
+
+		if policy == "skip" {
+			Consistently(tickChan).ShouldNot(Receive())
+			clock.BlockingAdvance(time.Second * 5)
+		}
+
+		Eventually(tickChan).Should(Receive())
+		Consistently(tickChan).ShouldNot(Receive())
+
+		worker.Stop()
+		Eventually(errChan).Should(Receive(BeNil()))
+	}
+
+	os.Unsetenv("WORKER_TICK_INTERVAL")
+	os.Unsetenv("WORKER_MISSED_TICK_POLICY")
+}
+
+func (s *WorkerSuite) TestMissedExecutionWindow(t sweet.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	Expect(err).To(BeNil())
+
+	for _, policy := range []string{"skip", "run-immediately"} {
+		var (
+			spec     = newMockWorkerSpec()
+			clock    = glock.NewMockClockAt(time.Date(2018, 3, 11, 1, 0, 0, 0, chicago))
+			worker   = newWorker(spec, clock)
+			tickChan = make(chan struct{}, 1)
+			errChan  = make(chan error)
+		)
+
+		spec.tick = func(ctx context.Context) error {
+			tickChan <- struct{}{}
+			return nil
+		}
+
+		os.Setenv("WORKER_TICK_INTERVAL", "60")
+		os.Setenv("WORKER_EXECUTION_WINDOWS", "02:00-02:30")
+		os.Setenv("WORKER_EXECUTION_TIMEZONE", "America/Chicago")
+		os.Setenv("WORKER_MISSED_TICK_POLICY", policy)
+
+		err := worker.Init(makeConfig(WorkerConfigToken, &WorkerConfig{}))
+		Expect(err).To(BeNil())
+
+		go func() {
+			errChan <- worker.Start()
+		}()
+
+		// The window lies within the hour skipped by the DST transition, so it
+		// has closed by the time the worker wakes for it at 03:00
+		clock.BlockingAdvance(time.Minute)
+		clock.BlockingAdvance(time.Minute * 59)
+
+		if policy == "skip" {
+			Consistently(tickChan).ShouldNot(Receive())
+		} else {
+			Eventually(tickChan).Should(Receive())
+		}
+
+		// Paused until the window opens the next day
+		clock.BlockingAdvance(time.Minute)
+		Consistently(tickChan).ShouldNot(Receive())
+
+		worker.Stop()
+		Eventually(errChan).Should(Receive(BeNil()))
+	}
+
+	os.Unsetenv("WORKER_TICK_INTERVAL")
+	os.Unsetenv("WORKER_EXECUTION_WINDOWS")
+	os.Unsetenv("WORKER_EXECUTION_TIMEZONE")
+	os.Unsetenv("WORKER_MISSED_TICK_POLICY")
+}
+
+func (s *WorkerSuite) TestExecutionWindowContains(t sweet.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	Expect(err).To(BeNil())
+
+	window, err := NewExecutionWindow("02:00", "04:00", chicago)
+	Expect(err).To(BeNil())
+
+	// 02:30 in Chicago is 08:30 UTC in winter
+	Expect(window.Contains(time.Date(2018, 1, 1, 8, 30, 0, 0, time.UTC))).To(BeTrue())
+	Expect(window.Contains(time.Date(2018, 1, 1, 2, 30, 0, 0, time.UTC))).To(BeFalse())
+	Expect(window.Contains(time.Date(2018, 1, 1, 4, 0, 0, 0, chicago))).To(BeFalse())
+
+	// Spans midnight
+	overnight, err := NewExecutionWindow("22:00", "02:00", time.UTC)
+	Expect(err).To(BeNil())
+	Expect(overnight.Contains(time.Date(2018, 1, 1, 23, 0, 0, 0, time.UTC))).To(BeTrue())
+	Expect(overnight.Contains(time.Date(2018, 1, 1, 1, 0, 0, 0, time.UTC))).To(BeTrue())
+	Expect(overnight.Contains(time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC))).To(BeFalse())
+}
+
+func (s *WorkerSuite) TestUntilWindowOpens(t sweet.T) {
+	windows, err := parseExecutionWindows("02:00-04:00, 12:00-13:00", time.UTC)
+	Expect(err).To(BeNil())
+	Expect(windows).To(HaveLen(2))
+
+	Expect(untilWindowOpens(nil, time.Now())).To(Equal(time.Duration(0)))
+	Expect(untilWindowOpens(windows, time.Date(2018, 1, 1, 3, 0, 0, 0, time.UTC))).To(Equal(time.Duration(0)))
+	Expect(untilWindowOpens(windows, time.Date(2018, 1, 1, 5, 0, 0, 0, time.UTC))).To(Equal(time.Hour * 7))
+	Expect(untilWindowOpens(windows, time.Date(2018, 1, 1, 13, 30, 0, 0, time.UTC))).To(Equal(time.Hour*12 + time.Minute*30))
+
+	chicago, err := time.LoadLocation("America/Chicago")
+	Expect(err).To(BeNil())
+	windows, err = parseExecutionWindows("02:30-03:15", chicago)
+	Expect(err).To(BeNil())
+
+	// 02:30 is skipped by the DST transition, so the window opens at 03:00
+	Expect(untilWindowOpens(windows, time.Date(2018, 3, 11, 1, 0, 0, 0, chicago))).To(Equal(time.Hour))
+}
+
+func (s *WorkerSuite) TestIllegalExecutionWindows(t sweet.T) {
+	c := &WorkerConfig{RawWorkerExecutionWindows: "02:00"}
+	Expect(c.PostLoad()).To(MatchError("illegal execution window `02:00`"))
+
+	c = &WorkerConfig{RawWorkerExecutionWindows: "02:00-25:00"}
+	Expect(c.PostLoad()).To(MatchError("illegal time of day `25:00`"))
+
+	c = &WorkerConfig{RawWorkerExecutionWindows: "02:00-02:00"}
+	Expect(c.PostLoad()).To(MatchError("execution window 02:00-02:00 is empty"))
+
+	c = &WorkerConfig{RawWorkerExecutionWindows: "02:00-04:00", RawWorkerExecutionTimezone: "Mars/Olympus"}
+	Expect(c.PostLoad()).To(MatchError("illegal execution timezone `Mars/Olympus`"))
+}
+
+func (s *WorkerSuite) TestIllegalMissedTickPolicy(t sweet.T) {
+	c := &WorkerConfig{RawWorkerExecutionTimezone: "UTC", RawWorkerMissedTickPolicy: "sometimes"}
+	Expect(c.PostLoad()).To(MatchError("illegal missed tick policy `sometimes`"))
+}
+
+func (s *WorkerSuite) TestKillSwitch(t sweet.T) {
+	var (
+		spec     = newMockWorkerSpec()
+		clock    = glock.NewMockClock()
+		worker   = newWorker(spec, clock, WithWorkerKillSwitch("reports"))
+		switches = nacelle.NewKillSwitches("reports")
+		tickChan = make(chan struct{}, 1)
+		errChan  = make(chan error)
+	)
+
+	spec.tick = func(ctx context.Context) error {
+		tickChan <- struct{}{}
+		return nil
+	}
+
+	os.Setenv("WORKER_TICK_INTERVAL", "5")
+	defer os.Unsetenv("WORKER_TICK_INTERVAL")
+
+	err := worker.Init(makeConfig(WorkerConfigToken, &WorkerConfig{}))
+	Expect(err).To(BeNil())
+	worker.KillSwitches = switches
+
+	go func() {
+		errChan <- worker.Start()
+	}()
+
+	// Ticks are skipped while the kill switch is engaged
+	clock.BlockingAdvance(time.Second * 5)
+	Consistently(tickChan).ShouldNot(Receive())
+	clock.BlockingAdvance(time.Second * 5)
+	Consistently(tickChan).ShouldNot(Receive())
+
+	switches.Restore("reports")
+	clock.BlockingAdvance(time.Second * 5)
+	Eventually(tickChan).Should(Receive())
+
+	worker.Stop()
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *WorkerSuite) TestNextInterval(t sweet.T) {
+	worker := &Worker{adaptive: true, maxTickInterval: time.Second * 5}
+	Expect(worker.nextInterval(0, TickIdle)).To(Equal(time.Second))
+	Expect(worker.nextInterval(time.Second*2, TickIdle)).To(Equal(time.Second * 4))
+	Expect(worker.nextInterval(time.Second*4, TickIdle)).To(Equal(time.Second * 5))
+	Expect(worker.nextInterval(time.Second*4, TickWorkFound)).To(Equal(time.Duration(0)))
+
+	worker = &Worker{tickInterval: time.Second * 3, maxTickInterval: time.Second * 5}
+	Expect(worker.nextInterval(time.Second*3, TickIdle)).To(Equal(time.Second * 3))
+}
+
 func (s *WorkerSuite) TestBadConfig(t sweet.T) {
 	worker := NewWorker(newMockWorkerSpec())
 	err := worker.Init(makeConfig(WorkerConfigToken, &emptyConfig{}))
@@ -84,7 +443,7 @@ func (s *WorkerSuite) TestTickError(t sweet.T) {
 		errChan = make(chan error)
 	)
 
-	spec.tick = func() error {
+	spec.tick = func(ctx context.Context) error {
 		return fmt.Errorf("utoh")
 	}
 
@@ -105,18 +464,35 @@ func (s *WorkerSuite) TestTickError(t sweet.T) {
 
 type mockSpec struct {
 	init func(nacelle.Config, *Worker) error
-	tick func() error
+	tick func(context.Context) error
 }
 
 func newMockWorkerSpec() *mockSpec {
 	return &mockSpec{
 		init: func(nacelle.Config, *Worker) error { return nil },
-		tick: func() error { return nil },
+		tick: func(context.Context) error { return nil },
 	}
 }
 
 func (s *mockSpec) Init(c nacelle.Config, w *Worker) error { return s.init(c, w) }
-func (s *mockSpec) Tick() error                            { return s.tick() }
+func (s *mockSpec) Tick(ctx context.Context) error         { return s.tick(ctx) }
+
+type mockAdaptiveSpec struct {
+	init func(nacelle.Config, *Worker) error
+	tick func(context.Context) (TickResult, error)
+}
+
+func newMockAdaptiveWorkerSpec() *mockAdaptiveSpec {
+	return &mockAdaptiveSpec{
+		init: func(nacelle.Config, *Worker) error { return nil },
+		tick: func(context.Context) (TickResult, error) { return TickIdle, nil },
+	}
+}
+
+func (s *mockAdaptiveSpec) Init(c nacelle.Config, w *Worker) error { return s.init(c, w) }
+func (s *mockAdaptiveSpec) Tick(ctx context.Context) (TickResult, error) {
+	return s.tick(ctx)
+}
 
 //
 // Bad Injection
@@ -126,4 +502,4 @@ type badInjectWorkerSpec struct {
 }
 
 func (s *badInjectWorkerSpec) Init(c nacelle.Config, w *Worker) error { return nil }
-func (s *badInjectWorkerSpec) Tick() error                            { return nil }
+func (s *badInjectWorkerSpec) Tick(ctx context.Context) error         { return nil }
@@ -0,0 +1,172 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExecutionWindow is a daily period of wall-clock time in a particular time zone
+// (e.g. from 02:00 to 04:00 in America/Chicago). A window whose end precedes its
+// start spans midnight. On the day of a DST transition, a window is open for each
+// occurrence of a repeated hour, and a window which lies entirely within a skipped
+// hour does not open (see MissedTickPolicy). See the worker_execution_windows
+// config value.
+type ExecutionWindow struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// NewExecutionWindow creates an execution window from the given start and end times
+// of day, formatted as HH:MM, in the given location.
+func NewExecutionWindow(start, end string, location *time.Location) (ExecutionWindow, error) {
+	startOffset, err := parseTimeOfDay(start)
+	if err != nil {
+		return ExecutionWindow{}, err
+	}
+
+	endOffset, err := parseTimeOfDay(end)
+	if err != nil {
+		return ExecutionWindow{}, err
+	}
+
+	if startOffset == endOffset {
+		return ExecutionWindow{}, fmt.Errorf("execution window %s-%s is empty", start, end)
+	}
+
+	return ExecutionWindow{
+		Start:    startOffset,
+		End:      endOffset,
+		Location: location,
+	}, nil
+}
+
+// Contains returns true if the given time falls within the window.
+func (w ExecutionWindow) Contains(t time.Time) bool {
+	offset := timeOfDay(t.In(w.Location))
+
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+
+	return offset >= w.Start || offset < w.End
+}
+
+// untilOpen returns the duration from the given time until the window next
+// opens, or zero if the window is open.
+func (w ExecutionWindow) untilOpen(t time.Time) time.Duration {
+	if w.Contains(t) {
+		return 0
+	}
+
+	var (
+		local = t.In(w.Location)
+		start = w.opening(local.Year(), local.Month(), local.Day())
+	)
+
+	if !start.After(local) {
+		start = w.opening(local.Year(), local.Month(), local.Day()+1)
+	}
+
+	return start.Sub(t)
+}
+
+// opening returns the instant at which the window opens on the given day.
+func (w ExecutionWindow) opening(year int, month time.Month, day int) time.Time {
+	var (
+		hour   = int(w.Start / time.Hour)
+		minute = int(w.Start % time.Hour / time.Minute)
+		start  = time.Date(year, month, day, hour, minute, 0, 0, w.Location)
+	)
+
+	// A start within the hour skipped by a DST transition does not exist and is
+	// normalized to before the transition, so the window opens at the transition
+	if offset := timeOfDay(start); offset < w.Start {
+		start = zoneTransition(start, start.Add(w.Start-offset))
+	}
+
+	return start
+}
+
+// zoneTransition returns the first instant after from (to the second) at which
+// the zone offset differs from that of from, where the offset at to differs.
+func zoneTransition(from, to time.Time) time.Time {
+	_, offset := from.Zone()
+
+	lo, hi := int64(0), int64(to.Sub(from)/time.Second)
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+
+		if _, midOffset := from.Add(time.Duration(mid) * time.Second).Zone(); midOffset == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return from.Add(time.Duration(hi) * time.Second)
+}
+
+// parseExecutionWindows parses a comma-separated list of windows formatted as
+// HH:MM-HH:MM in the given location.
+func parseExecutionWindows(value string, location *time.Location) ([]ExecutionWindow, error) {
+	windows := []ExecutionWindow{}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.Split(part, "-")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("illegal execution window `%s`", part)
+		}
+
+		window, err := NewExecutionWindow(strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1]), location)
+		if err != nil {
+			return nil, err
+		}
+
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+// untilWindowOpens returns the duration from the given time until any of the
+// given windows next opens. Zero is returned if a window is open or if there
+// are no windows.
+func untilWindowOpens(windows []ExecutionWindow, t time.Time) time.Duration {
+	var min time.Duration
+
+	for i, window := range windows {
+		wait := window.untilOpen(t)
+		if wait == 0 {
+			return 0
+		}
+
+		if i == 0 || wait < min {
+			min = wait
+		}
+	}
+
+	return min
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("illegal time of day `%s`", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+}
@@ -0,0 +1,116 @@
+package nacelle
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// ProgressReporter tracks the progress of a long-running Init method. The
+	// process runner injects a reporter into each initializer and process with
+	// a field tagged `service:"progress"` and periodically logs its progress
+	// until the Init method returns, so that a slow boot is not silent.
+	ProgressReporter struct {
+		name    string
+		mutex   sync.RWMutex
+		started time.Time
+		step    int
+		steps   int
+		message string
+	}
+
+	// Progress is a snapshot of a progress reporter.
+	Progress struct {
+		Name    string
+		Elapsed time.Duration
+		Step    int
+		Steps   int
+		Message string
+	}
+)
+
+// NewProgressReporter creates a progress reporter for the initializer or
+// process with the given name.
+func NewProgressReporter(name string) *ProgressReporter {
+	return &ProgressReporter{
+		name:    name,
+		started: time.Now(),
+	}
+}
+
+// SetSteps sets the total number of steps expected to complete.
+func (r *ProgressReporter) SetSteps(steps int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.steps = steps
+}
+
+// Step advances the reporter to the next step, described by the given message.
+func (r *ProgressReporter) Step(message string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.step++
+	r.message = message
+}
+
+// Progress returns a snapshot of the reporter.
+func (r *ProgressReporter) Progress() Progress {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return Progress{
+		Name:    r.name,
+		Elapsed: time.Since(r.started),
+		Step:    r.step,
+		Steps:   r.steps,
+		Message: r.message,
+	}
+}
+
+// reset clears the steps of the reporter before the Init method is re-run.
+func (r *ProgressReporter) reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.started = time.Now()
+	r.step = 0
+	r.steps = 0
+	r.message = ""
+}
+
+func (p Progress) String() string {
+	details := fmt.Sprintf("%s elapsed", p.Elapsed/time.Second*time.Second)
+
+	if step := p.step(); step != "" {
+		details = fmt.Sprintf("%s, %s", details, step)
+	}
+
+	return fmt.Sprintf("%s (%s)", p.Name, details)
+}
+
+// step describes the current step of the snapshot, or returns an empty
+// string if no step has been reported.
+func (p Progress) step() string {
+	switch {
+	case p.Steps > 0 && p.Message != "":
+		return fmt.Sprintf("step %d/%d, %s", p.Step, p.Steps, p.Message)
+	case p.Steps > 0:
+		return fmt.Sprintf("step %d/%d", p.Step, p.Steps)
+	default:
+		return p.Message
+	}
+}
+
+// Fields returns the snapshot as log fields.
+func (p Progress) Fields() Fields {
+	return Fields{
+		"name":    p.Name,
+		"elapsed": p.Elapsed.Seconds(),
+		"step":    p.Step,
+		"steps":   p.Steps,
+		"message": p.Message,
+	}
+}
@@ -0,0 +1,138 @@
+package nacelle
+
+import (
+	"time"
+)
+
+type (
+	// RestartPolicy determines whether the process runner restarts a process
+	// whose Start method has returned instead of shutting down the application.
+	RestartPolicy struct {
+		// Condition determines which exits cause a restart.
+		Condition RestartCondition
+
+		// MaxAttempts is the maximum number of times the process is restarted.
+		// Once this limit is reached, the next exit is handled as if the process
+		// had no restart policy. A value of zero allows unlimited restarts.
+		MaxAttempts int
+
+		// Backoff is the delay before the first restart. The delay doubles with
+		// each subsequent restart.
+		Backoff time.Duration
+
+		// MaxBackoff is the upper bound of the delay between restarts. A value of
+		// zero leaves the delay unbounded.
+		MaxBackoff time.Duration
+
+		// ResetAfter is the duration for which a restarted process must run before
+		// its restart count is reset, so that MaxAttempts and the backoff apply to
+		// consecutive restarts rather than to the lifetime of the process. A value
+		// of zero never resets the count.
+		ResetAfter time.Duration
+	}
+
+	// RestartCondition determines which exits of a process cause a restart.
+	RestartCondition int
+)
+
+const (
+	// RestartNever does not restart the process.
+	RestartNever RestartCondition = iota
+
+	// RestartOnFailure restarts the process if Start returns a non-nil error.
+	RestartOnFailure
+
+	// RestartAlways restarts the process whenever Start returns.
+	RestartAlways
+)
+
+// ShouldRestart returns true if an exit with the given error causes a restart.
+func (p RestartPolicy) ShouldRestart(err error) bool {
+	switch p.Condition {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+func (p RestartPolicy) exhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+func (p RestartPolicy) delay(attempts int) time.Duration {
+	delay := p.Backoff
+	for i := 0; i < attempts; i++ {
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+
+		delay *= 2
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return delay
+}
+
+// restartAfterExit re-initializes a process whose Start method has returned
+// with the given error after running for the given duration, according to the
+// process's restart policy. Returns true if the process should be started again.
+// Otherwise, returns the error which should be reported for the process.
+func (pr *ProcessRunner) restartAfterExit(process *processMeta, err error, ran time.Duration) (bool, error) {
+	policy := process.restartPolicy
+
+	if policy.ResetAfter > 0 && ran >= policy.ResetAfter {
+		process.restarts = 0
+	}
+
+	if err == nil {
+		pr.flushFailures(process)
+	}
+
+	for policy.ShouldRestart(err) && !pr.isStopping() {
+		if policy.exhausted(process.restarts) {
+			pr.flushFailures(process)
+			pr.logger.Error("%s has exhausted its %d restart attempts", process.Name(), policy.MaxAttempts)
+			return false, err
+		}
+
+		if pr.recordRestart(process) {
+			return pr.escalateCrashLoop(process, err)
+		}
+
+		delay := policy.delay(process.restarts)
+		process.restarts++
+		pr.record("Restarting %s in %s (attempt %d)", process.Name(), delay, process.restarts)
+		pr.emit(EventRestarting, process.Name(), process.tags, err)
+
+		if err != nil {
+			pr.logFailure(process, err, delay)
+		} else {
+			pr.logger.Info("%s exited, restarting in %s", process.Name(), delay)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-pr.ctx.Done():
+			return false, err
+		}
+
+		if err = pr.initProcess(process); err == nil {
+			return true, nil
+		}
+	}
+
+	return false, err
+}
+
+func (pr *ProcessRunner) isStopping() bool {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	return pr.stopping
+}
@@ -1,13 +1,14 @@
 package nacelle
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"sort"
 	"sync"
-	"syscall"
 	"time"
 )
 
@@ -22,8 +23,55 @@ type (
 		done         chan struct{}
 		halt         chan struct{}
 		once         *sync.Once
+		config       Config
+		logger       Logger
+		wg           *sync.WaitGroup
+		startErrors  chan errMeta
+		replicaSets  map[string]*replicaSet
+		mutex        watchedMutex
+		running      bool
+		stopping     bool
+
+		progressInterval   time.Duration
+		initializing       map[*ProgressReporter]struct{}
+		breakpoint         BreakpointFunc
+		failureLogInterval time.Duration
+		crashLoopHook      CrashLoopHook
+		degraded           map[string]struct{}
+		ctx                context.Context
+		cancel             func()
+		bootCtx            context.Context
+		recorder           *FlightRecorder
+		crashOutput        io.Writer
+		watchdogTimeout    time.Duration
+		activities         map[*activity]struct{}
+		activityMutex      sync.Mutex
+		signals            []os.Signal
+		drainTimeout       time.Duration
+		drainReporter      DrainReporter
+		operationGrace     time.Duration
+		logSyncTimeout     time.Duration
+		subscribers        subscribers
+		timeline           *Timeline
+		timelinePath       string
+		strictServices     bool
+		errorBufferSize    int
+		delivery           *deliveryCounters
+		groupStartedHooks  map[int][]GroupStartedHook
+		registered         map[registration]string
+		initializerTypes   map[string]InitializerFactory
+		processTypes       map[string]ProcessFactory
+		duplicates         []duplicate
 	}
 
+	// ProcessRunnerConfigFunc is a function used to configure an instance of
+	// a ProcessRunner.
+	ProcessRunnerConfigFunc func(*ProcessRunner)
+
+	// ProcessFactory creates a new instance of a process. A factory is used to
+	// register a process which may have multiple replicas.
+	ProcessFactory func() Process
+
 	errMeta struct {
 		err     error
 		process *processMeta
@@ -33,19 +81,45 @@ type (
 var ErrInitTimeout = fmt.Errorf("init method did not finish within timeout")
 
 // NewProcessRunner creates a new process runner with the given service container.
-func NewProcessRunner(container *ServiceContainer) *ProcessRunner {
-	return &ProcessRunner{
-		container:    container,
-		initializers: []*initializerMeta{},
-		processes:    map[int][]*processMeta{},
-		done:         make(chan struct{}),
-		halt:         make(chan struct{}),
-		once:         &sync.Once{},
+func NewProcessRunner(container *ServiceContainer, runnerConfigs ...ProcessRunnerConfigFunc) *ProcessRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pr := &ProcessRunner{
+		container:          container,
+		initializers:       []*initializerMeta{},
+		processes:          map[int][]*processMeta{},
+		replicaSets:        map[string]*replicaSet{},
+		done:               make(chan struct{}),
+		halt:               make(chan struct{}),
+		once:               &sync.Once{},
+		progressInterval:   defaultProgressInterval,
+		failureLogInterval: defaultFailureLogInterval,
+		initializing:       map[*ProgressReporter]struct{}{},
+		degraded:           map[string]struct{}{},
+		activities:         map[*activity]struct{}{},
+		groupStartedHooks:  map[int][]GroupStartedHook{},
+		registered:         map[registration]string{},
+		initializerTypes:   map[string]InitializerFactory{},
+		processTypes:       map[string]ProcessFactory{},
+		signals:            shutdownSignals,
+		logSyncTimeout:     defaultLogSyncTimeout,
+		errorBufferSize:    defaultErrorBufferSize,
+		delivery:           &deliveryCounters{},
+		ctx:                ctx,
+		cancel:             cancel,
+	}
+
+	for _, f := range runnerConfigs {
+		f(pr)
 	}
+
+	return pr
 }
 
 // RegisterInitializer registers an initializer with the given configuration. The
-// order the initializers are run mirrors the order of registration.
+// order the initializers are run mirrors the order of registration. Registering
+// the same initializer instance (or an initializer with the same key, see the
+// WithInitializerKey option) more than once has no effect.
 func (pr *ProcessRunner) RegisterInitializer(initializer Initializer, initializerConfigs ...InitializerConfigFunc) {
 	meta := &initializerMeta{Initializer: initializer}
 
@@ -53,11 +127,17 @@ func (pr *ProcessRunner) RegisterInitializer(initializer Initializer, initialize
 		f(meta)
 	}
 
+	if !pr.registerOnce("initializer", meta.key, initializer, meta.Name()) {
+		return
+	}
+
 	pr.initializers = append(pr.initializers, meta)
 }
 
 // RegisterProcess registers a process with the given configuration. The order
-// of process registration is arbitrary.
+// of process registration is arbitrary. Registering the same process instance (or
+// a process with the same key, see the WithProcessKey option) more than once has
+// no effect.
 func (pr *ProcessRunner) RegisterProcess(process Process, processConfigs ...ProcessConfigFunc) {
 	meta := &processMeta{Process: process}
 
@@ -65,12 +145,58 @@ func (pr *ProcessRunner) RegisterProcess(process Process, processConfigs ...Proc
 		f(meta)
 	}
 
+	if !pr.registerOnce("process", meta.key, process, meta.Name()) {
+		return
+	}
+
+	pr.addProcess(meta)
+}
+
+// RegisterProcessFactory registers a process factory with the given configuration.
+// The factory is called once for each replica of the process (see WithReplicas),
+// and each instance is registered as if by RegisterProcess. Each instance can
+// be injected with its replica index via a field tagged `service:"replica"`.
+// The number of replicas can be changed at runtime via the Scale method, which
+// identifies the set of replicas by the process name. A factory registered without
+// a name (see WithProcessName) cannot be scaled.
+func (pr *ProcessRunner) RegisterProcessFactory(factory ProcessFactory, processConfigs ...ProcessConfigFunc) {
+	template := &processMeta{replicas: 1}
+
+	for _, f := range processConfigs {
+		f(template)
+	}
+
+	set := &replicaSet{
+		factory: factory,
+		configs: processConfigs,
+	}
+
+	// Unnamed factories are keyed by registration order so that they do not
+	// replace each other, and are not addressable by Scale
+	key := template.name
+	if key == "" {
+		key = fmt.Sprintf("<unnamed factory %d>", len(pr.replicaSets))
+	}
+
+	pr.replicaSets[key] = set
+
+	for i := 0; i < template.replicas; i++ {
+		pr.addProcess(set.makeReplica(i))
+	}
+}
+
+func (pr *ProcessRunner) addProcess(meta *processMeta) {
+	pr.mutex.Lock()
+
 	if _, ok := pr.processes[meta.priority]; !ok {
 		pr.processes[meta.priority] = []*processMeta{}
 	}
 
 	pr.numProcesses++
 	pr.processes[meta.priority] = append(pr.processes[meta.priority], meta)
+	pr.mutex.Unlock()
+
+	pr.emit(EventProcessRegistered, meta.Name(), meta.tags, nil)
 }
 
 // Run will run the registered initializers and processes with the given loaded
@@ -81,50 +207,97 @@ func (pr *ProcessRunner) RegisterProcess(process Process, processConfigs ...Proc
 // initializer and then its Init method is called. Initializers are run one at a
 // time and an error from an initializer will cause an immediate return from Run.
 //
-// For each processes set with the same priority (lowest to highest): services are
-// injected into each process and each Init method is called. Init methods are called
-// one at a time and in the order of process registration. If an Init method returns
-// an error, all lower-priority processes are stopped. Then, the Start method for each
-// process is called concurrently in its own goroutine.
+// Services are then injected into every process. If injection fails for any
+// process, a StartupReport describing every failed injection is returned and no
+// process is initialized.
+//
+// For each processes set with the same priority (lowest to highest): each Init method
+// is called. Init methods are called one at a time and in the order of process
+// registration. If an Init method returns an error, all lower-priority processes are
+// stopped. Then, the Start method for each process is called concurrently in its own
+// goroutine.
 //
 // If any process returns a non-nil error from Start, all running processes will be
 // stopped. If a process return a nil error and has not been configured for silent exit,
 // the same behavior will occur.
 //
-// Receiving an external signal (SIGINT or SIGTERM) will also start a graceful shutdown.
+// Receiving an external signal (SIGINT or SIGTERM, unless configured otherwise via
+// WithSignals or WithoutSignals) will also start a graceful shutdown.
 // A second signal will cause the Run method to stop blocking (although a process may
-// still be running in a goroutine).
+// still be running in a goroutine). On Windows, console control events (close, logoff,
+// and shutdown) start a graceful shutdown and only a second interrupt (Ctrl+C) causes
+// the Run method to stop blocking. The context passed to each ContextProcess is
+// canceled once a graceful shutdown begins.
+//
+// Once all processes have been initialized, the service container is frozen and
+// any subsequent attempt to register a service will fail.
 //
 // If any process has started, the error channel returned from Run will remain open
 // until all running processes have exited.
 func (pr *ProcessRunner) Run(config Config, logger Logger) <-chan error {
-	errChan := make(chan error, pr.numProcesses*2+1)
+	return pr.RunContext(context.Background(), config, logger)
+}
+
+// RunContext behaves like Run, but is bound to the given context. If the context
+// is done (canceled or past its deadline) before all initializers and processes
+// have been initialized, the Init method which is running fails and startup is
+// aborted. This allows a caller to bound the total boot time of the application.
+// If the context is done after the application has booted, a graceful shutdown
+// is started as if by a call to Shutdown.
+func (pr *ProcessRunner) RunContext(ctx context.Context, config Config, logger Logger) <-chan error {
+	pr.bootCtx = ctx
+	pr.config = config
+	pr.logger = logger
+	pr.wg = &sync.WaitGroup{}
+	pr.startErrors = make(chan errMeta)
+	pr.logDuplicates()
+
+	if ctx.Done() != nil {
+		go pr.haltOnDone(ctx)
+	}
+
+	if pr.watchdogTimeout > 0 {
+		go pr.runWatchdog()
+	}
+
+	errChan := make(chan error, pr.numProcesses*4+len(pr.initializers)*2+4)
+	out := pr.relayErrors(errChan)
 
-	if err := pr.runInitializers(config, logger); err != nil {
+	if err := pr.runInitializers(); err != nil {
 		defer close(errChan)
+		defer close(pr.done)
+		pr.record("Initialization failed (%s)", err.Error())
+		pr.dumpOnCrash()
 		errChan <- err
-		return errChan
+		pr.rollback(errChan)
+		pr.finalize(nil, errChan)
+		pr.syncLogs(errChan)
+		return out
 	}
 
-	var (
-		startErrors = make(chan errMeta)
-		priorities  = pr.getPriorities()
-		wg          = &sync.WaitGroup{}
-	)
+	priorities := pr.getPriorities()
 
-	if !pr.runProcesses(priorities, config, logger, startErrors, errChan, wg) {
-		return errChan
+	if !pr.runProcesses(priorities, errChan) {
+		return out
 	}
 
+	pr.container.Freeze()
+	pr.setRunning()
 	logger.Info("All processes running")
 
-	go pr.watch(priorities, logger, startErrors, errChan)
-	go closeAfterWait(wg, startErrors)
+	pr.startLivenessChecks(priorities)
+	pr.checkUnusedServices(errChan)
+
+	go pr.watch(priorities, errChan)
+	go closeAfterWait(pr.wg, pr.startErrors)
 
-	return chainUntilHalt(errChan, pr.done)
+	return out
 }
 
 func (pr *ProcessRunner) getPriorities() []int {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
 	priorities := []int{}
 	for priority := range pr.processes {
 		priorities = append(priorities, priority)
@@ -134,13 +307,17 @@ func (pr *ProcessRunner) getPriorities() []int {
 	return priorities
 }
 
-func (pr *ProcessRunner) runInitializers(config Config, logger Logger) error {
-	logger.Info("Running initializers")
+func (pr *ProcessRunner) runInitializers() error {
+	pr.logger.Info("Running initializers")
 
 	for _, initializer := range pr.initializers {
-		logger.Debug("Injecting services into %s", initializer.Name())
+		pr.logger.Debug("Injecting services into %s", initializer.Name())
+
+		reporter := NewProgressReporter(initializer.Name())
 
-		if err := pr.container.Inject(initializer.Initializer); err != nil {
+		if err := pr.container.injectWithOverrides(initializer.Initializer, map[interface{}]interface{}{
+			"progress": reporter,
+		}); err != nil {
 			return fmt.Errorf(
 				"failed to inject services into %s (%s)",
 				initializer.Name(),
@@ -148,75 +325,72 @@ func (pr *ProcessRunner) runInitializers(config Config, logger Logger) error {
 			)
 		}
 
-		logger.Debug("Initializing %s", initializer.Name())
+		pr.logger.Debug("Initializing %s", initializer.Name())
 
-		if err := initWithTimeout(initializer, config, initializer.timeout); err != nil {
+		if err := pr.initWithProgress(initializer, pr.config, initializer.timeout, reporter); err != nil {
 			return fmt.Errorf(
-				"failed to initialize %s (%s)",
+				"failed to initialize %s (%w)",
 				initializer.Name(),
-				err.Error(),
+				err,
 			)
 		}
 
-		logger.Debug("Initialized %s", initializer.Name())
+		pr.logger.Debug("Initialized %s", initializer.Name())
+		pr.record("Initialized %s", initializer.Name())
+		initializer.initialized = true
 	}
 
 	return nil
 }
 
-func (pr *ProcessRunner) runProcesses(
-	priorities []int,
-	config Config,
-	logger Logger,
-	startErrors chan errMeta,
-	errChan chan error,
-	wg *sync.WaitGroup,
-) bool {
-	logger.Debug("Injecting services into process instances")
+func (pr *ProcessRunner) runProcesses(priorities []int, errChan chan error) bool {
+	pr.logger.Debug("Injecting services into process instances")
 
+	report := &StartupReport{}
 	for i := range priorities {
 		for _, process := range pr.processes[priorities[i]] {
-			if err := pr.container.Inject(process.Process); err != nil {
-				defer close(errChan)
-
-				errChan <- fmt.Errorf(
-					"failed to inject services into %s (%s)",
-					process.Name(),
+			if err := pr.injectProcess(process); err != nil {
+				report.add(StageInject, process.Name(), fmt.Errorf(
+					"failed to inject services (%s)",
 					err.Error(),
-				)
-
-				return false
+				))
 			}
 		}
 	}
 
-	logger.Info("Initializing and starting processes")
+	if err := report.Err(); err != nil {
+		defer close(errChan)
+		defer close(pr.done)
+		errChan <- err
+		pr.rollback(errChan)
+		pr.finalize(nil, errChan)
+		pr.syncLogs(errChan)
+		return false
+	}
+
+	pr.logger.Info("Initializing and starting processes")
 
 	for i := range priorities {
-		err := pr.initAndStartProcesses(
-			pr.processes[priorities[i]],
-			priorities[i],
-			config,
-			logger,
-			wg,
-			startErrors,
-		)
-
-		if err != nil {
+		if err := pr.initAndStartProcesses(pr.processes[priorities[i]], priorities[i]); err != nil {
+			pr.record("Initialization failed (%s)", err.Error())
+			pr.dumpOnCrash()
 			errChan <- err
-			pr.stopProcesessBelowPriority(priorities, i, logger, errChan)
-			go closeAfterWait(wg, startErrors)
+			pr.abortProcesses(priorities, i, errChan)
+			return false
+		}
 
-			go func() {
-				defer close(errChan)
+		if !pr.waitUntilReady(pr.processes[priorities[i]], priorities[i], errChan) {
+			pr.abortProcesses(priorities, i+1, errChan)
+			return false
+		}
 
-				for err := range startErrors {
-					if err.err != nil {
-						errChan <- err.err
-					}
-				}
-			}()
+		if !pr.runGroupStartedHooks(priorities[i], errChan) {
+			pr.abortProcesses(priorities, i+1, errChan)
+			return false
+		}
 
+		if !pr.pause(priorities[i]) {
+			pr.abortProcesses(priorities, i+1, errChan)
 			return false
 		}
 	}
@@ -224,60 +398,157 @@ func (pr *ProcessRunner) runProcesses(
 	return true
 }
 
-func (pr *ProcessRunner) initAndStartProcesses(
-	processes []*processMeta,
-	priority int,
-	config Config,
-	logger Logger,
-	wg *sync.WaitGroup,
-	startErrors chan<- errMeta,
-) error {
-	logger.Debug("Initializing processes at priority %d", priority)
+// abortProcesses stops the processes below the given priority index which have
+// already been started, and closes the error channel once they have all exited.
+func (pr *ProcessRunner) abortProcesses(priorities []int, p int, errChan chan error) {
+	pr.stopProcesessBelowPriority(priorities, p, errChan)
+	go closeAfterWait(pr.wg, pr.startErrors)
 
-	for _, process := range processes {
-		logger.Debug("Initializing %s", process.Name())
+	untrack := pr.trackPending(pr.unexitedProcesses, "waiting for processes to exit")
+
+	go func() {
+		defer close(errChan)
+		defer close(pr.done)
 
-		if err := initWithTimeout(process, config, process.initTimeout); err != nil {
-			return fmt.Errorf("failed to initialize %s (%s)", process.Name(), err.Error())
+		for err := range pr.startErrors {
+			if err.err != nil {
+				errChan <- err.err
+			}
 		}
 
-		logger.Debug("Initialized %s", process.Name())
+		untrack()
+		pr.finalize(priorities, errChan)
+		pr.syncLogs(errChan)
+	}()
+}
+
+func (pr *ProcessRunner) injectProcess(process *processMeta) error {
+	process.progress = NewProgressReporter(process.Name())
+
+	overrides := map[interface{}]interface{}{
+		"progress": process.progress,
+	}
+
+	if process.replica != nil {
+		overrides["replica"] = process.replica
+	}
+
+	return pr.container.injectWithOverrides(injectionTarget(process.Process), overrides)
+}
+
+func (pr *ProcessRunner) initAndStartProcesses(processes []*processMeta, priority int) error {
+	pr.logger.Debug("Initializing processes at priority %d", priority)
+
+	for _, process := range processes {
+		if err := pr.initProcess(process); err != nil {
+			return err
+		}
 	}
 
-	logger.Debug("Starting processes at priority %d", priority)
+	pr.logger.Debug("Starting processes at priority %d", priority)
 
 	for _, process := range processes {
-		wg.Add(1)
+		pr.wg.Add(1)
+		pr.startProcess(process)
+	}
+
+	return nil
+}
 
-		go func(process *processMeta) {
-			defer wg.Done()
+func (pr *ProcessRunner) initProcess(process *processMeta) error {
+	pr.logger.Debug("Initializing %s", process.Name())
 
-			logger.Debug("Starting %s", process.Name())
+	config := pr.config
+	if process.configPrefix != "" {
+		config = NewPrefixedConfig(config, process.configPrefix)
+	}
 
-			err := process.Start()
-			if err != nil {
-				err = fmt.Errorf("%s returned a fatal error (%s)", process.Name(), err.Error())
-			}
+	if err := pr.injectProcessHandles(process); err != nil {
+		return fmt.Errorf("failed to inject process handles into %s (%s)", process.Name(), err.Error())
+	}
 
-			startErrors <- errMeta{err, process}
-		}(process)
+	if err := pr.initWithProgress(process, config, process.initTimeout, process.progress); err != nil {
+		return fmt.Errorf("failed to initialize %s (%w)", process.Name(), err)
 	}
 
+	pr.logger.Debug("Initialized %s", process.Name())
+	pr.record("Initialized %s", process.Name())
+	process.initialized = true
 	return nil
 }
 
-func (pr *ProcessRunner) watch(
-	priorities []int,
-	logger Logger,
-	startErrors <-chan errMeta,
-	errChan chan<- error,
-) {
+// startProcess calls the process's Start method in a goroutine. The caller
+// must have already added to the runner's wait group on behalf of the process.
+func (pr *ProcessRunner) startProcess(process *processMeta) {
+	exited := make(chan struct{})
+	process.setExited(exited)
+
+	go func() {
+		defer pr.wg.Done()
+
+		for {
+			pr.logger.Debug("Starting %s", process.Name())
+			pr.record("Starting %s", process.Name())
+			pr.emit(EventStartCalled, process.Name(), process.tags, nil)
+
+			started := time.Now()
+
+			err := pr.callStart(process)
+			if err != nil {
+				format := "%s returned a fatal error (%w)"
+				if process.job != nil {
+					format = "%s returned an error (%w)"
+				}
+
+				err = fmt.Errorf(format, process.Name(), err)
+				pr.record("%s exited with an error (%s)", process.Name(), err.Error())
+				pr.emit(EventErrored, process.Name(), process.tags, err)
+			} else {
+				pr.record("%s exited", process.Name())
+			}
+
+			pr.emit(EventStopped, process.Name(), process.tags, err)
+
+			if process.isExitExpected() {
+				if err != nil {
+					pr.logger.Warning("%s returned an error while being stopped (%s)", process.Name(), err.Error())
+				}
+
+				close(exited)
+				return
+			}
+
+			restarted, err := pr.restartAfterExit(process, err, time.Since(started))
+			if restarted {
+				continue
+			}
+
+			close(exited)
+
+			if !process.abandoned {
+				pr.startErrors <- errMeta{err, process}
+			}
+
+			return
+		}
+	}()
+}
+
+// callStart calls the process's Start method. A panic is returned as an error.
+func (pr *ProcessRunner) callStart(process *processMeta) error {
+	return callSafely(process.Start)
+}
+
+func (pr *ProcessRunner) watch(priorities []int, errChan chan<- error) {
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt)
-	signal.Notify(sigChan, syscall.SIGTERM)
+	if len(pr.signals) > 0 {
+		signal.Notify(sigChan, pr.signals...)
+		defer signal.Stop(sigChan)
+	}
 
 	defer close(errChan)
 	defer close(pr.done)
+	defer pr.syncLogs(errChan)
 
 	var (
 		urgent  = false
@@ -286,49 +557,82 @@ func (pr *ProcessRunner) watch(
 
 	for {
 		select {
-		case <-sigChan:
+		case sig := <-sigChan:
 			if urgent {
-				logger.Info("Received second signal, no longer waiting for graceful exit")
-				return
+				if forcesExit(sig) {
+					pr.logger.Info("Received second signal, no longer waiting for graceful exit")
+					return
+				}
+
+				pr.logger.Info("Received signal (%s), continuing graceful shutdown", sig)
+				continue
 			}
 
-			logger.Info("Received signal, starting graceful shutdown")
+			pr.logger.Info("Received signal (%s), starting graceful shutdown", sig)
+			pr.record("Received signal (%s)", sig)
 			urgent = true
 
-		case err, ok := <-startErrors:
+		case err, ok := <-pr.startErrors:
 			if !ok {
+				pr.finalize(priorities, errChan)
 				return
 			}
 
+			if err.process.job != nil {
+				pr.completeJob(err.process, err.err, errChan)
+				continue
+			}
+
 			if err.err == nil {
 				if err.process.silentExit {
 					continue
 				}
 
-				logger.Info(
+				pr.logger.Info(
 					"%s has stopped cleanly, starting graceful shutdown",
 					err.process.Name(),
 				)
 			} else {
-				logger.Error(
+				pr.logger.ErrorWithFields(
+					err.process.fields(errorFields(err.err)),
 					"%s returned a fatal error, starting graceful shutdown",
 					err.process.Name(),
 				)
 
+				pr.dumpOnCrash()
+
 				errChan <- err.err
 			}
 
 		case <-pr.halt:
-			logger.Info("Received external shutdown request")
+			pr.logger.Info("Received external shutdown request")
+			pr.record("Received external shutdown request")
 		}
 
 		if !stopped {
 			stopped = true
-			pr.stopProcesessBelowPriority(priorities, len(priorities), logger, errChan)
+			pr.stopProcesessBelowPriority(priorities, len(priorities), errChan)
+			pr.trackPending(pr.unexitedProcesses, "waiting for processes to exit")
 		}
 	}
 }
 
+// haltOnDone requests a shutdown once the given context is done, unless the
+// runner has already stopped.
+func (pr *ProcessRunner) haltOnDone(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		pr.once.Do(func() {
+			close(pr.halt)
+		})
+
+	case <-pr.done:
+	}
+}
+
+// Shutdown starts a graceful shutdown of the runner, as if a signal was received,
+// and blocks until all processes have exited or the given timeout elapses. This
+// allows an application to trigger shutdown from code (e.g. from an admin RPC).
 func (pr *ProcessRunner) Shutdown(timeout time.Duration) error {
 	pr.once.Do(func() {
 		close(pr.halt)
@@ -342,33 +646,96 @@ func (pr *ProcessRunner) Shutdown(timeout time.Duration) error {
 	}
 }
 
-func (pr *ProcessRunner) stopProcesessBelowPriority(priorities []int, p int, logger Logger, errChan chan<- error) {
+func (pr *ProcessRunner) stopProcesessBelowPriority(priorities []int, p int, errChan chan<- error) {
+	pr.mutex.Lock()
+	notify := !pr.stopping
+	pr.stopping = true
+	pr.mutex.Unlock()
+
+	pr.cancel()
+
+	if notify {
+		pr.notifyShutdown()
+		pr.watchOperations()
+	}
+
+	pr.drainProcesses(priorities, p, errChan)
+
+	processes := []*processMeta{}
 	for i := p - 1; i >= 0; i-- {
-		pr.stopProcesses(pr.processes[priorities[i]], priorities[i], logger, errChan)
+		processes = append(processes, pr.getProcesses(priorities[i])...)
+	}
+
+	// Stable so that processes with equal stop priorities remain in
+	// reverse start priority order
+	sort.SliceStable(processes, func(i, j int) bool {
+		return processes[i].getStopPriority() > processes[j].getStopPriority()
+	})
+
+	for len(processes) > 0 {
+		n := 1
+		for n < len(processes) && processes[n].getStopPriority() == processes[0].getStopPriority() {
+			n++
+		}
+
+		pr.stopProcesses(processes[:n], processes[0].getStopPriority(), errChan)
+		processes = processes[n:]
 	}
 }
 
-func (pr *ProcessRunner) stopProcesses(processes []*processMeta, priority int, logger Logger, errChan chan<- error) {
-	logger.Debug("Stopping processes at priority %d", priority)
+func (pr *ProcessRunner) setRunning() {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	pr.running = true
+}
+
+func (pr *ProcessRunner) isRunning() bool {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	return pr.running
+}
+
+func (pr *ProcessRunner) getProcesses(priority int) []*processMeta {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	return append([]*processMeta{}, pr.processes[priority]...)
+}
+
+func (pr *ProcessRunner) stopProcesses(processes []*processMeta, priority int, errChan chan<- error) {
+	pr.logger.Debug("Stopping processes at stop priority %d", priority)
 
 	for _, process := range processes {
-		logger.Debug("Stopping %s", process.Name())
+		if process.job != nil && hasExited(process) {
+			continue
+		}
+
+		pr.logger.Debug("Stopping %s", process.Name())
+		pr.record("Stopping %s", process.Name())
+		pr.emit(EventStopping, process.Name(), process.tags, nil)
+		untrack := pr.track("stopping %s", process.Name())
 
-		if err := process.Stop(); err != nil {
-			errChan <- fmt.Errorf("%s returned error from stop (%s)", process.Name(), err.Error())
+		if err := callSafely(process.Stop); err != nil {
+			errChan <- fmt.Errorf("%s returned error from stop (%w)", process.Name(), err)
 		}
+
+		untrack()
 	}
 }
 
 //
 // Helpers
 
-func initWithTimeout(initializer Initializer, config Config, timeout time.Duration) error {
-	ch := make(chan error)
+func initWithTimeout(ctx context.Context, initializer Initializer, config Config, timeout time.Duration) error {
+	// Buffered so that an Init method which eventually returns after the
+	// timeout does not leak its goroutine
+	ch := make(chan error, 1)
 
 	go func() {
 		defer close(ch)
-		ch <- initializer.Init(config)
+		ch <- callSafely(func() error { return initializer.Init(config) })
 	}()
 
 	select {
@@ -376,6 +743,8 @@ func initWithTimeout(initializer Initializer, config Config, timeout time.Durati
 		return err
 	case <-makeTimeoutChan(timeout):
 		return ErrInitTimeout
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -393,31 +762,3 @@ func closeAfterWait(wg *sync.WaitGroup, startErrors chan errMeta) {
 	wg.Wait()
 	close(startErrors)
 }
-
-func chainUntilHalt(src <-chan error, halt <-chan struct{}) <-chan error {
-	out := make(chan error)
-
-	go func() {
-	loop:
-		for {
-			select {
-			case err, ok := <-src:
-				if !ok {
-					break loop
-				}
-
-				out <- err
-
-			case <-halt:
-				break loop
-			}
-		}
-
-		close(out)
-
-		for range src {
-		}
-	}()
-
-	return out
-}
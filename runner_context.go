@@ -0,0 +1,63 @@
+package nacelle
+
+import (
+	"context"
+	"sync"
+)
+
+// contextProcess adapts a ContextProcess to the Process interface.
+type contextProcess struct {
+	ContextProcess
+	ctx    context.Context
+	cancel func()
+	mutex  sync.Mutex
+}
+
+// RegisterContextProcess registers a context-aware process with the given
+// configuration. The process is otherwise treated as if it was registered
+// by RegisterProcess.
+func (pr *ProcessRunner) RegisterContextProcess(process ContextProcess, processConfigs ...ProcessConfigFunc) {
+	pr.RegisterProcess(pr.wrapContextProcess(process), processConfigs...)
+}
+
+func (pr *ProcessRunner) wrapContextProcess(process ContextProcess) Process {
+	return &contextProcess{
+		ContextProcess: process,
+		ctx:            pr.ctx,
+		cancel:         func() {},
+	}
+}
+
+func (p *contextProcess) Init(config Config) error {
+	return p.ContextProcess.Init(p.ctx, config)
+}
+
+func (p *contextProcess) Start() error {
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+
+	p.mutex.Lock()
+	p.cancel = cancel
+	p.mutex.Unlock()
+
+	return p.ContextProcess.Start(ctx)
+}
+
+func (p *contextProcess) Stop() error {
+	p.mutex.Lock()
+	cancel := p.cancel
+	p.mutex.Unlock()
+
+	cancel()
+	return p.ContextProcess.Stop(context.Background())
+}
+
+// injectionTarget returns the value into which services should be injected
+// for the given process. This is the wrapped process for adapted processes.
+func injectionTarget(process Process) interface{} {
+	if p, ok := process.(*contextProcess); ok {
+		return p.ContextProcess
+	}
+
+	return process
+}
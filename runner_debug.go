@@ -0,0 +1,108 @@
+package nacelle
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+type (
+	// BreakpointFunc is called by the process runner in debug mode after the
+	// processes of each priority group have been started (see WithBreakpoint).
+	// The next priority group is not initialized until the function returns.
+	BreakpointFunc func(priority int)
+
+	// ManualBreakpoint is a breakpoint which waits for an explicit call to
+	// Resume, such as from an admin API handler.
+	ManualBreakpoint struct {
+		resume chan struct{}
+		mutex  sync.Mutex
+		paused bool
+	}
+)
+
+// WithBreakpoint enables a debug mode in which the runner pauses after starting
+// the processes of each priority group by calling the given breakpoint function.
+// This allows a developer to inspect the state of the application between phases
+// of startup. A shutdown request received while paused stops the processes which
+// have already been started. This option should not be used in production.
+func WithBreakpoint(breakpoint BreakpointFunc) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.breakpoint = breakpoint }
+}
+
+// NewKeypressBreakpoint creates a breakpoint function which waits for a line
+// to be read from the given reader (e.g. os.Stdin).
+func NewKeypressBreakpoint(r io.Reader) BreakpointFunc {
+	reader := bufio.NewReader(r)
+
+	return func(priority int) {
+		reader.ReadString('\n')
+	}
+}
+
+// NewManualBreakpoint creates a new ManualBreakpoint.
+func NewManualBreakpoint() *ManualBreakpoint {
+	return &ManualBreakpoint{
+		resume: make(chan struct{}, 1),
+	}
+}
+
+// Wait blocks until Resume is called. This method can be passed directly
+// to WithBreakpoint.
+func (b *ManualBreakpoint) Wait(priority int) {
+	b.setPaused(true)
+	defer b.setPaused(false)
+
+	<-b.resume
+}
+
+// Resume unblocks a call to Wait. If the runner is not currently paused, the
+// next call to Wait returns immediately.
+func (b *ManualBreakpoint) Resume() {
+	select {
+	case b.resume <- struct{}{}:
+	default:
+	}
+}
+
+// Paused returns true if a call to Wait is currently blocked.
+func (b *ManualBreakpoint) Paused() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.paused
+}
+
+func (b *ManualBreakpoint) setPaused(paused bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.paused = paused
+}
+
+// pause calls the runner's breakpoint function, if one is set, and blocks until
+// it returns. Returns false if a shutdown was requested while paused.
+func (pr *ProcessRunner) pause(priority int) bool {
+	if pr.breakpoint == nil {
+		return true
+	}
+
+	pr.logger.Info("Paused after starting processes at priority %d", priority)
+
+	resumed := make(chan struct{})
+
+	go func() {
+		defer close(resumed)
+		pr.breakpoint(priority)
+	}()
+
+	select {
+	case <-resumed:
+		pr.logger.Info("Resuming after breakpoint at priority %d", priority)
+		return true
+
+	case <-pr.halt:
+		pr.logger.Info("Received external shutdown request while paused")
+		return false
+	}
+}
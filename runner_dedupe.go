@@ -0,0 +1,92 @@
+package nacelle
+
+import "reflect"
+
+type (
+	// registration identifies an initializer or process which has been
+	// registered with the runner.
+	registration struct {
+		kind string
+		key  interface{}
+	}
+
+	// declaredKey is a registration key set via WithInitializerKey or
+	// WithProcessKey. It is distinct from any pointer used as an identity.
+	declaredKey string
+
+	// duplicate is a registration which was skipped because an equivalent
+	// initializer or process had already been registered.
+	duplicate struct {
+		kind     string
+		name     string
+		original string
+	}
+)
+
+// WithInitializerKey declares a key which identifies an initializer. If another
+// initializer with the same key has already been registered, the registration
+// is skipped so that its Init method is called only once. Without a declared
+// key, an initializer is identified by its pointer value.
+func WithInitializerKey(key string) InitializerConfigFunc {
+	return func(meta *initializerMeta) { meta.key = key }
+}
+
+// WithProcessKey declares a key which identifies a process. If another process
+// with the same key has already been registered, the registration is skipped so
+// that its Init and Start methods are called only once. Without a declared key,
+// a process is identified by its pointer value.
+func WithProcessKey(key string) ProcessConfigFunc {
+	return func(meta *processMeta) { meta.key = key }
+}
+
+// registerOnce records the registration of an initializer or process with the
+// given declared key and value. False is returned if an equivalent registration
+// has already been made, in which case the registration should be skipped. A
+// value which is not a pointer and has no declared key is never deduplicated.
+func (pr *ProcessRunner) registerOnce(kind, key string, value interface{}, name string) bool {
+	identity := registrationKey(key, value)
+	if identity == nil {
+		return true
+	}
+
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	r := registration{kind: kind, key: identity}
+
+	if original, ok := pr.registered[r]; ok {
+		pr.duplicates = append(pr.duplicates, duplicate{
+			kind:     kind,
+			name:     name,
+			original: original,
+		})
+
+		return false
+	}
+
+	pr.registered[r] = name
+	return true
+}
+
+// logDuplicates logs each registration which was skipped by registerOnce.
+func (pr *ProcessRunner) logDuplicates() {
+	for _, d := range pr.duplicates {
+		pr.logger.Warning("Skipped duplicate registration of %s %s (already registered as %s)", d.kind, d.name, d.original)
+	}
+}
+
+func registrationKey(key string, value interface{}) interface{} {
+	if key != "" {
+		return declaredKey(key)
+	}
+
+	if p, ok := value.(*contextProcess); ok {
+		value = p.ContextProcess
+	}
+
+	if value == nil || reflect.TypeOf(value).Kind() != reflect.Ptr {
+		return nil
+	}
+
+	return value
+}
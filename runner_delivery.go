@@ -0,0 +1,120 @@
+package nacelle
+
+import (
+	"sync/atomic"
+)
+
+const defaultErrorBufferSize = 256
+
+type (
+	// DeliveryStats counts the errors and lifecycle events which a runner has
+	// delivered to its consumers and those it has dropped because a consumer
+	// fell behind. A non-zero drop count indicates that the reader of the error
+	// channel (or a buffered subscriber) is stalled or too slow.
+	DeliveryStats struct {
+		ErrorsDelivered uint64
+		ErrorsDropped   uint64
+		EventsDelivered uint64
+		EventsDropped   uint64
+	}
+
+	deliveryCounters struct {
+		errorsDelivered uint64
+		errorsDropped   uint64
+		eventsDelivered uint64
+		eventsDropped   uint64
+	}
+)
+
+// WithErrorBufferSize sets the number of errors which the channel returned by Run
+// buffers for its reader. The runner never blocks on this channel: an error which
+// arrives while the buffer is full is logged and dropped (see DeliveryStats), so
+// a reader which stalls cannot deadlock shutdown. The default is 256.
+func WithErrorBufferSize(size int) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) {
+		if size > 0 {
+			pr.errorBufferSize = size
+		}
+	}
+}
+
+// DeliveryStats returns the current delivery counters of the runner.
+func (pr *ProcessRunner) DeliveryStats() DeliveryStats {
+	return DeliveryStats{
+		ErrorsDelivered: atomic.LoadUint64(&pr.delivery.errorsDelivered),
+		ErrorsDropped:   atomic.LoadUint64(&pr.delivery.errorsDropped),
+		EventsDelivered: atomic.LoadUint64(&pr.delivery.eventsDelivered),
+		EventsDropped:   atomic.LoadUint64(&pr.delivery.eventsDropped),
+	}
+}
+
+// Fields returns the counters as log fields, suitable for reporting to a
+// metrics backend.
+func (s DeliveryStats) Fields() Fields {
+	return Fields{
+		"errors_delivered": s.ErrorsDelivered,
+		"errors_dropped":   s.ErrorsDropped,
+		"events_delivered": s.EventsDelivered,
+		"events_dropped":   s.EventsDropped,
+	}
+}
+
+// SubscribeBuffered registers a subscriber as Subscribe does, but calls it from
+// a separate goroutine so that a slow subscriber does not block the runner. At
+// most size events are queued for the subscriber; an event which arrives while
+// the queue is full is dropped (see DeliveryStats). The returned function removes
+// the subscription.
+func (pr *ProcessRunner) SubscribeBuffered(subscriber LifecycleSubscriber, size int) func() {
+	var (
+		events = make(chan LifecycleEvent, size)
+		halt   = make(chan struct{})
+	)
+
+	go func() {
+		for {
+			select {
+			case event := <-events:
+				subscriber(event)
+			case <-halt:
+				return
+			}
+		}
+	}()
+
+	unsubscribe := pr.Subscribe(func(event LifecycleEvent) {
+		select {
+		case events <- event:
+			atomic.AddUint64(&pr.delivery.eventsDelivered, 1)
+		default:
+			atomic.AddUint64(&pr.delivery.eventsDropped, 1)
+		}
+	})
+
+	return func() {
+		unsubscribe()
+		close(halt)
+	}
+}
+
+// relayErrors forwards the errors sent to src to a channel of the runner's error
+// buffer size without blocking, dropping the errors which do not fit. The returned
+// channel is closed once src is closed.
+func (pr *ProcessRunner) relayErrors(src <-chan error) <-chan error {
+	out := make(chan error, pr.errorBufferSize)
+
+	go func() {
+		defer close(out)
+
+		for err := range src {
+			select {
+			case out <- err:
+				atomic.AddUint64(&pr.delivery.errorsDelivered, 1)
+			default:
+				atomic.AddUint64(&pr.delivery.errorsDropped, 1)
+				pr.logger.ErrorWithFields(errorFields(err), "Dropped error as the error channel is full (%s)", err.Error())
+			}
+		}
+	}()
+
+	return out
+}
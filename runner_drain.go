@@ -0,0 +1,226 @@
+package nacelle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// DrainProgress is a snapshot of a process which has not yet finished draining.
+	DrainProgress struct {
+		Name    string
+		Elapsed time.Duration
+
+		// InFlight is the amount of work remaining, and Initial is the amount of
+		// work remaining when draining began. Both are -1 if the process does not
+		// implement InFlightReporter.
+		InFlight int
+		Initial  int
+
+		// Estimate is the time remaining until the process finishes draining
+		// extrapolated from its rate of progress so far, or zero if no progress
+		// has been made.
+		Estimate time.Duration
+	}
+
+	// DrainReporter receives the progress of the processes which have not yet
+	// finished draining at each progress interval. A reporter may, for example,
+	// publish the progress to a metrics backend.
+	DrainReporter func(progress []DrainProgress)
+
+	drainTracker struct {
+		drainer Drainer
+		counter InFlightReporter
+		initial int
+		started time.Time
+	}
+)
+
+// WithDrainTimeout sets the maximum time the runner waits for the Drain methods
+// of its processes to return during shutdown. Processes which are still draining
+// once the timeout elapses are stopped regardless. The default is no time limit.
+func WithDrainTimeout(timeout time.Duration) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.drainTimeout = timeout }
+}
+
+// WithDrainReporter sets a function which receives the progress of the processes
+// which have not yet finished draining at each progress interval.
+func WithDrainReporter(reporter DrainReporter) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.drainReporter = reporter }
+}
+
+// drainProcesses concurrently calls the Drain method of each running process below
+// the given priority index which implements Drainer, and blocks until they have all
+// returned or the drain timeout elapses.
+func (pr *ProcessRunner) drainProcesses(priorities []int, p int, errChan chan<- error) {
+	type drainResult struct {
+		process *processMeta
+		err     error
+	}
+
+	drainers := map[*processMeta]*drainTracker{}
+	for i := p - 1; i >= 0; i-- {
+		for _, process := range pr.getProcesses(priorities[i]) {
+			if drainer, ok := injectionTarget(process.Process).(Drainer); ok && !hasExited(process) {
+				drainers[process] = newDrainTracker(drainer)
+			}
+		}
+	}
+
+	if len(drainers) == 0 {
+		return
+	}
+
+	pr.logger.Info("Draining processes")
+	pr.record("Draining processes")
+	defer pr.track("draining processes")()
+
+	var (
+		results = make(chan drainResult, len(drainers))
+		pending = map[*processMeta]struct{}{}
+	)
+
+	for process, tracker := range drainers {
+		pending[process] = struct{}{}
+
+		go func(process *processMeta, drainer Drainer) {
+			results <- drainResult{process, drainer.Drain()}
+		}(process, tracker.drainer)
+	}
+
+	var timeout <-chan time.Time
+	if pr.drainTimeout > 0 {
+		timer := time.NewTimer(pr.drainTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var progress <-chan time.Time
+	if pr.progressInterval > 0 {
+		ticker := time.NewTicker(pr.progressInterval)
+		defer ticker.Stop()
+		progress = ticker.C
+	}
+
+	for len(pending) > 0 {
+		select {
+		case result := <-results:
+			delete(pending, result.process)
+
+			if result.err != nil {
+				errChan <- fmt.Errorf("%s returned error from drain (%s)", result.process.Name(), result.err.Error())
+			}
+
+		case <-progress:
+			pr.logDrainProgress(drainers, pending)
+
+		case <-timeout:
+			names := []string{}
+			for process := range pending {
+				names = append(names, process.Name())
+			}
+
+			sort.Strings(names)
+			pr.logger.Warning("Processes did not finish draining within %s (%s)", pr.drainTimeout, strings.Join(names, ", "))
+			return
+		}
+	}
+}
+
+// logDrainProgress logs the progress of each pending process, ordered by name,
+// and hands the progress to the drain reporter, if one is set.
+func (pr *ProcessRunner) logDrainProgress(drainers map[*processMeta]*drainTracker, pending map[*processMeta]struct{}) {
+	progress := []DrainProgress{}
+	for process := range pending {
+		progress = append(progress, drainers[process].progress(process.Name()))
+	}
+
+	sort.Slice(progress, func(i, j int) bool {
+		return progress[i].Name < progress[j].Name
+	})
+
+	for _, p := range progress {
+		pr.logger.InfoWithFields(p.Fields(), "Still draining %s", p.String())
+	}
+
+	if pr.drainReporter != nil {
+		pr.drainReporter(progress)
+	}
+}
+
+func newDrainTracker(drainer Drainer) *drainTracker {
+	tracker := &drainTracker{
+		drainer: drainer,
+		initial: -1,
+		started: time.Now(),
+	}
+
+	if counter, ok := drainer.(InFlightReporter); ok {
+		tracker.counter = counter
+		tracker.initial = counter.InFlight()
+	}
+
+	return tracker
+}
+
+func (t *drainTracker) progress(name string) DrainProgress {
+	progress := DrainProgress{
+		Name:     name,
+		Elapsed:  time.Since(t.started),
+		InFlight: -1,
+		Initial:  t.initial,
+	}
+
+	if t.counter == nil {
+		return progress
+	}
+
+	progress.InFlight = t.counter.InFlight()
+
+	if completed := progress.Initial - progress.InFlight; completed > 0 {
+		progress.Estimate = time.Duration(float64(progress.Elapsed) / float64(completed) * float64(progress.InFlight))
+	}
+
+	return progress
+}
+
+func (p DrainProgress) String() string {
+	details := fmt.Sprintf("%s elapsed", p.Elapsed/time.Second*time.Second)
+
+	if p.InFlight >= 0 {
+		details = fmt.Sprintf("%s, %d in flight", details, p.InFlight)
+	}
+
+	if p.Estimate > 0 {
+		details = fmt.Sprintf("%s, about %s remaining", details, p.Estimate/time.Second*time.Second)
+	}
+
+	return fmt.Sprintf("%s (%s)", p.Name, details)
+}
+
+// Fields returns the snapshot as log fields.
+func (p DrainProgress) Fields() Fields {
+	return Fields{
+		"name":      p.Name,
+		"elapsed":   p.Elapsed.Seconds(),
+		"in_flight": p.InFlight,
+		"initial":   p.Initial,
+		"estimate":  p.Estimate.Seconds(),
+	}
+}
+
+func hasExited(process *processMeta) bool {
+	exited := process.getExited()
+	if exited == nil {
+		return false
+	}
+
+	select {
+	case <-exited:
+		return true
+	default:
+		return false
+	}
+}
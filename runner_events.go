@@ -0,0 +1,150 @@
+package nacelle
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// LifecycleEvent describes a lifecycle transition of an initializer or process.
+	LifecycleEvent struct {
+		Type LifecycleEventType
+		Name string
+		Time time.Time
+
+		// Tags are the tags of the process (see WithProcessTags). This
+		// field is nil for the events of initializers.
+		Tags map[string]string
+
+		// Err is the error which caused the transition, if any. It is set for
+		// EventErrored events and for EventStopped events of processes whose
+		// Start method returned an error.
+		Err error
+	}
+
+	// LifecycleEventType is the kind of a lifecycle event.
+	LifecycleEventType int
+
+	// LifecycleSubscriber receives the lifecycle events of a runner. Subscribers
+	// are called synchronously from the runner's goroutines and must not block.
+	LifecycleSubscriber func(event LifecycleEvent)
+
+	subscribers struct {
+		mutex       sync.Mutex
+		next        int
+		subscribers map[int]LifecycleSubscriber
+	}
+)
+
+const (
+	// EventProcessRegistered is emitted when a process is registered.
+	EventProcessRegistered LifecycleEventType = iota
+
+	// EventInitStarted is emitted before the Init method of an initializer
+	// or process is called.
+	EventInitStarted
+
+	// EventInitCompleted is emitted after the Init method of an initializer
+	// or process returns successfully.
+	EventInitCompleted
+
+	// EventStartCalled is emitted before the Start method of a process is called.
+	EventStartCalled
+
+	// EventStopping is emitted before the Stop method of a process is called.
+	EventStopping
+
+	// EventStopped is emitted after the Start method of a process returns.
+	EventStopped
+
+	// EventErrored is emitted when the Init method of an initializer or process
+	// fails or when the Start method of a process returns an error.
+	EventErrored
+
+	// EventRestarting is emitted before a process is re-initialized by its
+	// restart policy or by a rolling restart.
+	EventRestarting
+
+	// EventReloaded is emitted when a component reports that it has reloaded
+	// its configuration (see RecordReload).
+	EventReloaded
+)
+
+func (t LifecycleEventType) String() string {
+	switch t {
+	case EventProcessRegistered:
+		return "process registered"
+	case EventInitStarted:
+		return "init started"
+	case EventInitCompleted:
+		return "init completed"
+	case EventStartCalled:
+		return "start called"
+	case EventStopping:
+		return "stopping"
+	case EventStopped:
+		return "stopped"
+	case EventErrored:
+		return "errored"
+	case EventRestarting:
+		return "restarting"
+	case EventReloaded:
+		return "reloaded"
+	default:
+		return "unknown"
+	}
+}
+
+// Subscribe registers a function which is called with each subsequent lifecycle
+// event of the runner. This allows metrics, tracing, and custom logging to observe
+// the runner. The returned function removes the subscription.
+func (pr *ProcessRunner) Subscribe(subscriber LifecycleSubscriber) func() {
+	pr.subscribers.mutex.Lock()
+	defer pr.subscribers.mutex.Unlock()
+
+	if pr.subscribers.subscribers == nil {
+		pr.subscribers.subscribers = map[int]LifecycleSubscriber{}
+	}
+
+	id := pr.subscribers.next
+	pr.subscribers.next++
+	pr.subscribers.subscribers[id] = subscriber
+
+	return func() {
+		pr.subscribers.mutex.Lock()
+		defer pr.subscribers.mutex.Unlock()
+
+		delete(pr.subscribers.subscribers, id)
+	}
+}
+
+// RecordReload emits an EventReloaded event with the given name (e.g. "kill
+// switches") to the runner's subscribers and records it to the runner's flight
+// recorder. This allows components which reload their configuration at runtime
+// to appear in lifecycle timelines.
+func (pr *ProcessRunner) RecordReload(name string) {
+	pr.record("Reloaded %s", name)
+	pr.emit(EventReloaded, name, nil, nil)
+}
+
+// emit sends a lifecycle event to each subscriber.
+func (pr *ProcessRunner) emit(eventType LifecycleEventType, name string, tags map[string]string, err error) {
+	pr.subscribers.mutex.Lock()
+	subscribers := make([]LifecycleSubscriber, 0, len(pr.subscribers.subscribers))
+	for _, subscriber := range pr.subscribers.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	pr.subscribers.mutex.Unlock()
+
+	event := LifecycleEvent{
+		Type: eventType,
+		Name: name,
+		Time: time.Now(),
+		Tags: tags,
+		Err:  err,
+	}
+
+	for _, subscriber := range subscribers {
+		subscriber(event)
+	}
+}
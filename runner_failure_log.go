@@ -0,0 +1,78 @@
+package nacelle
+
+import (
+	"time"
+)
+
+// failureLog tracks the repeated failures of a process so that identical
+// failures can be collapsed into periodic summaries.
+type failureLog struct {
+	message string
+	count   int
+	since   time.Time
+}
+
+const defaultFailureLogInterval = time.Minute
+
+// WithFailureLogInterval sets the interval at which repeated identical failures of a
+// restarting process are logged. The first failure is logged immediately; identical
+// failures within the interval are counted and logged as a single summary once the
+// interval has elapsed. The count resets when the process fails with a different
+// error or exits successfully. The default is one minute. An interval of zero logs
+// every failure.
+func WithFailureLogInterval(interval time.Duration) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.failureLogInterval = interval }
+}
+
+// logFailure logs the failure of a process which is about to be restarted after
+// the given delay, unless an identical failure has been logged recently.
+func (pr *ProcessRunner) logFailure(process *processMeta, err error, delay time.Duration) {
+	var (
+		failures = &process.failures
+		message  = err.Error()
+		now      = time.Now()
+	)
+
+	if message == failures.message {
+		elapsed := now.Sub(failures.since)
+		if elapsed < pr.failureLogInterval {
+			failures.count++
+			return
+		}
+
+		pr.logger.WarningWithFields(
+			Fields{"failures": failures.count + 1},
+			"%s failed %d times in the last %s, restarting in %s (%s)",
+			process.Name(),
+			failures.count+1,
+			elapsed/time.Second*time.Second,
+			delay,
+			message,
+		)
+	} else {
+		pr.flushFailures(process)
+		pr.logger.Warning("%s failed, restarting in %s (%s)", process.Name(), delay, message)
+	}
+
+	failures.message = message
+	failures.count = 0
+	failures.since = now
+}
+
+// flushFailures logs a summary of the failures of the process which have not
+// yet been logged and resets the failure count.
+func (pr *ProcessRunner) flushFailures(process *processMeta) {
+	failures := &process.failures
+
+	if failures.count > 0 {
+		pr.logger.WarningWithFields(
+			Fields{"failures": failures.count},
+			"%s failed %d more times (%s)",
+			process.Name(),
+			failures.count,
+			failures.message,
+		)
+	}
+
+	process.failures = failureLog{}
+}
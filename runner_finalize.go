@@ -0,0 +1,51 @@
+package nacelle
+
+import "fmt"
+
+// finalize calls the Finalize method of each initialized process which implements
+// Finalizer in reverse priority order, then of each initialized initializer which
+// implements Finalizer in reverse order of registration, and then closes the services
+// registered to the runner's container and writes the runner's timeline. This must
+// only be called once the Start method of every process has returned.
+func (pr *ProcessRunner) finalize(priorities []int, errChan chan<- error) {
+	for i := len(priorities) - 1; i >= 0; i-- {
+		for _, process := range pr.getProcesses(priorities[i]) {
+			if process.initialized {
+				if err := pr.finalizeOne(injectionTarget(process.Process), process.Name()); err != nil {
+					errChan <- err
+				}
+			}
+		}
+	}
+
+	for i := len(pr.initializers) - 1; i >= 0; i-- {
+		if initializer := pr.initializers[i]; initializer.initialized {
+			if err := pr.finalizeOne(initializer.Initializer, initializer.Name()); err != nil {
+				errChan <- err
+			}
+		}
+	}
+
+	if err := pr.container.Close(); err != nil {
+		errChan <- err
+	}
+
+	pr.writeTimeline()
+}
+
+func (pr *ProcessRunner) finalizeOne(target interface{}, name string) error {
+	finalizer, ok := target.(Finalizer)
+	if !ok {
+		return nil
+	}
+
+	pr.logger.Debug("Finalizing %s", name)
+	pr.record("Finalizing %s", name)
+	defer pr.track("finalizing %s", name)()
+
+	if err := finalizer.Finalize(); err != nil {
+		return fmt.Errorf("%s returned error from finalize (%s)", name, err.Error())
+	}
+
+	return nil
+}
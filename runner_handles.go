@@ -0,0 +1,85 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const processTag = "process"
+
+// injectProcessHandles sets the exported fields of the given process tagged as
+// `process:"name"` with the process registered under that name. This allows a
+// process to interact with another (e.g. a debug server which reports the lag
+// of a consumer) without sharing global state. As the handle is set before the
+// Init method of the process is called, the named process must already have been
+// initialized, so it must have a lower priority or be registered earlier at the
+// same priority.
+func (pr *ProcessRunner) injectProcessHandles(process *processMeta) error {
+	var (
+		ov = reflect.ValueOf(injectionTarget(process.Process))
+		oi = reflect.Indirect(ov)
+	)
+
+	if oi.Kind() != reflect.Struct {
+		return nil
+	}
+
+	ot := oi.Type()
+
+	for i := 0; i < ot.NumField(); i++ {
+		var (
+			fieldType  = ot.Field(i)
+			fieldValue = oi.Field(i)
+			processTag = fieldType.Tag.Get(processTag)
+		)
+
+		if processTag == "" {
+			continue
+		}
+
+		if !fieldValue.CanSet() {
+			return fmt.Errorf("field '%s' can not be set", fieldType.Name)
+		}
+
+		target, err := pr.initializedProcess(processTag)
+		if err != nil {
+			return err
+		}
+
+		value := reflect.ValueOf(target)
+		if !value.Type().ConvertibleTo(fieldValue.Type()) {
+			return fmt.Errorf(
+				"field '%s' cannot be assigned a process of type %s",
+				fieldType.Name,
+				getTypeName(target),
+			)
+		}
+
+		fieldValue.Set(value.Convert(fieldValue.Type()))
+	}
+
+	return nil
+}
+
+// initializedProcess returns the process registered under the given name. It
+// is an error if no such process exists or if it has not been initialized.
+func (pr *ProcessRunner) initializedProcess(name string) (interface{}, error) {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	for _, processes := range pr.processes {
+		for _, process := range processes {
+			if process.Name() != name {
+				continue
+			}
+
+			if !process.initialized {
+				return nil, fmt.Errorf("process `%s` has not been initialized", name)
+			}
+
+			return injectionTarget(process.Process), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no process registered with name `%s`", name)
+}
@@ -0,0 +1,38 @@
+package nacelle
+
+import "fmt"
+
+// GroupStartedHook is called by the process runner once the processes of a
+// priority group have been started (see WithGroupStartedHook).
+type GroupStartedHook func(priority int) error
+
+// WithGroupStartedHook registers a function which is called once each process at
+// the given priority has been started and has become ready (see ProcessReadyNotifier
+// and WithStartTimeout). This is useful for announcing a service to a discovery
+// system only after all of the servers of that tier are up. Hooks are called in
+// the order of registration, and the next priority group is not initialized until
+// they have returned. A hook which returns an error fails the startup. A hook for
+// a priority without processes is never called.
+func WithGroupStartedHook(priority int, hook GroupStartedHook) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) {
+		pr.groupStartedHooks[priority] = append(pr.groupStartedHooks[priority], hook)
+	}
+}
+
+// runGroupStartedHooks calls the hooks registered for the given priority. The
+// first error is sent to the given channel. Returns false if a hook failed.
+func (pr *ProcessRunner) runGroupStartedHooks(priority int, errChan chan<- error) bool {
+	for _, hook := range pr.groupStartedHooks[priority] {
+		pr.logger.Debug("Running group started hook for priority %d", priority)
+		untrack := pr.track("running group started hook for priority %d", priority)
+		err := callSafely(func() error { return hook(priority) })
+		untrack()
+
+		if err != nil {
+			errChan <- fmt.Errorf("group started hook for priority %d returned error (%w)", priority, err)
+			return false
+		}
+	}
+
+	return true
+}
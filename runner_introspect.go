@@ -0,0 +1,59 @@
+package nacelle
+
+import "sort"
+
+// ProcessInfo describes a process registered with the process runner.
+type ProcessInfo struct {
+	Name     string
+	Priority int
+	Labels   []string
+	Tags     map[string]string
+}
+
+// Processes returns a description of each registered process, ordered by
+// priority and then by name.
+func (pr *ProcessRunner) Processes() []ProcessInfo {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	infos := []ProcessInfo{}
+	for _, processes := range pr.processes {
+		for _, process := range processes {
+			tags := map[string]string{}
+			for key, value := range process.tags {
+				tags[key] = value
+			}
+
+			infos = append(infos, ProcessInfo{
+				Name:     process.Name(),
+				Priority: process.priority,
+				Labels:   append([]string{}, process.labels...),
+				Tags:     tags,
+			})
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Priority != infos[j].Priority {
+			return infos[i].Priority < infos[j].Priority
+		}
+
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+// ProcessesWithTag returns the names of the registered processes which have
+// the given tag value, ordered by name.
+func (pr *ProcessRunner) ProcessesWithTag(key, value string) []string {
+	names := []string{}
+	for _, info := range pr.Processes() {
+		if v, ok := info.Tags[key]; ok && v == value {
+			names = append(names, info.Name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
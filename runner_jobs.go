@@ -0,0 +1,77 @@
+package nacelle
+
+import "sort"
+
+type (
+	// JobStatus describes the state of a job registered via RegisterJob.
+	JobStatus struct {
+		Name      string
+		Completed bool
+
+		// Err is the error returned from the Start method of the job, if it
+		// has completed unsuccessfully.
+		Err error
+	}
+
+	jobResult struct {
+		completed bool
+		err       error
+	}
+)
+
+// RegisterJob registers a process which runs once and is expected to return from
+// its Start method (e.g. a database migration or a cache warm-up task). The job
+// is otherwise treated as if it was registered by RegisterProcess. Unlike other
+// processes, the completion of a job does not start a graceful shutdown of the
+// application, even if it returns an error; the error is instead logged and written
+// to the error channel returned from Run. The Stop method of a job is only called
+// if the job is still running during shutdown. The state of each job can be read
+// via the Jobs method.
+func (pr *ProcessRunner) RegisterJob(job Process, processConfigs ...ProcessConfigFunc) {
+	pr.RegisterProcess(job, append(processConfigs, func(meta *processMeta) { meta.job = &jobResult{} })...)
+}
+
+// Jobs returns the state of each registered job, ordered by name.
+func (pr *ProcessRunner) Jobs() []JobStatus {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	statuses := []JobStatus{}
+	for _, processes := range pr.processes {
+		for _, process := range processes {
+			if process.job == nil {
+				continue
+			}
+
+			process.mutex.Lock()
+			statuses = append(statuses, JobStatus{
+				Name:      process.Name(),
+				Completed: process.job.completed,
+				Err:       process.job.err,
+			})
+			process.mutex.Unlock()
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+// completeJob records the result of a job whose Start method has returned.
+func (pr *ProcessRunner) completeJob(process *processMeta, err error, errChan chan<- error) {
+	process.mutex.Lock()
+	process.job.completed = true
+	process.job.err = err
+	process.mutex.Unlock()
+
+	if err == nil {
+		pr.logger.Info("%s has completed", process.Name())
+		return
+	}
+
+	pr.logger.ErrorWithFields(process.fields(errorFields(err)), "%s has failed", process.Name())
+	errChan <- err
+}
@@ -0,0 +1,145 @@
+package nacelle
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// LivenessPolicy determines how the process runner polls a running process
+	// which implements HealthChecker and what happens once the process is found
+	// to be wedged (running, but no longer doing useful work).
+	LivenessPolicy struct {
+		// Interval is the time between checks. A check which does not return
+		// within the interval is counted as a failure. A non-positive value is
+		// treated as ten seconds.
+		Interval time.Duration
+
+		// FailureThreshold is the number of consecutive failed checks after which
+		// the action is taken. A value of zero is treated as one.
+		FailureThreshold int
+
+		// Action determines what happens to a wedged process.
+		Action LivenessAction
+	}
+
+	// LivenessAction is the action taken when a process fails its liveness checks.
+	LivenessAction int
+)
+
+const defaultLivenessInterval = time.Second * 10
+
+const (
+	// LivenessShutdown shuts the application down as if the process had
+	// returned a fatal error.
+	LivenessShutdown LivenessAction = iota
+
+	// LivenessRestart stops, re-initializes, and restarts the process.
+	LivenessRestart
+)
+
+func (a LivenessAction) String() string {
+	switch a {
+	case LivenessRestart:
+		return "restart"
+	default:
+		return "shutdown"
+	}
+}
+
+// startLivenessChecks begins polling each process registered with a liveness
+// policy. Each poller holds a slot in the runner's wait group until the runner
+// begins to shut down or the process exits.
+func (pr *ProcessRunner) startLivenessChecks(priorities []int) {
+	for _, priority := range priorities {
+		for _, process := range pr.getProcesses(priority) {
+			checker, ok := injectionTarget(process.Process).(HealthChecker)
+			if !ok || process.livenessPolicy == nil {
+				continue
+			}
+
+			pr.wg.Add(1)
+			go pr.checkLiveness(process, checker, *process.livenessPolicy)
+		}
+	}
+}
+
+func (pr *ProcessRunner) checkLiveness(process *processMeta, checker HealthChecker, policy LivenessPolicy) {
+	defer pr.wg.Done()
+
+	ticker := time.NewTicker(policy.interval())
+	defer ticker.Stop()
+
+	var (
+		failures = 0
+		pending  chan error
+	)
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-pr.ctx.Done():
+			return
+		}
+
+		if hasExited(process) && !process.isExitExpected() {
+			return
+		}
+
+		if pending == nil {
+			pending = make(chan error, 1)
+			go func(result chan<- error) { result <- checker.HealthCheck() }(pending)
+		}
+
+		var err error
+		select {
+		case err = <-pending:
+			pending = nil
+		case <-time.After(policy.interval()):
+			err = fmt.Errorf("liveness check did not return within %s", policy.interval())
+		}
+
+		if err == nil {
+			failures = 0
+			continue
+		}
+
+		failures++
+		pr.logger.Warning("%s failed liveness check %d of %d (%s)", process.Name(), failures, policy.threshold(), err.Error())
+
+		if failures < policy.threshold() {
+			continue
+		}
+
+		failures = 0
+		err = fmt.Errorf("%s failed %d consecutive liveness checks (%s)", process.Name(), policy.threshold(), err.Error())
+		pr.record("%s, escalating by %s", err.Error(), policy.Action)
+
+		if policy.Action == LivenessShutdown {
+			pr.startErrors <- errMeta{err, process}
+			return
+		}
+
+		pr.logger.Error("%s, restarting", err.Error())
+
+		if err := pr.restartProcess(process); err != nil && err != ErrRunnerStopping {
+			pr.logger.Error("Failed to restart %s (%s)", process.Name(), err.Error())
+		}
+	}
+}
+
+func (p LivenessPolicy) interval() time.Duration {
+	if p.Interval <= 0 {
+		return defaultLivenessInterval
+	}
+
+	return p.Interval
+}
+
+func (p LivenessPolicy) threshold() int {
+	if p.FailureThreshold <= 0 {
+		return 1
+	}
+
+	return p.FailureThreshold
+}
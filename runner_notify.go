@@ -0,0 +1,58 @@
+package nacelle
+
+import (
+	"reflect"
+	"sort"
+)
+
+// notifyShutdown calls the ShuttingDown method of each service registered to the
+// runner's container which implements ShutdownAware, ordered by key. Services
+// registered via SetFactory are notified only if they have been constructed. A
+// service registered to multiple keys is notified once. A panic is logged rather
+// than aborting the shutdown.
+func (pr *ProcessRunner) notifyShutdown() {
+	var (
+		services = pr.container.snapshot()
+		keys     = []interface{}{}
+		notified = map[interface{}]struct{}{}
+	)
+
+	for key, service := range services {
+		if _, ok := service.(ShutdownAware); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return serializeKey(keys[i]) < serializeKey(keys[j])
+	})
+
+	pr.logger.Info("Notifying services of shutdown")
+	pr.record("Notifying services of shutdown")
+	defer pr.track("notifying services of shutdown")()
+
+	for _, key := range keys {
+		service := services[key]
+
+		if reflect.TypeOf(service).Comparable() {
+			if _, ok := notified[service]; ok {
+				continue
+			}
+
+			notified[service] = struct{}{}
+		}
+
+		err := callSafely(func() error {
+			service.(ShutdownAware).ShuttingDown()
+			return nil
+		})
+
+		if err != nil {
+			pr.logger.ErrorWithFields(errorFields(err), "Service `%s` panicked during shutdown notification (%s)", serializeKey(key), err.Error())
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package nacelle
+
+import "time"
+
+// WithOperationGracePeriod sets the time the runner waits, once a graceful shutdown
+// begins, before cancelling the long-running operations which are still outstanding
+// in the registry registered to the service key "operations". The default is zero,
+// in which case outstanding operations are logged but never cancelled.
+func WithOperationGracePeriod(gracePeriod time.Duration) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.operationGrace = gracePeriod }
+}
+
+// watchOperations logs the outstanding operations of the operation registry,
+// if one is registered, and cancels those which are still outstanding once the
+// grace period elapses. This does not block.
+func (pr *ProcessRunner) watchOperations() {
+	operations, err := Resolve[*Operations](pr.container, "operations")
+	if err != nil {
+		return
+	}
+
+	outstanding := operations.Outstanding()
+	if len(outstanding) == 0 {
+		return
+	}
+
+	pr.logger.Info("Waiting on %d outstanding operations (%s)", len(outstanding), describeOperations(outstanding))
+	pr.record("Waiting on %d outstanding operations", len(outstanding))
+
+	if pr.operationGrace == 0 {
+		return
+	}
+
+	go func() {
+		timer := time.NewTimer(pr.operationGrace)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-pr.done:
+			return
+		}
+
+		outstanding := operations.Outstanding()
+		if len(outstanding) == 0 {
+			return
+		}
+
+		pr.logger.Warning("Cancelling operations still outstanding after %s (%s)", pr.operationGrace, describeOperations(outstanding))
+		pr.record("Cancelled %d outstanding operations", operations.Cancel())
+	}()
+}
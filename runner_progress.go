@@ -0,0 +1,100 @@
+package nacelle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const defaultProgressInterval = time.Second * 15
+
+// WithProgressInterval sets the interval at which the runner logs the progress of
+// each initializer and process whose Init method has not yet returned, and of each
+// process which has not yet finished draining. The default is fifteen seconds. An
+// interval of zero disables progress logging.
+func WithProgressInterval(interval time.Duration) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.progressInterval = interval }
+}
+
+// Initializing returns the progress of each initializer and process whose Init
+// method is currently running, ordered by name.
+func (pr *ProcessRunner) Initializing() []Progress {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	progress := []Progress{}
+	for reporter := range pr.initializing {
+		progress = append(progress, reporter.Progress())
+	}
+
+	sort.Slice(progress, func(i, j int) bool {
+		return progress[i].Name < progress[j].Name
+	})
+
+	return progress
+}
+
+// initWithProgress calls initWithTimeout and logs the progress of the given
+// reporter periodically until the Init method returns.
+func (pr *ProcessRunner) initWithProgress(initializer Initializer, config Config, timeout time.Duration, reporter *ProgressReporter) error {
+	defer pr.track("initializing %s", reporter.name)()
+
+	reporter.reset()
+	pr.setInitializing(reporter, true)
+	defer pr.setInitializing(reporter, false)
+
+	done := make(chan struct{})
+	defer close(done)
+	go pr.logProgress(reporter, done)
+
+	tags := tagsOf(initializer)
+	pr.emit(EventInitStarted, reporter.name, tags, nil)
+
+	err := initWithTimeout(pr.bootCtx, initializer, config, timeout)
+	if err == ErrInitTimeout {
+		if step := reporter.Progress().step(); step != "" {
+			err = fmt.Errorf("init method did not finish within %s (stalled at %s)", timeout, step)
+		} else {
+			err = fmt.Errorf("init method did not finish within %s", timeout)
+		}
+	}
+
+	if err != nil {
+		pr.emit(EventErrored, reporter.name, tags, err)
+		return err
+	}
+
+	pr.emit(EventInitCompleted, reporter.name, tags, nil)
+	return nil
+}
+
+func (pr *ProcessRunner) setInitializing(reporter *ProgressReporter, initializing bool) {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	if initializing {
+		pr.initializing[reporter] = struct{}{}
+	} else {
+		delete(pr.initializing, reporter)
+	}
+}
+
+func (pr *ProcessRunner) logProgress(reporter *ProgressReporter, done <-chan struct{}) {
+	if pr.progressInterval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(pr.progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			progress := reporter.Progress()
+			pr.logger.InfoWithFields(progress.Fields(), "Still initializing %s", progress.String())
+
+		case <-done:
+			return
+		}
+	}
+}
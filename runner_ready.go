@@ -0,0 +1,87 @@
+package nacelle
+
+import (
+	"fmt"
+	"time"
+)
+
+// waitUntilReady blocks until each of the given processes which implements
+// ProcessReadyNotifier has reported that it is ready and until the start window
+// of each of the given processes with a start timeout has passed. If a process
+// exits or does not become ready within its ready timeout or start window, an
+// error is sent to the given channel. Returns false if the processes did not all
+// become ready or if the runner received an external shutdown request while
+// waiting.
+func (pr *ProcessRunner) waitUntilReady(processes []*processMeta, priority int, errChan chan<- error) bool {
+	started := time.Now()
+
+	for _, process := range processes {
+		notifier, _ := injectionTarget(process.Process).(ProcessReadyNotifier)
+		if notifier == nil && process.startTimeout == 0 {
+			continue
+		}
+
+		if !pr.waitForReady(process, notifier, started, priority, errChan) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (pr *ProcessRunner) waitForReady(process *processMeta, notifier ProcessReadyNotifier, started time.Time, priority int, errChan chan<- error) bool {
+	var ready <-chan struct{}
+	if notifier != nil {
+		ready = notifier.Ready()
+
+		pr.logger.Debug("Waiting for %s to become ready", process.Name())
+		defer pr.track("waiting for %s to become ready", process.Name())()
+	} else {
+		pr.logger.Debug("Waiting for the start window of %s to pass", process.Name())
+		defer pr.track("waiting for the start window of %s to pass", process.Name())()
+	}
+
+	var timeout <-chan time.Time
+	if notifier != nil && process.readyTimeout > 0 {
+		timer := time.NewTimer(process.readyTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var window <-chan time.Time
+	if process.startTimeout > 0 {
+		timer := time.NewTimer(time.Until(started.Add(process.startTimeout)))
+		defer timer.Stop()
+		window = timer.C
+	}
+
+	select {
+	case <-ready:
+		pr.logger.Debug("%s is ready", process.Name())
+		pr.record("%s is ready", process.Name())
+		return true
+
+	case <-process.getExited():
+		if notifier != nil {
+			errChan <- fmt.Errorf("%s exited before becoming ready", process.Name())
+		} else {
+			errChan <- fmt.Errorf("%s exited within its start window of %s", process.Name(), process.startTimeout)
+		}
+
+	case <-timeout:
+		errChan <- fmt.Errorf("%s did not become ready within %s", process.Name(), process.readyTimeout)
+
+	case <-window:
+		if notifier == nil {
+			pr.logger.Debug("Start window of %s has passed", process.Name())
+			return true
+		}
+
+		errChan <- fmt.Errorf("%s did not become ready within its start window of %s", process.Name(), process.startTimeout)
+
+	case <-pr.halt:
+		pr.logger.Info("Received external shutdown request while waiting for processes at priority %d", priority)
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package nacelle
+
+import (
+	"io"
+)
+
+// WithFlightRecorder sets a flight recorder to which the runner records lifecycle
+// events (initialization, start, exit, restart, and stop of each initializer and
+// process, as well as shutdown requests). If crashOutput is non-nil, the recorded
+// events are dumped to it when a process returns a fatal error or fails to
+// initialize.
+func WithFlightRecorder(recorder *FlightRecorder, crashOutput io.Writer) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) {
+		pr.recorder = recorder
+		pr.crashOutput = crashOutput
+	}
+}
+
+// record adds a lifecycle event to the runner's flight recorder, if one is set.
+func (pr *ProcessRunner) record(format string, args ...interface{}) {
+	if pr.recorder != nil {
+		pr.recorder.Record(FlightEventLifecycle, nil, format, args...)
+	}
+}
+
+// dumpOnCrash dumps the runner's flight recorder to its crash output, if both
+// are set.
+func (pr *ProcessRunner) dumpOnCrash() {
+	if pr.recorder == nil || pr.crashOutput == nil {
+		return
+	}
+
+	if err := pr.recorder.Dump(pr.crashOutput); err != nil {
+		pr.logger.Error("Failed to dump flight recorder (%s)", err.Error())
+	}
+}
@@ -0,0 +1,13 @@
+package nacelle
+
+// ReloadConfig reloads the given keys of the runner's config and rebuilds the
+// swappable services which depend on them (see ServiceContainer.HotReload). A
+// successful reload is reported to the runner's subscribers as EventReloaded.
+func (pr *ProcessRunner) ReloadConfig(keys ...interface{}) error {
+	if err := pr.container.HotReload(pr.config, keys...); err != nil {
+		return err
+	}
+
+	pr.RecordReload("config")
+	return nil
+}
@@ -0,0 +1,146 @@
+package nacelle
+
+import (
+	"fmt"
+	"sync"
+)
+
+type replicaSet struct {
+	factory ProcessFactory
+	configs []ProcessConfigFunc
+	members []*processMeta
+	mutex   sync.RWMutex
+}
+
+func (s *replicaSet) makeReplica(index int) *processMeta {
+	meta := &processMeta{Process: s.factory()}
+
+	for _, f := range s.configs {
+		f(meta)
+	}
+
+	meta.replica = &Replica{Index: index, set: s}
+
+	s.mutex.Lock()
+	s.members = append(s.members, meta)
+	s.mutex.Unlock()
+
+	return meta
+}
+
+func (s *replicaSet) size() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.members)
+}
+
+func (s *replicaSet) removeLast() *processMeta {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	last := s.members[len(s.members)-1]
+	s.members = s.members[:len(s.members)-1]
+	return last
+}
+
+// Scale changes the number of running replicas of the process factory registered
+// with the given name. If the number of replicas increases, new instances are
+// created, injected, initialized, and started. If the number of replicas decreases,
+// the instances with the highest indices are stopped and removed. A replica which
+// is stopped by scaling will not trigger a shutdown of the application.
+func (pr *ProcessRunner) Scale(name string, replicas int) error {
+	if !pr.isRunning() {
+		return ErrRunnerNotRunning
+	}
+
+	if replicas < 0 {
+		return fmt.Errorf("illegal replica count %d", replicas)
+	}
+
+	set, ok := pr.replicaSets[name]
+	if !ok {
+		return fmt.Errorf("no process factory registered with name `%s`", name)
+	}
+
+	current := set.size()
+	if replicas != current {
+		pr.logger.Info("Scaling %s from %d to %d replicas", name, current, replicas)
+	}
+
+	for i := current; i < replicas; i++ {
+		if err := pr.addReplica(set, i); err != nil {
+			return err
+		}
+	}
+
+	for i := current; i > replicas; i-- {
+		if err := pr.removeReplica(set); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pr *ProcessRunner) addReplica(set *replicaSet, index int) error {
+	process := set.makeReplica(index)
+	pr.emit(EventProcessRegistered, process.Name(), process.tags, nil)
+
+	if err := pr.injectProcess(process); err != nil {
+		set.removeLast()
+		return fmt.Errorf("failed to inject services into %s (%s)", process.Name(), err.Error())
+	}
+
+	if err := pr.initProcess(process); err != nil {
+		set.removeLast()
+		return err
+	}
+
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	if pr.stopping {
+		set.removeLast()
+		return ErrRunnerStopping
+	}
+
+	pr.numProcesses++
+	pr.processes[process.priority] = append(pr.processes[process.priority], process)
+	pr.wg.Add(1)
+	pr.startProcess(process)
+	return nil
+}
+
+func (pr *ProcessRunner) removeReplica(set *replicaSet) error {
+	pr.mutex.Lock()
+	if pr.stopping {
+		pr.mutex.Unlock()
+		return ErrRunnerStopping
+	}
+
+	process := set.removeLast()
+	process.setExitExpected(true)
+
+	processes := []*processMeta{}
+	for _, p := range pr.processes[process.priority] {
+		if p != process {
+			processes = append(processes, p)
+		}
+	}
+
+	pr.numProcesses--
+	pr.processes[process.priority] = processes
+	pr.mutex.Unlock()
+
+	pr.logger.Info("Stopping %s", process.Name())
+	pr.record("Stopping %s", process.Name())
+	pr.emit(EventStopping, process.Name(), process.tags, nil)
+
+	if err := callSafely(process.Stop); err != nil {
+		return fmt.Errorf("%s returned error from stop (%w)", process.Name(), err)
+	}
+
+	<-process.getExited()
+	return pr.finalizeOne(injectionTarget(process.Process), process.Name())
+}
@@ -0,0 +1,128 @@
+package nacelle
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrRunnerNotRunning is returned when an operation on running processes
+	// is requested before the process runner has been started.
+	ErrRunnerNotRunning = errors.New("process runner is not running")
+
+	// ErrRunnerStopping is returned when an operation on running processes is
+	// requested after the process runner has begun shutting down.
+	ErrRunnerStopping = errors.New("process runner is shutting down")
+)
+
+// RollingRestart stops, re-initializes, and restarts each running process with
+// the given label. Processes are restarted in batches of the given size (in order
+// of priority and registration), with the given delay between batches, so that
+// the remaining processes of the set keep running while a batch is restarted.
+// A restarting process will not trigger a shutdown of the application when its
+// Start method returns. A process must be able to be re-initialized after it
+// has been stopped in order to be restarted.
+func (pr *ProcessRunner) RollingRestart(label string, batchSize int, delay time.Duration) error {
+	if !pr.isRunning() {
+		return ErrRunnerNotRunning
+	}
+
+	if batchSize <= 0 {
+		return fmt.Errorf("illegal batch size %d", batchSize)
+	}
+
+	processes := pr.getLabeledProcesses(label)
+	if len(processes) == 0 {
+		return fmt.Errorf("no processes registered with label `%s`", label)
+	}
+
+	pr.logger.Info("Restarting %d processes with label %s", len(processes), label)
+
+	for i := 0; i < len(processes); i += batchSize {
+		if i > 0 {
+			select {
+			case <-time.After(delay):
+			case <-pr.halt:
+				return ErrRunnerStopping
+			}
+		}
+
+		end := i + batchSize
+		if end > len(processes) {
+			end = len(processes)
+		}
+
+		for _, process := range processes[i:end] {
+			if err := pr.restartProcess(process); err != nil {
+				return err
+			}
+		}
+	}
+
+	pr.logger.Info("Restarted %d processes with label %s", len(processes), label)
+	return nil
+}
+
+func (pr *ProcessRunner) getLabeledProcesses(label string) []*processMeta {
+	processes := []*processMeta{}
+	for _, priority := range pr.getPriorities() {
+		for _, process := range pr.getProcesses(priority) {
+			if process.hasLabel(label) {
+				processes = append(processes, process)
+			}
+		}
+	}
+
+	return processes
+}
+
+func (pr *ProcessRunner) restartProcess(process *processMeta) error {
+	pr.mutex.Lock()
+	if pr.stopping {
+		pr.mutex.Unlock()
+		return ErrRunnerStopping
+	}
+
+	// Hold a slot in the wait group while the process is restarting
+	// so that the runner does not consider all processes as exited.
+	pr.wg.Add(1)
+	pr.mutex.Unlock()
+
+	process.setExitExpected(true)
+	defer process.setExitExpected(false)
+
+	pr.logger.Info("Restarting %s", process.Name())
+	pr.emit(EventRestarting, process.Name(), process.tags, nil)
+
+	if err := callSafely(process.Stop); err != nil {
+		pr.wg.Done()
+		return fmt.Errorf("%s returned error from stop (%w)", process.Name(), err)
+	}
+
+	<-process.getExited()
+
+	if err := pr.injectProcess(process); err != nil {
+		pr.wg.Done()
+		return fmt.Errorf("failed to inject services into %s (%s)", process.Name(), err.Error())
+	}
+
+	if err := pr.initProcess(process); err != nil {
+		pr.wg.Done()
+		return err
+	}
+
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+
+	if pr.stopping {
+		pr.wg.Done()
+		return ErrRunnerStopping
+	}
+
+	process.setExitExpected(false)
+	process.abandoned = false
+	pr.setDegraded(process.Name(), false)
+	pr.startProcess(process)
+	return nil
+}
@@ -0,0 +1,34 @@
+package nacelle
+
+import "fmt"
+
+// rollback calls the Rollback method of each initialized initializer which
+// implements Rollbacker in reverse order of registration. This is called when
+// an initializer fails so that the resources acquired by the initializers which
+// have already run are released.
+func (pr *ProcessRunner) rollback(errChan chan<- error) {
+	for i := len(pr.initializers) - 1; i >= 0; i-- {
+		if initializer := pr.initializers[i]; initializer.initialized {
+			if err := pr.rollbackOne(initializer); err != nil {
+				errChan <- err
+			}
+		}
+	}
+}
+
+func (pr *ProcessRunner) rollbackOne(initializer *initializerMeta) error {
+	rollbacker, ok := initializer.Initializer.(Rollbacker)
+	if !ok {
+		return nil
+	}
+
+	pr.logger.Info("Rolling back %s", initializer.Name())
+	pr.record("Rolling back %s", initializer.Name())
+	defer pr.track("rolling back %s", initializer.Name())()
+
+	if err := callSafely(rollbacker.Rollback); err != nil {
+		return fmt.Errorf("%s returned error from rollback (%w)", initializer.Name(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package nacelle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// SelfTestReport describes the result of a self-test (see the method SelfTest
+	// of the process runner).
+	SelfTestReport struct {
+		Results []SelfTestResult `json:"results"`
+	}
+
+	// SelfTestResult is the result of one stage of a self-test for a single
+	// initializer or process.
+	SelfTestResult struct {
+		// Name is the name of the initializer or process.
+		Name string `json:"name"`
+
+		// Stage is the stage which produced the result (e.g. "inject", "init",
+		// or "self-test").
+		Stage string `json:"stage"`
+
+		Passed   bool    `json:"passed"`
+		Error    string  `json:"error,omitempty"`
+		Duration float64 `json:"duration"`
+	}
+)
+
+// SelfTest verifies that the application is able to run without starting any
+// process. Each initializer is run and each process is injected and initialized
+// as if by Run. Then, the SelfTest method of each initializer and process which
+// implements SelfTester is called, in the same order. Every self-test is run even
+// if another fails. Finally, each initialized initializer and process is finalized.
+// If an initializer or process fails to initialize, no self-test is run. The
+// returned report contains a result for each self-test and for each failure to
+// initialize.
+func (pr *ProcessRunner) SelfTest(ctx context.Context, config Config, logger Logger) *SelfTestReport {
+	pr.bootCtx = ctx
+	pr.config = config
+	pr.logger = logger
+	pr.wg = &sync.WaitGroup{}
+	pr.logDuplicates()
+
+	var (
+		report     = &SelfTestReport{}
+		priorities = pr.getPriorities()
+	)
+
+	if err := pr.runInitializers(); err != nil {
+		report.add(pr.failedInitializer(), StageInit, err, 0)
+		pr.finalizeSelfTest(nil, true)
+		return report
+	}
+
+	defer pr.finalizeSelfTest(priorities, false)
+
+	for _, priority := range priorities {
+		for _, process := range pr.processes[priority] {
+			if err := pr.injectProcess(process); err != nil {
+				report.add(process.Name(), StageInject, fmt.Errorf("failed to inject services (%s)", err.Error()), 0)
+			}
+		}
+	}
+
+	if !report.Passed() {
+		return report
+	}
+
+	for _, priority := range priorities {
+		for _, process := range pr.processes[priority] {
+			if err := pr.initProcess(process); err != nil {
+				report.add(process.Name(), StageInit, err, 0)
+				return report
+			}
+		}
+	}
+
+	for _, initializer := range pr.initializers {
+		pr.selfTestOne(ctx, report, initializer.Initializer, initializer.Name())
+	}
+
+	for _, priority := range priorities {
+		for _, process := range pr.processes[priority] {
+			pr.selfTestOne(ctx, report, injectionTarget(process.Process), process.Name())
+		}
+	}
+
+	return report
+}
+
+func (pr *ProcessRunner) selfTestOne(ctx context.Context, report *SelfTestReport, target interface{}, name string) {
+	tester, ok := target.(SelfTester)
+	if !ok {
+		return
+	}
+
+	pr.logger.Info("Running self-test of %s", name)
+	defer pr.track("running self-test of %s", name)()
+
+	started := time.Now()
+	err := callSafely(func() error { return tester.SelfTest(ctx) })
+	report.add(name, StageSelfTest, err, time.Since(started))
+}
+
+// failedInitializer returns the name of the first initializer which has not
+// been initialized.
+func (pr *ProcessRunner) failedInitializer() string {
+	for _, initializer := range pr.initializers {
+		if !initializer.initialized {
+			return initializer.Name()
+		}
+	}
+
+	return ""
+}
+
+// finalizeSelfTest finalizes each initialized initializer and process and logs
+// any error. If rollback is true, initialized initializers are first rolled back
+// as they would be by Run after an initializer failure.
+func (pr *ProcessRunner) finalizeSelfTest(priorities []int, rollback bool) {
+	errChan := make(chan error, pr.numProcesses+len(pr.initializers)*2+1)
+	if rollback {
+		pr.rollback(errChan)
+	}
+
+	pr.finalize(priorities, errChan)
+	close(errChan)
+
+	for err := range errChan {
+		pr.logger.Error("%s", err.Error())
+	}
+}
+
+func (r *SelfTestReport) add(name, stage string, err error, duration time.Duration) {
+	result := SelfTestResult{
+		Name:     name,
+		Stage:    stage,
+		Passed:   err == nil,
+		Duration: duration.Seconds(),
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	r.Results = append(r.Results, result)
+}
+
+// Passed returns true if every result of the report passed.
+func (r *SelfTestReport) Passed() bool {
+	return r.Err() == nil
+}
+
+// Err returns an error describing each failed result of the report, or nil if
+// every result passed.
+func (r *SelfTestReport) Err() error {
+	messages := []string{}
+	for _, result := range r.Results {
+		if !result.Passed {
+			messages = append(messages, fmt.Sprintf("%s: %s: %s", result.Stage, result.Name, result.Error))
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("self-test failed (%s)", strings.Join(messages, "; "))
+}
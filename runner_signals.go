@@ -0,0 +1,16 @@
+package nacelle
+
+import "os"
+
+// WithSignals sets the signals which start a graceful shutdown of the runner,
+// replacing the default signals (SIGINT and SIGTERM).
+func WithSignals(signals ...os.Signal) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.signals = signals }
+}
+
+// WithoutSignals disables signal handling. A graceful shutdown can then only be
+// started by a call to Shutdown, by the cancellation of the context passed to
+// RunContext, or by the exit of a process.
+func WithoutSignals() ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.signals = nil }
+}
@@ -0,0 +1,161 @@
+package nacelle
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Spec is a declarative description of the initializers and processes of an
+	// application. A spec can be written as a Go literal or decoded from JSON or
+	// YAML, in which case initializers and processes refer to the types registered
+	// with the runner via WithInitializerType and WithProcessType. See the method
+	// RegisterFromSpec.
+	Spec struct {
+		Initializers []InitializerSpec `json:"initializers" yaml:"initializers"`
+		Processes    []ProcessSpec     `json:"processes" yaml:"processes"`
+	}
+
+	// InitializerSpec describes one initializer of a Spec. Exactly one of Type
+	// and Initializer must be set.
+	InitializerSpec struct {
+		Name        string      `json:"name" yaml:"name"`
+		Type        string      `json:"type" yaml:"type"`
+		Key         string      `json:"key" yaml:"key"`
+		Initializer Initializer `json:"-" yaml:"-"`
+	}
+
+	// ProcessSpec describes one process of a Spec. Exactly one of Type and
+	// Factory must be set. A named process with a replica count (even a count
+	// of one) is registered as if by RegisterProcessFactory, so that it can be
+	// scaled by name, and a job as if by RegisterJob.
+	ProcessSpec struct {
+		Name         string            `json:"name" yaml:"name"`
+		Type         string            `json:"type" yaml:"type"`
+		Priority     int               `json:"priority" yaml:"priority"`
+		Labels       []string          `json:"labels" yaml:"labels"`
+		Tags         map[string]string `json:"tags" yaml:"tags"`
+		Replicas     int               `json:"replicas" yaml:"replicas"`
+		ConfigPrefix string            `json:"config_prefix" yaml:"config_prefix"`
+		SilentExit   bool              `json:"silent_exit" yaml:"silent_exit"`
+		Job          bool              `json:"job" yaml:"job"`
+		Factory      ProcessFactory    `json:"-" yaml:"-"`
+	}
+
+	// InitializerFactory creates a new instance of an initializer.
+	InitializerFactory func() Initializer
+)
+
+// WithInitializerType registers an initializer factory which can be referred to
+// by name from the Type field of an InitializerSpec.
+func WithInitializerType(name string, factory InitializerFactory) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.initializerTypes[name] = factory }
+}
+
+// WithProcessType registers a process factory which can be referred to by name
+// from the Type field of a ProcessSpec.
+func WithProcessType(name string, factory ProcessFactory) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.processTypes[name] = factory }
+}
+
+// RegisterFromSpec registers the initializers and processes described by the
+// given spec. Initializers are registered in the order they are listed. The spec
+// is validated before anything is registered, and an error describing every
+// problem with the spec is returned if it is invalid.
+func (pr *ProcessRunner) RegisterFromSpec(spec Spec) error {
+	if err := pr.validateSpec(spec); err != nil {
+		return err
+	}
+
+	for _, s := range spec.Initializers {
+		initializer := s.Initializer
+		if initializer == nil {
+			initializer = pr.initializerTypes[s.Type]()
+		}
+
+		pr.RegisterInitializer(initializer, WithInitializerName(s.Name), WithInitializerKey(s.Key))
+	}
+
+	for _, s := range spec.Processes {
+		factory := s.Factory
+		if factory == nil {
+			factory = pr.processTypes[s.Type]
+		}
+
+		configs := []ProcessConfigFunc{
+			WithProcessName(s.Name),
+			WithPriority(s.Priority),
+			WithProcessLabels(s.Labels...),
+			WithProcessTags(s.Tags),
+			WithProcessConfigPrefix(s.ConfigPrefix),
+		}
+
+		if s.SilentExit {
+			configs = append(configs, WithSilentExit())
+		}
+
+		switch {
+		case s.Job:
+			pr.RegisterJob(factory(), configs...)
+		case s.Name != "" && s.Replicas >= 1:
+			pr.RegisterProcessFactory(factory, append(configs, WithReplicas(s.Replicas))...)
+		default:
+			pr.RegisterProcess(factory(), configs...)
+		}
+	}
+
+	return nil
+}
+
+func (pr *ProcessRunner) validateSpec(spec Spec) error {
+	problems := []string{}
+
+	for i, s := range spec.Initializers {
+		switch {
+		case s.Type == "" && s.Initializer == nil:
+			problems = append(problems, fmt.Sprintf("initializer %d (%s) has no type", i, s.Name))
+		case s.Type != "" && s.Initializer != nil:
+			problems = append(problems, fmt.Sprintf("initializer %d (%s) has both a type and an instance", i, s.Name))
+		case s.Type != "" && pr.initializerTypes[s.Type] == nil:
+			problems = append(problems, fmt.Sprintf("initializer %d (%s) has unknown type `%s`", i, s.Name, s.Type))
+		}
+	}
+
+	names := map[string]int{}
+	for _, s := range spec.Processes {
+		names[s.Name]++
+	}
+
+	for i, s := range spec.Processes {
+		switch {
+		case s.Type == "" && s.Factory == nil:
+			problems = append(problems, fmt.Sprintf("process %d (%s) has no type", i, s.Name))
+		case s.Type != "" && s.Factory != nil:
+			problems = append(problems, fmt.Sprintf("process %d (%s) has both a type and a factory", i, s.Name))
+		case s.Type != "" && pr.processTypes[s.Type] == nil:
+			problems = append(problems, fmt.Sprintf("process %d (%s) has unknown type `%s`", i, s.Name, s.Type))
+		}
+
+		if s.Replicas < 0 {
+			problems = append(problems, fmt.Sprintf("process %d (%s) has a negative number of replicas", i, s.Name))
+		}
+
+		if s.Job && s.Replicas > 1 {
+			problems = append(problems, fmt.Sprintf("process %d (%s) is a job with multiple replicas", i, s.Name))
+		}
+
+		if s.Replicas > 1 && s.Name == "" {
+			problems = append(problems, fmt.Sprintf("process %d has multiple replicas but no name", i))
+		}
+
+		if s.Replicas >= 1 && s.Name != "" && names[s.Name] > 1 {
+			problems = append(problems, fmt.Sprintf("process %d (%s) has replicas but its name is not unique", i, s.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid spec (%s)", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package nacelle
+
+type (
+	// HealthStatus is the aggregate health of an application.
+	HealthStatus int
+)
+
+const (
+	// StatusStarting indicates that processes are still being initialized.
+	StatusStarting HealthStatus = iota
+
+	// StatusHealthy indicates that all processes are running and that the
+	// health tracker reports no problems.
+	StatusHealthy
+
+	// StatusUnhealthy indicates that the health tracker reports a problem or
+	// that the application is shutting down.
+	StatusUnhealthy
+)
+
+func (s HealthStatus) String() string {
+	switch s {
+	case StatusStarting:
+		return "starting"
+	case StatusHealthy:
+		return "healthy"
+	default:
+		return "unhealthy"
+	}
+}
+
+// Status returns the aggregate health of the application. The health tracker is
+// read from the service key "health" of the runner's service container. If no
+// health tracker is registered, only the crash loop health of the runner itself
+// is considered.
+func (pr *ProcessRunner) Status() HealthStatus {
+	if pr.isStopping() {
+		return StatusUnhealthy
+	}
+
+	if !pr.isRunning() {
+		return StatusStarting
+	}
+
+	if raw, err := pr.container.Get("health"); err == nil {
+		if health, ok := raw.(*Health); ok && !health.Healthy() {
+			return StatusUnhealthy
+		}
+	} else if pr.HealthCheck() != nil {
+		return StatusUnhealthy
+	}
+
+	return StatusHealthy
+}
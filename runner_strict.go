@@ -0,0 +1,35 @@
+package nacelle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithStrictServices causes the runner to check, once all processes are running,
+// that each service registered to its container has been retrieved (see
+// UnusedServices). If any service is unused, an error is reported and a graceful
+// shutdown is started. This catches dead wiring and mistyped `service` tags in
+// large applications.
+func WithStrictServices() ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.strictServices = true }
+}
+
+// checkUnusedServices reports the unused services of the runner's container and
+// requests a shutdown if there are any and the runner is in strict mode.
+func (pr *ProcessRunner) checkUnusedServices(errChan chan<- error) {
+	if !pr.strictServices {
+		return
+	}
+
+	unused := pr.container.UnusedServices()
+	if len(unused) == 0 {
+		return
+	}
+
+	pr.logger.Error("Found %d services which were registered but never retrieved (%s)", len(unused), strings.Join(unused, ", "))
+	errChan <- fmt.Errorf("services registered but never retrieved: %s", strings.Join(unused, ", "))
+
+	pr.once.Do(func() {
+		close(pr.halt)
+	})
+}
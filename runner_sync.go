@@ -0,0 +1,51 @@
+package nacelle
+
+import (
+	"fmt"
+	"time"
+)
+
+const defaultLogSyncTimeout = time.Second * 5
+
+// ErrLogSyncTimeout is returned when a logger does not finish syncing in time.
+var ErrLogSyncTimeout = fmt.Errorf("logger did not finish syncing within timeout")
+
+// WithLogSyncTimeout sets the maximum time the runner waits for the logger to
+// sync once all processes have stopped. The default is five seconds.
+func WithLogSyncTimeout(timeout time.Duration) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.logSyncTimeout = timeout }
+}
+
+// syncLogs syncs the runner's logger as the final step of shutdown so that
+// the last messages logged by the runner and its processes (e.g. the error
+// of a crashed process) are flushed before the error channel is closed. A
+// timeout is sent to the given channel. Any other error is reported to the
+// emergency logger, as the runner's logger may be unable to write it.
+func (pr *ProcessRunner) syncLogs(errChan chan<- error) {
+	if err := syncLogger(pr.logger, pr.logSyncTimeout); err != nil {
+		if err == ErrLogSyncTimeout {
+			errChan <- fmt.Errorf("logger did not finish syncing within %s", pr.logSyncTimeout)
+			return
+		}
+
+		emergencyLogger().Error("failed to sync logs on shutdown (%s)", err.Error())
+	}
+}
+
+// syncLogger calls the logger's Sync method and waits for it to return for
+// at most the given timeout. A zero timeout waits indefinitely.
+func syncLogger(logger Logger, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() { errs <- logger.Sync() }()
+
+	if timeout == 0 {
+		return <-errs
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	case <-time.After(timeout):
+		return ErrLogSyncTimeout
+	}
+}
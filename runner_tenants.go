@@ -0,0 +1,92 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// Tenant is an entry of a config list from which a process is instantiated
+	// via RegisterTenantProcesses. An entry generally carries the settings of a
+	// single process (e.g. the topic and credentials of a consumer).
+	Tenant interface {
+		// TenantName returns a name which is unique among the entries of the
+		// list. It is used to derive the name of the process for this entry.
+		TenantName() string
+	}
+
+	// TenantProcessFactory creates the process for a single config entry.
+	TenantProcessFactory func(tenant Tenant) (Process, error)
+)
+
+// RegisterTenantProcesses registers one process for each entry of the given
+// config list, which must be a slice or array whose elements (or pointers to
+// whose elements) implement Tenant. Each process is created by the factory and
+// is registered as if by RegisterProcess with the given configuration. The name
+// of each process is formatted as name[tenant], where tenant is the result of
+// the entry's TenantName method. This method must be called after the config
+// has been loaded and before Run (e.g. from an AppInitFunc). No process is
+// registered if any entry is invalid or if the factory returns an error.
+func (pr *ProcessRunner) RegisterTenantProcesses(name string, tenants interface{}, factory TenantProcessFactory, processConfigs ...ProcessConfigFunc) error {
+	value := reflect.ValueOf(tenants)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return fmt.Errorf("tenant list must be a slice, got %s", getTypeName(tenants))
+	}
+
+	var (
+		names = map[string]struct{}{}
+		metas = []*processMeta{}
+	)
+
+	for i := 0; i < value.Len(); i++ {
+		tenant, ok := getTenant(value.Index(i))
+		if !ok {
+			return fmt.Errorf(
+				"tenant at index %d does not implement nacelle.Tenant (%s)",
+				i,
+				value.Index(i).Type().String(),
+			)
+		}
+
+		tenantName := tenant.TenantName()
+		if _, ok := names[tenantName]; ok {
+			return fmt.Errorf("duplicate tenant name `%s`", tenantName)
+		}
+
+		names[tenantName] = struct{}{}
+
+		process, err := factory(tenant)
+		if err != nil {
+			return fmt.Errorf("failed to create process for tenant %s (%s)", tenantName, err.Error())
+		}
+
+		meta := &processMeta{Process: process}
+
+		for _, f := range processConfigs {
+			f(meta)
+		}
+
+		meta.name = fmt.Sprintf("%s[%s]", name, tenantName)
+		metas = append(metas, meta)
+	}
+
+	for _, meta := range metas {
+		pr.addProcess(meta)
+	}
+
+	return nil
+}
+
+func getTenant(value reflect.Value) (Tenant, bool) {
+	if tenant, ok := value.Interface().(Tenant); ok {
+		return tenant, true
+	}
+
+	if value.CanAddr() {
+		if tenant, ok := value.Addr().Interface().(Tenant); ok {
+			return tenant, true
+		}
+	}
+
+	return nil, false
+}
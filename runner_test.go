@@ -1,8 +1,14 @@
 package nacelle
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aphistic/sweet"
 	. "github.com/onsi/gomega"
@@ -368,6 +374,2453 @@ func (s *RunnerSuite) TestInitializationError(t sweet.T) {
 	Eventually(errChan).Should(BeClosed())
 }
 
+func (s *RunnerSuite) TestInjectionErrors(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer())
+		initChan = make(chan string, 3)
+	)
+
+	makeProcess := func(name string) Process {
+		p := &mockProcess{}
+		p.init = func(config Config) error { initChan <- name; return nil }
+		p.start = func() error { return nil }
+		p.stop = func() error { return nil }
+		return p
+	}
+
+	runner.RegisterProcess(&injectedProcess{Process: makeProcess("proc1")}, WithProcessName("foo"), WithPriority(1))
+	runner.RegisterProcess(makeProcess("proc2"), WithPriority(1))
+	runner.RegisterProcess(&injectedProcess{Process: makeProcess("proc3")}, WithProcessName("bar"), WithPriority(2))
+
+	var errs []error
+	for err := range runner.Run(nil, log.NewNilLogger()) {
+		errs = append(errs, err)
+	}
+
+	// All injection errors are reported together before any process is initialized
+	Expect(errs).To(HaveLen(1))
+	Expect(initChan).NotTo(Receive())
+
+	report, ok := errs[0].(*StartupReport)
+	Expect(ok).To(BeTrue())
+	Expect(report.Problems).To(HaveLen(2))
+	Expect(report.Problems[0].Source).To(Equal("foo"))
+	Expect(report.Problems[1].Source).To(Equal("bar"))
+	Expect(report.Problems[0].Stage).To(Equal(StageInject))
+	Expect(report.Error()).To(HavePrefix("found 2 startup problems"))
+}
+
+func (s *RunnerSuite) TestProgress(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer(), WithProgressInterval(time.Millisecond*10))
+		logger   = &progressLogger{Logger: log.NewNilLogger(), messages: make(chan string, 100)}
+		process  = &progressProcess{Process: makeBlockingProcess(), proceed: make(chan struct{})}
+		errChan  = make(chan error)
+		expected = "Still initializing migrator (0s elapsed, step 2/3, loading schema)"
+	)
+
+	runner.RegisterProcess(process, WithProcessName("migrator"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(logger.messages).Should(Receive(Equal(expected)))
+
+	progress := runner.Initializing()
+	Expect(progress).To(HaveLen(1))
+	Expect(progress[0].Name).To(Equal("migrator"))
+	Expect(progress[0].Step).To(Equal(2))
+	Expect(progress[0].Steps).To(Equal(3))
+	Expect(progress[0].Message).To(Equal("loading schema"))
+
+	close(process.proceed)
+	Eventually(runner.Initializing).Should(BeEmpty())
+
+	process.Stop()
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestBreakpoint(t sweet.T) {
+	var (
+		breakpoint = NewManualBreakpoint()
+		runner     = NewProcessRunner(NewServiceContainer(), WithBreakpoint(breakpoint.Wait))
+		initChan   = make(chan string, 2)
+		errChan    = make(chan error)
+	)
+
+	makeProcess := func(name string) Process {
+		p := makeBlockingProcess().(*mockProcess)
+		p.init = func(config Config) error { initChan <- name; return nil }
+		return p
+	}
+
+	runner.RegisterProcess(makeProcess("proc1"), WithPriority(1))
+	runner.RegisterProcess(makeProcess("proc2"), WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	// Paused after first priority group
+	Eventually(initChan).Should(Receive(Equal("proc1")))
+	Eventually(breakpoint.Paused).Should(BeTrue())
+	Consistently(initChan).ShouldNot(Receive())
+
+	// Paused after second priority group
+	breakpoint.Resume()
+	Eventually(initChan).Should(Receive(Equal("proc2")))
+	Eventually(breakpoint.Paused).Should(BeTrue())
+	Expect(runner.isRunning()).To(BeFalse())
+
+	breakpoint.Resume()
+	Eventually(runner.isRunning).Should(BeTrue())
+
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestBreakpointShutdown(t sweet.T) {
+	var (
+		breakpoint = NewManualBreakpoint()
+		runner     = NewProcessRunner(NewServiceContainer(), WithBreakpoint(breakpoint.Wait))
+		errChan    = make(chan error)
+	)
+
+	runner.RegisterProcess(makeBlockingProcess(), WithPriority(1))
+	runner.RegisterProcess(makeBlockingProcess(), WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(breakpoint.Paused).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+	Expect(runner.isRunning()).To(BeFalse())
+}
+
+func (s *RunnerSuite) TestContextProcess(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		runner    = NewProcessRunner(container)
+		process   = &mockContextProcess{started: make(chan struct{}), stopped: make(chan error, 1)}
+		errChan   = make(chan error)
+	)
+
+	container.Set("value", &IntWrapper{10})
+	runner.RegisterContextProcess(process)
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(process.started).Should(BeClosed())
+	Expect(process.Value).To(Equal(&IntWrapper{10}))
+	Expect(process.initCtx.Err()).To(BeNil())
+
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	// Init and Start contexts are canceled, Stop context is not
+	Expect(process.initCtx.Err()).To(Equal(context.Canceled))
+	Eventually(process.stopped).Should(Receive(BeNil()))
+}
+
+func (s *RunnerSuite) TestRunContextBootDeadline(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		process = makeBlockingProcess().(*mockProcess)
+		block   = make(chan struct{})
+		errChan = make(chan error)
+	)
+
+	defer close(block)
+	process.init = func(config Config) error { <-block; return nil }
+	runner.RegisterProcess(process, WithProcessName("slow"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.RunContext(ctx, nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("failed to initialize slow (context deadline exceeded)")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRunContextCancel(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	runner.RegisterProcess(makeBlockingProcess())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.RunContext(ctx, nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(errChan).ShouldNot(BeClosed())
+
+	cancel()
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRestartPolicy(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		process   = makeBlockingProcess().(*mockProcess)
+		block     = process.start
+		initChan  = make(chan struct{}, 3)
+		startChan = make(chan struct{}, 3)
+		errChan   = make(chan error)
+		starts    = 0
+	)
+
+	process.init = func(config Config) error {
+		initChan <- struct{}{}
+		return nil
+	}
+
+	process.start = func() error {
+		startChan <- struct{}{}
+
+		if starts++; starts < 3 {
+			return errors.New("utoh")
+		}
+
+		return block()
+	}
+
+	runner.RegisterProcess(process, WithProcessName("flaky"), WithRestart(RestartPolicy{
+		Condition:   RestartOnFailure,
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		Eventually(initChan).Should(Receive())
+		Eventually(startChan).Should(Receive())
+	}
+
+	Consistently(errChan).ShouldNot(Receive())
+
+	process.Stop()
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRestartPolicyExhausted(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		process   = makeBlockingProcess().(*mockProcess)
+		startChan = make(chan struct{}, 3)
+		errChan   = make(chan error)
+	)
+
+	process.start = func() error {
+		startChan <- struct{}{}
+		return errors.New("utoh")
+	}
+
+	runner.RegisterProcess(process, WithProcessName("flaky"), WithRestart(RestartPolicy{
+		Condition:   RestartOnFailure,
+		MaxAttempts: 2,
+		Backoff:     time.Millisecond,
+	}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("flaky returned a fatal error (utoh)")))
+	Eventually(errChan).Should(BeClosed())
+	Expect(startChan).To(HaveLen(3))
+}
+
+func (s *RunnerSuite) TestRestartPolicyResetAfter(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		process   = makeBlockingProcess().(*mockProcess)
+		startChan = make(chan struct{}, 5)
+		errChan   = make(chan error)
+		starts    = 0
+	)
+
+	process.start = func() error {
+		startChan <- struct{}{}
+
+		// The third run is stable, which resets the restart count
+		if starts++; starts == 3 {
+			<-time.After(time.Millisecond * 50)
+		}
+
+		return errors.New("utoh")
+	}
+
+	runner.RegisterProcess(process, WithProcessName("flaky"), WithRestart(RestartPolicy{
+		Condition:   RestartOnFailure,
+		MaxAttempts: 2,
+		Backoff:     time.Millisecond,
+		ResetAfter:  time.Millisecond * 20,
+	}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("flaky returned a fatal error (utoh)")))
+	Eventually(errChan).Should(BeClosed())
+	Expect(startChan).To(HaveLen(5))
+}
+
+func (s *RunnerSuite) TestRestartFailureLogging(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer(), WithFailureLogInterval(time.Hour))
+		logger  = &warningLogger{Logger: log.NewNilLogger(), messages: make(chan string, 10)}
+		process = makeBlockingProcess().(*mockProcess)
+		block   = process.start
+		errChan = make(chan error)
+		starts  = 0
+	)
+
+	process.start = func() error {
+		if starts++; starts <= 5 {
+			return errors.New("utoh")
+		}
+
+		return block()
+	}
+
+	runner.RegisterProcess(process, WithProcessName("flaky"), WithRestart(RestartPolicy{
+		Condition: RestartOnFailure,
+		Backoff:   time.Millisecond,
+	}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	// Only the first of the identical failures is logged
+	Eventually(logger.messages).Should(Receive(Equal("flaky failed, restarting in 1ms (flaky returned a fatal error (utoh))")))
+	Consistently(logger.messages).ShouldNot(Receive())
+
+	// Suppressed failures are summarized on success
+	process.Stop()
+	Eventually(logger.messages).Should(Receive(Equal("flaky failed 4 more times (flaky returned a fatal error (utoh))")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestCrashLoopDegrade(t sweet.T) {
+	var (
+		events  = make(chan CrashLoopEvent, 1)
+		runner  = NewProcessRunner(NewServiceContainer(), WithCrashLoopHook(func(event CrashLoopEvent) { events <- event }))
+		process = makeBlockingProcess().(*mockProcess)
+		other   = makeBlockingProcess()
+		errChan = make(chan error)
+	)
+
+	process.start = func() error { return errors.New("utoh") }
+
+	runner.RegisterProcess(other)
+	runner.RegisterProcess(
+		process,
+		WithProcessName("flaky"),
+		WithRestart(RestartPolicy{Condition: RestartOnFailure, Backoff: time.Millisecond}),
+		WithCrashLoopPolicy(CrashLoopPolicy{Threshold: 2, Window: time.Hour, Escalation: CrashLoopDegrade}),
+	)
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	var event CrashLoopEvent
+	Eventually(events).Should(Receive(&event))
+	Expect(event.Process).To(Equal("flaky"))
+	Expect(event.Restarts).To(Equal(3))
+	Expect(event.Escalation).To(Equal(CrashLoopDegrade))
+	Expect(event.Err).To(MatchError("flaky returned a fatal error (utoh)"))
+
+	// Remaining processes keep running
+	Eventually(runner.HealthCheck).Should(MatchError("processes in crash loop (flaky)"))
+	Consistently(errChan).ShouldNot(Receive())
+
+	other.Stop()
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestCrashLoopShutdown(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		process = makeBlockingProcess().(*mockProcess)
+		errChan = make(chan error)
+	)
+
+	process.start = func() error { return errors.New("utoh") }
+
+	runner.RegisterProcess(
+		process,
+		WithProcessName("flaky"),
+		WithRestart(RestartPolicy{Condition: RestartOnFailure, Backoff: time.Millisecond}),
+		WithCrashLoopPolicy(CrashLoopPolicy{Threshold: 2, Window: time.Hour}),
+	)
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("flaky is in a crash loop (3 restarts within 1h0m0s)")))
+	Eventually(errChan).Should(BeClosed())
+	Expect(runner.HealthCheck()).To(BeNil())
+}
+
+func (s *RunnerSuite) TestRestartPolicyDelay(t sweet.T) {
+	policy := RestartPolicy{Backoff: time.Second, MaxBackoff: time.Second * 5}
+	Expect(policy.delay(0)).To(Equal(time.Second))
+	Expect(policy.delay(1)).To(Equal(time.Second * 2))
+	Expect(policy.delay(2)).To(Equal(time.Second * 4))
+	Expect(policy.delay(3)).To(Equal(time.Second * 5))
+	Expect(policy.delay(100)).To(Equal(time.Second * 5))
+}
+
+func (s *RunnerSuite) TestStatus(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		health    = NewHealth(container)
+		runner    = NewProcessRunner(container)
+		process   = makeBlockingProcess()
+		errChan   = make(chan error)
+	)
+
+	container.Set("health", health)
+	runner.RegisterProcess(process)
+	Expect(runner.Status()).To(Equal(StatusStarting))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.Status).Should(Equal(StatusHealthy))
+
+	health.AddReason("consumer")
+	Expect(runner.Status()).To(Equal(StatusUnhealthy))
+	health.RemoveReason("consumer")
+	Expect(runner.Status()).To(Equal(StatusHealthy))
+
+	process.Stop()
+	Eventually(errChan).Should(BeClosed())
+	Expect(runner.Status()).To(Equal(StatusUnhealthy))
+}
+
+func (s *RunnerSuite) TestInitializerTimeout(t sweet.T) {
+	var (
+		runner      = NewProcessRunner(NewServiceContainer())
+		initializer = &stalledInitializer{block: make(chan struct{})}
+		errChan     = make(chan error)
+	)
+
+	defer close(initializer.block)
+	runner.RegisterInitializer(initializer, WithInitializerName("db"), WithInitializerTimeout(time.Millisecond*20))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("failed to initialize db (init method did not finish within 20ms (stalled at step 1/2, connecting))")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRollingRestart(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		initChan  = make(chan string, 10)
+		startChan = make(chan string, 10)
+		errChan   = make(chan error)
+	)
+
+	makeProcess := func(name string) Process {
+		p := &mockProcess{}
+
+		var (
+			c     chan struct{}
+			o     *sync.Once
+			mutex sync.Mutex
+		)
+
+		p.init = func(config Config) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			c = make(chan struct{})
+			o = &sync.Once{}
+			initChan <- name
+			return nil
+		}
+
+		p.start = func() error {
+			mutex.Lock()
+			ch := c
+			mutex.Unlock()
+
+			startChan <- name
+			<-ch
+			return nil
+		}
+
+		p.stop = func() error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			o.Do(func() { close(c) })
+			return nil
+		}
+
+		return p
+	}
+
+	var (
+		proc1 = makeProcess("proc1")
+		proc2 = makeProcess("proc2")
+		proc3 = makeProcess("proc3")
+		proc4 = makeProcess("proc4")
+	)
+
+	runner.RegisterProcess(proc1, WithProcessLabels("consumer"))
+	runner.RegisterProcess(proc2, WithProcessLabels("consumer"))
+	runner.RegisterProcess(proc3, WithProcessLabels("consumer"))
+	runner.RegisterProcess(proc4)
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	for i := 0; i < 4; i++ {
+		Eventually(startChan).Should(Receive())
+		Eventually(initChan).Should(Receive())
+	}
+
+	Expect(runner.RollingRestart("consumer", 2, 0)).To(BeNil())
+
+	for _, name := range []string{"proc1", "proc2", "proc3"} {
+		Eventually(initChan).Should(Receive(Equal(name)))
+	}
+
+	for i := 0; i < 3; i++ {
+		Eventually(startChan).Should(Receive())
+	}
+
+	Consistently(errChan).ShouldNot(BeClosed())
+	Expect(runner.RollingRestart("producer", 2, 0)).To(MatchError("no processes registered with label `producer`"))
+
+	proc4.Stop()
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRollingRestartNotRunning(t sweet.T) {
+	runner := NewProcessRunner(NewServiceContainer())
+	Expect(runner.RollingRestart("consumer", 1, 0)).To(Equal(ErrRunnerNotRunning))
+}
+
+func (s *RunnerSuite) TestReplicas(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		startChan = make(chan int, 3)
+		errChan   = make(chan error)
+		processes = []*replicaProcess{}
+	)
+
+	factory := func() Process {
+		p := &replicaProcess{
+			startChan: startChan,
+			halt:      make(chan struct{}),
+			once:      &sync.Once{},
+		}
+
+		processes = append(processes, p)
+		return p
+	}
+
+	runner.RegisterProcessFactory(factory, WithProcessName("consumer"), WithReplicas(3))
+	Expect(processes).To(HaveLen(3))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	indices := []int{}
+	for i := 0; i < 3; i++ {
+		var index int
+		Eventually(startChan).Should(Receive(&index))
+		indices = append(indices, index)
+	}
+
+	Expect(indices).To(ConsistOf(0, 1, 2))
+
+	for _, p := range processes {
+		Expect(p.Replica.Count()).To(Equal(3))
+	}
+
+	// Stopping one replica stops all
+	processes[1].Stop()
+	Eventually(errChan).Should(BeClosed())
+
+	for _, p := range processes {
+		Eventually(p.halt).Should(BeClosed())
+	}
+}
+
+func (s *RunnerSuite) TestTenantProcesses(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		created = []string{}
+		tenants = []testTenant{
+			{Name: "orders", Topic: "orders-v1"},
+			{Name: "users", Topic: "users-v2"},
+		}
+	)
+
+	factory := func(tenant Tenant) (Process, error) {
+		created = append(created, tenant.(*testTenant).Topic)
+		return makeBlockingProcess(), nil
+	}
+
+	Expect(runner.RegisterTenantProcesses("consumer", tenants, factory, WithPriority(2))).To(BeNil())
+	Expect(created).To(Equal([]string{"orders-v1", "users-v2"}))
+	Expect(runner.processes[2]).To(HaveLen(2))
+	Expect(runner.processes[2][0].Name()).To(Equal("consumer[orders]"))
+	Expect(runner.processes[2][1].Name()).To(Equal("consumer[users]"))
+}
+
+func (s *RunnerSuite) TestTenantProcessesInvalid(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		factory = func(tenant Tenant) (Process, error) { return makeBlockingProcess(), nil }
+	)
+
+	Expect(runner.RegisterTenantProcesses("consumer", "orders", factory)).To(MatchError("tenant list must be a slice, got string"))
+	Expect(runner.RegisterTenantProcesses("consumer", []string{"orders"}, factory)).To(MatchError("tenant at index 0 does not implement nacelle.Tenant (string)"))
+
+	err := runner.RegisterTenantProcesses("consumer", []testTenant{{Name: "a"}, {Name: "b"}, {Name: "a"}}, factory)
+	Expect(err).To(MatchError("duplicate tenant name `a`"))
+	Expect(runner.numProcesses).To(Equal(0))
+
+	err = runner.RegisterTenantProcesses("consumer", []testTenant{{Name: "a"}}, func(tenant Tenant) (Process, error) {
+		return nil, errors.New("utoh")
+	})
+
+	Expect(err).To(MatchError("failed to create process for tenant a (utoh)"))
+	Expect(runner.numProcesses).To(Equal(0))
+}
+
+func (s *RunnerSuite) TestScale(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		startChan = make(chan int, 5)
+		errChan   = make(chan error)
+		processes = []*replicaProcess{}
+		mutex     sync.Mutex
+		events    = make(chan string, 10)
+	)
+
+	factory := func() Process {
+		p := &replicaProcess{
+			startChan: startChan,
+			halt:      make(chan struct{}),
+			once:      &sync.Once{},
+		}
+
+		mutex.Lock()
+		processes = append(processes, p)
+		mutex.Unlock()
+		return p
+	}
+
+	runner.Subscribe(func(event LifecycleEvent) {
+		if event.Type == EventProcessRegistered {
+			events <- event.Name
+		}
+	})
+
+	runner.RegisterProcessFactory(factory, WithProcessName("consumer"), WithReplicas(2))
+	runner.RegisterProcess(makeBlockingProcess())
+	Expect(events).To(HaveLen(3))
+
+	Expect(runner.Scale("consumer", 3)).To(Equal(ErrRunnerNotRunning))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(startChan).Should(Receive())
+	Eventually(startChan).Should(Receive())
+	Eventually(runner.isRunning).Should(BeTrue())
+
+	// Scale up
+	Expect(runner.Scale("consumer", 4)).To(BeNil())
+	Eventually(startChan).Should(Receive(Equal(2)))
+	Eventually(startChan).Should(Receive(Equal(3)))
+	Expect(events).To(HaveLen(5))
+	Expect(processes[0].Replica.Count()).To(Equal(4))
+
+	// Scale down
+	Expect(runner.Scale("consumer", 1)).To(BeNil())
+	Expect(processes[3].halt).To(BeClosed())
+	Expect(processes[2].halt).To(BeClosed())
+	Expect(processes[1].halt).To(BeClosed())
+	Expect(processes[0].halt).NotTo(BeClosed())
+	Expect(processes[0].Replica.Count()).To(Equal(1))
+	Consistently(errChan).ShouldNot(BeClosed())
+
+	Expect(runner.Scale("producer", 1)).To(MatchError("no process factory registered with name `producer`"))
+
+	// Stopping the remaining replica stops all
+	processes[0].Stop()
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestUnnamedProcessFactories(t sweet.T) {
+	runner := NewProcessRunner(NewServiceContainer())
+	runner.RegisterProcessFactory(makeBlockingProcess)
+	runner.RegisterProcessFactory(makeBlockingProcess, WithReplicas(2))
+
+	// Unnamed factories do not replace each other
+	Expect(runner.replicaSets).To(HaveLen(2))
+	Expect(runner.numProcesses).To(Equal(3))
+}
+
+func (s *RunnerSuite) TestReadyNotifier(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer())
+		ready    = &readyProcess{Process: makeBlockingProcess(), ready: make(chan struct{})}
+		initChan = make(chan string, 1)
+		errChan  = make(chan error)
+	)
+
+	next := makeBlockingProcess().(*mockProcess)
+	next.init = func(config Config) error { initChan <- "next"; return nil }
+
+	runner.RegisterProcess(ready, WithPriority(1))
+	runner.RegisterProcess(next, WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	// Next priority waits for ready notification
+	Consistently(initChan).ShouldNot(Receive())
+	close(ready.ready)
+	Eventually(initChan).Should(Receive(Equal("next")))
+	Eventually(runner.isRunning).Should(BeTrue())
+
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestReadyTimeout(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		ready   = &readyProcess{Process: makeBlockingProcess(), ready: make(chan struct{})}
+		errChan = make(chan error)
+	)
+
+	runner.RegisterProcess(ready, WithProcessName("warm"), WithPriority(1), WithReadyTimeout(time.Millisecond*20))
+	runner.RegisterProcess(makeBlockingProcess(), WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("warm did not become ready within 20ms")))
+	Eventually(errChan).Should(BeClosed())
+	Expect(runner.isRunning()).To(BeFalse())
+}
+
+func (s *RunnerSuite) TestFinalize(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		finalized = make(chan string, 4)
+		errChan   = make(chan error)
+	)
+
+	makeFinalizer := func(name string, err error) *finalizerProcess {
+		return &finalizerProcess{
+			Process: makeBlockingProcess(),
+			finalize: func() error {
+				finalized <- name
+				return err
+			},
+		}
+	}
+
+	runner.RegisterInitializer(makeFinalizer("init1", nil), WithInitializerName("init1"))
+	runner.RegisterInitializer(makeFinalizer("init2", nil), WithInitializerName("init2"))
+	runner.RegisterProcess(makeFinalizer("proc1", nil), WithProcessName("proc1"), WithPriority(1))
+	runner.RegisterProcess(makeFinalizer("proc2", errors.New("utoh")), WithProcessName("proc2"), WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(finalized).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+
+	// Finalized in reverse order once all processes stop
+	Eventually(finalized).Should(Receive(Equal("proc2")))
+	Eventually(finalized).Should(Receive(Equal("proc1")))
+	Eventually(finalized).Should(Receive(Equal("init2")))
+	Eventually(finalized).Should(Receive(Equal("init1")))
+}
+
+func (s *RunnerSuite) TestCloseServices(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		runner    = NewProcessRunner(container)
+		steps     = make(chan string, 4)
+		errChan   = make(chan error)
+	)
+
+	container.MustSet("pool", &closerService{close: func() error { steps <- "closed"; return nil }})
+
+	runner.RegisterProcess(&finalizerProcess{
+		Process:  makeBlockingProcess(),
+		finalize: func() error { steps <- "finalized"; return nil },
+	})
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(steps).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	// Services are closed once processes have been finalized
+	Expect(steps).To(Receive(Equal("finalized")))
+	Expect(steps).To(Receive(Equal("closed")))
+}
+
+func (s *RunnerSuite) TestFinalizeInitFailure(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		finalized = make(chan string, 2)
+		errChan   = make(chan error)
+	)
+
+	failing := makeBlockingProcess().(*mockProcess)
+	failing.init = func(config Config) error { return errors.New("utoh") }
+
+	runner.RegisterProcess(&finalizerProcess{
+		Process:  makeBlockingProcess(),
+		finalize: func() error { finalized <- "proc1"; return nil },
+	}, WithPriority(1))
+
+	runner.RegisterProcess(&finalizerProcess{
+		Process:  failing,
+		finalize: func() error { finalized <- "proc2"; return nil },
+	}, WithProcessName("proc2"), WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("failed to initialize proc2 (utoh)")))
+	Eventually(finalized).Should(Receive(Equal("proc1")))
+	Consistently(finalized).ShouldNot(Receive())
+}
+
+func (s *RunnerSuite) TestRollbackInitFailure(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		steps   = make(chan string, 10)
+		errChan = make(chan error)
+	)
+
+	makeRollbacker := func(name string, initErr, rollbackErr error) *rollbackInitializer {
+		return &rollbackInitializer{
+			Initializer: InitializerFunc(func(config Config) error { return initErr }),
+			rollback: func() error {
+				steps <- "rollback " + name
+				return rollbackErr
+			},
+		}
+	}
+
+	runner.RegisterInitializer(makeRollbacker("init1", nil, nil), WithInitializerName("init1"))
+	runner.RegisterInitializer(makeRollbacker("init2", nil, errors.New("oops")), WithInitializerName("init2"))
+	runner.RegisterInitializer(InitializerFunc(func(config Config) error { return nil }), WithInitializerName("init3"))
+	runner.RegisterInitializer(makeRollbacker("init4", errors.New("utoh"), nil), WithInitializerName("init4"))
+	runner.RegisterInitializer(makeRollbacker("init5", nil, nil), WithInitializerName("init5"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("failed to initialize init4 (utoh)")))
+	Eventually(errChan).Should(Receive(MatchError("init2 returned error from rollback (oops)")))
+	Eventually(errChan).Should(BeClosed())
+
+	// Rolled back in reverse order, skipping the failed initializer
+	Expect(steps).To(Receive(Equal("rollback init2")))
+	Expect(steps).To(Receive(Equal("rollback init1")))
+	Expect(steps).NotTo(Receive())
+}
+
+func (s *RunnerSuite) TestRollbackInjectionFailure(t sweet.T) {
+	var (
+		runner = NewProcessRunner(NewServiceContainer())
+		steps  = make(chan string, 10)
+	)
+
+	runner.RegisterInitializer(&rollbackInitializer{
+		Initializer: InitializerFunc(func(config Config) error { return nil }),
+		rollback:    func() error { steps <- "rollback"; return nil },
+	}, WithInitializerName("init"))
+
+	runner.RegisterProcess(&injectedProcess{Process: makeBlockingProcess()}, WithProcessName("proc"))
+
+	var errs []error
+	for err := range runner.Run(nil, log.NewNilLogger()) {
+		errs = append(errs, err)
+	}
+
+	// Initializers are rolled back when a process cannot be injected
+	Expect(errs).To(HaveLen(1))
+	Expect(errs[0]).To(BeAssignableToTypeOf(&StartupReport{}))
+	Expect(steps).To(Receive(Equal("rollback")))
+	Expect(steps).NotTo(Receive())
+}
+
+func (s *RunnerSuite) TestWatchdogInit(t sweet.T) {
+	var (
+		logger      = &errorLogger{Logger: log.NewNilLogger(), messages: make(chan string, 10)}
+		runner      = NewProcessRunner(NewServiceContainer(), WithWatchdog(time.Millisecond*20))
+		initializer = &stalledInitializer{block: make(chan struct{})}
+		errChan     = make(chan error)
+	)
+
+	runner.RegisterInitializer(initializer, WithInitializerName("slow"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(logger.messages).Should(Receive(Equal("Runner has made no progress for 20ms while initializing slow")))
+	Consistently(logger.messages).ShouldNot(Receive())
+
+	close(initializer.block)
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestWatchdogShutdown(t sweet.T) {
+	var (
+		logger  = &errorLogger{Logger: log.NewNilLogger(), messages: make(chan string, 10)}
+		runner  = NewProcessRunner(NewServiceContainer(), WithWatchdog(time.Millisecond*20))
+		stuck   = makeBlockingProcess().(*mockProcess)
+		release = make(chan struct{})
+		errChan = make(chan error)
+	)
+
+	stuck.start = func() error { <-release; return nil }
+	stuck.stop = func() error { return nil }
+	runner.RegisterProcess(stuck, WithProcessName("stuck"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(logger.messages).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Millisecond * 50)).NotTo(BeNil())
+	Eventually(logger.messages).Should(Receive(Equal("Runner has made no progress for 20ms while waiting for processes to exit (waiting on stuck)")))
+
+	close(release)
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestSignalOptions(t sweet.T) {
+	Expect(NewProcessRunner(NewServiceContainer()).signals).To(Equal(shutdownSignals))
+	Expect(NewProcessRunner(NewServiceContainer(), WithSignals(os.Interrupt)).signals).To(Equal([]os.Signal{os.Interrupt}))
+	Expect(NewProcessRunner(NewServiceContainer(), WithoutSignals()).signals).To(BeEmpty())
+}
+
+func (s *RunnerSuite) TestWithoutSignalsShutdown(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer(), WithoutSignals())
+		errChan = make(chan error)
+	)
+
+	runner.RegisterProcess(makeBlockingProcess())
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestDrain(t sweet.T) {
+	var (
+		runner    = NewProcessRunner(NewServiceContainer())
+		drained   = make(chan string, 2)
+		release   = make(chan struct{})
+		stopped   = make(chan string, 2)
+		errChan   = make(chan error)
+		shutdown  = make(chan error)
+		processes = []*drainerProcess{}
+	)
+
+	for _, name := range []string{"a", "b"} {
+		name := name
+		p := makeBlockingProcess().(*mockProcess)
+		stop := p.stop
+		p.stop = func() error { stopped <- name; return stop() }
+
+		processes = append(processes, &drainerProcess{
+			Process: p,
+			drain: func() error {
+				drained <- name
+				<-release
+				return nil
+			},
+		})
+	}
+
+	runner.RegisterProcess(processes[0], WithPriority(1))
+	runner.RegisterProcess(processes[1], WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	go func() { shutdown <- runner.Shutdown(time.Second) }()
+
+	// All processes drain before any stops
+	Eventually(drained).Should(Receive())
+	Eventually(drained).Should(Receive())
+	Consistently(stopped).ShouldNot(Receive())
+
+	close(release)
+	Eventually(stopped).Should(Receive(Equal("b")))
+	Eventually(stopped).Should(Receive(Equal("a")))
+	Eventually(shutdown).Should(Receive(BeNil()))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestShutdownAware(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		runner    = NewProcessRunner(container)
+		steps     = make(chan string, 10)
+		errChan   = make(chan error)
+	)
+
+	pool := &shutdownAwareService{notify: func() { steps <- "pool" }}
+	container.MustSet("pool", pool)
+	container.MustSet("pool-alias", pool)
+	container.MustSet("cache", &shutdownAwareService{notify: func() { steps <- "cache" }})
+
+	// Services registered via SetFactory are notified only once constructed
+	container.MustSetFactory("queue", func(c *ServiceContainer) (interface{}, error) {
+		return &shutdownAwareService{notify: func() { steps <- "queue" }}, nil
+	})
+
+	container.MustSetFactory("unused", func(c *ServiceContainer) (interface{}, error) {
+		return &shutdownAwareService{notify: func() { steps <- "unused" }}, nil
+	})
+
+	_, err := container.Get("queue")
+	Expect(err).To(BeNil())
+
+	runner.RegisterProcess(&drainerProcess{
+		Process: makeBlockingProcess(),
+		drain:   func() error { steps <- "drain"; return nil },
+	})
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(steps).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	// Services are notified once each, ordered by key, before draining
+	Expect(steps).To(Receive(Equal("cache")))
+	Expect(steps).To(Receive(Equal("pool")))
+	Expect(steps).To(Receive(Equal("queue")))
+	Expect(steps).To(Receive(Equal("drain")))
+	Expect(steps).NotTo(Receive())
+}
+
+func (s *RunnerSuite) TestOperationGracePeriod(t sweet.T) {
+	var (
+		logger     = &warningLogger{Logger: log.NewNilLogger(), messages: make(chan string, 10)}
+		container  = NewServiceContainer()
+		operations = NewOperations()
+		runner     = NewProcessRunner(container, WithOperationGracePeriod(time.Millisecond*20))
+		cancelled  = make(chan struct{})
+		errChan    = make(chan error)
+	)
+
+	container.MustSet("operations", operations)
+	done := operations.Begin("export", func() { close(cancelled) })
+
+	runner.RegisterProcess(&drainerProcess{
+		Process: makeBlockingProcess(),
+		drain:   func() error { <-cancelled; done(); return nil },
+	})
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(logger.messages).Should(Receive(HavePrefix("Cancelling operations still outstanding after 20ms (export running for")))
+	Eventually(errChan).Should(BeClosed())
+	Expect(operations.Outstanding()).To(BeEmpty())
+}
+
+func (s *RunnerSuite) TestDrainTimeout(t sweet.T) {
+	var (
+		logger  = &warningLogger{Logger: log.NewNilLogger(), messages: make(chan string, 10)}
+		runner  = NewProcessRunner(NewServiceContainer(), WithDrainTimeout(time.Millisecond*20))
+		block   = make(chan struct{})
+		errChan = make(chan error)
+	)
+
+	defer close(block)
+
+	runner.RegisterProcess(&drainerProcess{
+		Process: makeBlockingProcess(),
+		drain:   func() error { <-block; return nil },
+	}, WithProcessName("stuck"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(logger.messages).Should(Receive(Equal("Processes did not finish draining within 20ms (stuck)")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestProcessHandles(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer())
+		consumer = makeBlockingProcess().(*mockProcess)
+		debug    = &handleProcess{Process: makeBlockingProcess()}
+		errChan  = make(chan error)
+	)
+
+	runner.RegisterProcess(debug, WithProcessName("debug"), WithPriority(2))
+	runner.RegisterProcess(consumer, WithProcessName("consumer"), WithPriority(1))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(debug.Consumer).To(BeIdenticalTo(consumer))
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestProcessHandlesUninitialized(t sweet.T) {
+	runner := NewProcessRunner(NewServiceContainer())
+	runner.RegisterProcess(&handleProcess{Process: makeBlockingProcess()}, WithProcessName("debug"), WithPriority(1))
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("consumer"), WithPriority(2))
+
+	Expect(runner.RunAndWait(nil, log.NewNilLogger())).To(MatchError(
+		"failed to inject process handles into debug (process `consumer` has not been initialized)",
+	))
+}
+
+func (s *RunnerSuite) TestDrainProgress(t sweet.T) {
+	var (
+		inFlight = int32(10)
+		reports  = make(chan []DrainProgress, 100)
+		release  = make(chan struct{})
+		errChan  = make(chan error)
+		runner   = NewProcessRunner(
+			NewServiceContainer(),
+			WithProgressInterval(time.Millisecond*10),
+			WithDrainReporter(func(progress []DrainProgress) { reports <- progress }),
+		)
+	)
+
+	runner.RegisterProcess(&inFlightProcess{
+		drainerProcess: drainerProcess{
+			Process: makeBlockingProcess(),
+			drain:   func() error { <-release; return nil },
+		},
+		inFlight: func() int { return int(atomic.LoadInt32(&inFlight)) },
+	}, WithProcessName("server"))
+
+	runner.RegisterProcess(&drainerProcess{
+		Process: makeBlockingProcess(),
+		drain:   func() error { <-release; return nil },
+	}, WithProcessName("consumer"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	go runner.Shutdown(time.Second)
+
+	var progress []DrainProgress
+	Eventually(reports).Should(Receive(&progress))
+	Expect(progress).To(HaveLen(2))
+	Expect(progress[0].Name).To(Equal("consumer"))
+	Expect(progress[0].InFlight).To(Equal(-1))
+	Expect(progress[1].Name).To(Equal("server"))
+	Expect(progress[1].InFlight).To(Equal(10))
+	Expect(progress[1].Initial).To(Equal(10))
+	Expect(progress[1].Estimate).To(BeZero())
+
+	atomic.StoreInt32(&inFlight, 5)
+
+	Eventually(func() int {
+		Eventually(reports).Should(Receive(&progress))
+		return progress[1].InFlight
+	}).Should(Equal(5))
+
+	Expect(progress[1].Estimate).To(BeNumerically(">", 0))
+
+	close(release)
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestSyncLogsOnShutdown(t sweet.T) {
+	var (
+		events  = make(chan string, 2)
+		logger  = &syncingLogger{Logger: log.NewNilLogger(), sync: func() error { events <- "synced"; return nil }}
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	runner.RegisterProcess(&finalizerProcess{
+		Process:  makeBlockingProcess(),
+		finalize: func() error { events <- "finalized"; return nil },
+	})
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(events).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+
+	// Logs are synced as the final step of shutdown
+	Eventually(events).Should(Receive(Equal("finalized")))
+	Eventually(events).Should(Receive(Equal("synced")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestSyncLogsTimeout(t sweet.T) {
+	var (
+		block   = make(chan struct{})
+		logger  = &syncingLogger{Logger: log.NewNilLogger(), sync: func() error { <-block; return nil }}
+		runner  = NewProcessRunner(NewServiceContainer(), WithLogSyncTimeout(time.Millisecond*20))
+		errChan = make(chan error)
+	)
+
+	defer close(block)
+
+	failing := makeBlockingProcess().(*mockProcess)
+	failing.init = func(config Config) error { return errors.New("utoh") }
+	runner.RegisterProcess(failing, WithProcessName("failing"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, logger) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("failed to initialize failing (utoh)")))
+	Eventually(errChan).Should(Receive(MatchError("logger did not finish syncing within 20ms")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestLifecycleEvents(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		events  = make(chan LifecycleEvent, 20)
+		errChan = make(chan error)
+	)
+
+	unsubscribe := runner.Subscribe(func(event LifecycleEvent) { events <- event })
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("proc"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+	unsubscribe()
+
+	types := []LifecycleEventType{}
+	for len(events) > 0 {
+		event := <-events
+		Expect(event.Name).To(Equal("proc"))
+		Expect(event.Err).To(BeNil())
+		types = append(types, event.Type)
+	}
+
+	Expect(types).To(Equal([]LifecycleEventType{
+		EventProcessRegistered,
+		EventInitStarted,
+		EventInitCompleted,
+		EventStartCalled,
+		EventStopping,
+		EventStopped,
+	}))
+}
+
+func (s *RunnerSuite) TestStrictServices(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		runner    = NewProcessRunner(container, WithStrictServices())
+		errChan   = make(chan error)
+	)
+
+	container.MustSet("used", &IntWrapper{1})
+	container.MustSet("unused", &IntWrapper{2})
+	container.MustGet("used")
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("proc"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("services registered but never retrieved: unused")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestStrictServicesAllUsed(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		runner    = NewProcessRunner(container, WithStrictServices())
+		errChan   = make(chan error)
+	)
+
+	container.MustSet("used", &IntWrapper{1})
+	container.MustGet("used")
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("proc"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(errChan).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRelayErrorsOverflow(t sweet.T) {
+	runner := NewProcessRunner(NewServiceContainer(), WithErrorBufferSize(1))
+	runner.logger = log.NewNilLogger()
+
+	src := make(chan error, 3)
+	src <- errors.New("a")
+	src <- errors.New("b")
+	src <- errors.New("c")
+	close(src)
+
+	out := runner.relayErrors(src)
+
+	Eventually(func() uint64 {
+		stats := runner.DeliveryStats()
+		return stats.ErrorsDelivered + stats.ErrorsDropped
+	}).Should(Equal(uint64(3)))
+
+	Expect(runner.DeliveryStats()).To(Equal(DeliveryStats{ErrorsDelivered: 1, ErrorsDropped: 2}))
+	Eventually(out).Should(Receive(MatchError("a")))
+	Eventually(out).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestStalledErrorReader(t sweet.T) {
+	runner := NewProcessRunner(NewServiceContainer(), WithErrorBufferSize(1))
+
+	for _, name := range []string{"a", "b", "c"} {
+		var (
+			process = &mockProcess{}
+			stopped = make(chan struct{})
+			once    = &sync.Once{}
+		)
+
+		process.init = func(config Config) error { return nil }
+		process.start = func() error { <-stopped; return errors.New("utoh") }
+		process.stop = func() error { once.Do(func() { close(stopped) }); return nil }
+		runner.RegisterProcess(process, WithProcessName(name))
+	}
+
+	// The error channel is never read
+	runner.Run(nil, log.NewNilLogger())
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(func() uint64 { return runner.DeliveryStats().ErrorsDropped }).Should(Equal(uint64(2)))
+}
+
+func (s *RunnerSuite) TestSubscribeBuffered(t sweet.T) {
+	var (
+		runner = NewProcessRunner(NewServiceContainer())
+		block  = make(chan struct{})
+		events = make(chan LifecycleEvent, 3)
+	)
+
+	unsubscribe := runner.SubscribeBuffered(func(event LifecycleEvent) {
+		<-block
+		events <- event
+	}, 1)
+
+	runner.RecordReload("a")
+	runner.RecordReload("b")
+	runner.RecordReload("c")
+
+	stats := runner.DeliveryStats()
+	Expect(stats.EventsDelivered + stats.EventsDropped).To(Equal(uint64(3)))
+	Expect(stats.EventsDropped).To(BeNumerically(">=", 1))
+
+	close(block)
+
+	var event LifecycleEvent
+	Eventually(events).Should(Receive(&event))
+	Expect(event.Name).To(Equal("a"))
+	unsubscribe()
+
+	Expect(stats.Fields()).To(HaveKey("events_dropped"))
+}
+
+func (s *RunnerSuite) TestLifecycleEventsErrored(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		events  = make(chan LifecycleEvent, 20)
+		errChan = make(chan error)
+	)
+
+	failing := makeBlockingProcess().(*mockProcess)
+	failing.init = func(config Config) error { return errors.New("utoh") }
+	runner.RegisterProcess(failing, WithProcessName("proc"))
+
+	runner.Subscribe(func(event LifecycleEvent) {
+		if event.Type == EventErrored {
+			events <- event
+		}
+	})
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	var event LifecycleEvent
+	Eventually(events).Should(Receive(&event))
+	Expect(event.Name).To(Equal("proc"))
+	Expect(event.Err).To(MatchError("utoh"))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRegisterFromSpec(t sweet.T) {
+	initialized := make(chan string, 4)
+
+	runner := NewProcessRunner(
+		NewServiceContainer(),
+		WithInitializerType("schema", func() Initializer {
+			return InitializerFunc(func(config Config) error { initialized <- "schema"; return nil })
+		}),
+		WithProcessType("server", makeBlockingProcess),
+		WithProcessType("consumer", makeBlockingProcess),
+	)
+
+	spec := Spec{}
+	Expect(json.Unmarshal([]byte(`{
+		"initializers": [
+			{"name": "schema", "type": "schema"}
+		],
+		"processes": [
+			{"name": "api", "type": "server", "priority": 2, "labels": ["frontend"], "tags": {"team": "web"}},
+			{"name": "consumer", "type": "consumer", "priority": 1, "replicas": 2, "config_prefix": "orders"}
+		]
+	}`), &spec)).To(BeNil())
+
+	spec.Initializers = append(spec.Initializers, InitializerSpec{
+		Name:        "cache",
+		Initializer: InitializerFunc(func(config Config) error { initialized <- "cache"; return nil }),
+	})
+
+	Expect(runner.RegisterFromSpec(spec)).To(BeNil())
+
+	Expect(runner.Processes()).To(Equal([]ProcessInfo{
+		{Name: "consumer[0]", Priority: 1, Labels: []string{}, Tags: map[string]string{}},
+		{Name: "consumer[1]", Priority: 1, Labels: []string{}, Tags: map[string]string{}},
+		{Name: "api", Priority: 2, Labels: []string{"frontend"}, Tags: map[string]string{"team": "web"}},
+	}))
+
+	Expect(runner.processes[1][0].configPrefix).To(Equal("orders"))
+
+	errChan := make(chan error)
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(initialized).Should(Receive(Equal("schema")))
+	Eventually(initialized).Should(Receive(Equal("cache")))
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRegisterFromSpecScale(t sweet.T) {
+	var (
+		startChan = make(chan int, 5)
+		errChan   = make(chan error)
+	)
+
+	factory := func() Process {
+		return &replicaProcess{
+			startChan: startChan,
+			halt:      make(chan struct{}),
+			once:      &sync.Once{},
+		}
+	}
+
+	runner := NewProcessRunner(NewServiceContainer(), WithProcessType("consumer", factory))
+
+	Expect(runner.RegisterFromSpec(Spec{
+		Processes: []ProcessSpec{
+			{Name: "consumer", Type: "consumer", Replicas: 1},
+		},
+	})).To(BeNil())
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(startChan).Should(Receive(Equal(0)))
+	Eventually(runner.isRunning).Should(BeTrue())
+
+	// A process which starts with a single replica can be scaled up
+	Expect(runner.Scale("consumer", 3)).To(BeNil())
+	Eventually(startChan).Should(Receive(Equal(1)))
+	Eventually(startChan).Should(Receive(Equal(2)))
+
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRegisterFromSpecInvalid(t sweet.T) {
+	runner := NewProcessRunner(NewServiceContainer(), WithProcessType("server", makeBlockingProcess))
+
+	err := runner.RegisterFromSpec(Spec{
+		Initializers: []InitializerSpec{
+			{Name: "schema", Type: "schema"},
+		},
+		Processes: []ProcessSpec{
+			{Name: "api", Type: "server"},
+			{Name: "api", Type: "server", Replicas: 2},
+			{Name: "migrate", Factory: makeBlockingProcess, Type: "server"},
+			{Name: "consumer"},
+			{Name: "worker", Type: "server", Replicas: 1},
+			{Name: "worker", Type: "server"},
+		},
+	})
+
+	Expect(err).To(MatchError("invalid spec (" +
+		"initializer 0 (schema) has unknown type `schema`, " +
+		"process 1 (api) has replicas but its name is not unique, " +
+		"process 2 (migrate) has both a type and a factory, " +
+		"process 3 (consumer) has no type, " +
+		"process 4 (worker) has replicas but its name is not unique)",
+	))
+
+	Expect(runner.Processes()).To(BeEmpty())
+}
+
+func (s *RunnerSuite) TestJobs(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		stopped = make(chan string, 4)
+		errChan = make(chan error)
+		block   = make(chan struct{})
+	)
+
+	makeJob := func(name string, err error) Process {
+		return &mockProcess{
+			init:  func(config Config) error { return nil },
+			start: func() error { <-block; return err },
+			stop:  func() error { stopped <- name; return nil },
+		}
+	}
+
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("server"))
+	runner.RegisterJob(makeJob("migrate", nil), WithProcessName("migrate"))
+	runner.RegisterJob(makeJob("warm", errors.New("utoh")), WithProcessName("warm"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Jobs()).To(Equal([]JobStatus{
+		{Name: "migrate"},
+		{Name: "warm"},
+	}))
+
+	// Completion does not halt the application
+	close(block)
+	Eventually(errChan).Should(Receive(MatchError("warm returned an error (utoh)")))
+	Eventually(func() bool { return runner.Jobs()[0].Completed && runner.Jobs()[1].Completed }).Should(BeTrue())
+	Consistently(errChan).ShouldNot(Receive())
+	Expect(runner.isRunning()).To(BeTrue())
+
+	jobs := runner.Jobs()
+	Expect(jobs[0].Err).To(BeNil())
+	Expect(jobs[1].Err).To(MatchError("warm returned an error (utoh)"))
+
+	// Completed jobs are not stopped
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+	Expect(stopped).NotTo(Receive())
+}
+
+func (s *RunnerSuite) TestStopPriority(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		stopped = make(chan string, 4)
+		errChan = make(chan error)
+	)
+
+	makeProcess := func(name string) Process {
+		p := makeBlockingProcess().(*mockProcess)
+		stop := p.stop
+		p.stop = func() error { stopped <- name; return stop() }
+		return p
+	}
+
+	runner.RegisterProcess(makeProcess("http"), WithProcessName("http"), WithPriority(1), WithStopPriority(3))
+	runner.RegisterProcess(makeProcess("cache"), WithProcessName("cache"), WithPriority(1))
+	runner.RegisterProcess(makeProcess("consumer1"), WithProcessName("consumer1"), WithPriority(2))
+	runner.RegisterProcess(makeProcess("consumer2"), WithProcessName("consumer2"), WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	Expect(stopped).To(Receive(Equal("http")))
+	Expect(stopped).To(Receive(Equal("consumer1")))
+	Expect(stopped).To(Receive(Equal("consumer2")))
+	Expect(stopped).To(Receive(Equal("cache")))
+}
+
+func (s *RunnerSuite) TestProcessTags(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		events  = make(chan LifecycleEvent, 20)
+		errChan = make(chan error)
+	)
+
+	runner.Subscribe(func(event LifecycleEvent) {
+		if event.Name == "api" {
+			events <- event
+		}
+	})
+
+	runner.RegisterProcess(
+		makeBlockingProcess(),
+		WithProcessName("api"),
+		WithPriority(2),
+		WithProcessLabels("frontend"),
+		WithProcessTags(map[string]string{"team": "web", "tier": "edge"}),
+		WithProcessTags(map[string]string{"tier": "public"}),
+	)
+
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("worker"), WithPriority(1), WithProcessTags(map[string]string{"team": "data"}))
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("cron"), WithPriority(1), WithProcessTags(map[string]string{"team": "web"}))
+
+	Expect(runner.Processes()).To(Equal([]ProcessInfo{
+		{Name: "cron", Priority: 1, Labels: []string{}, Tags: map[string]string{"team": "web"}},
+		{Name: "worker", Priority: 1, Labels: []string{}, Tags: map[string]string{"team": "data"}},
+		{Name: "api", Priority: 2, Labels: []string{"frontend"}, Tags: map[string]string{"team": "web", "tier": "public"}},
+	}))
+
+	Expect(runner.ProcessesWithTag("team", "web")).To(Equal([]string{"api", "cron"}))
+	Expect(runner.ProcessesWithTag("team", "ops")).To(BeEmpty())
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	Expect(events).NotTo(BeEmpty())
+	for len(events) > 0 {
+		event := <-events
+		Expect(event.Tags).To(Equal(map[string]string{"team": "web", "tier": "public"}))
+	}
+}
+
+func (s *RunnerSuite) TestLivenessShutdown(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	wedged := &livenessProcess{
+		Process: makeBlockingProcess(),
+		check:   func() error { return errors.New("queue stalled") },
+	}
+
+	runner.RegisterProcess(wedged, WithProcessName("consumer"), WithLivenessCheck(LivenessPolicy{
+		Interval:         time.Millisecond * 10,
+		FailureThreshold: 3,
+		Action:           LivenessShutdown,
+	}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("consumer failed 3 consecutive liveness checks (queue stalled)")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestLivenessRestart(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer())
+		initChan = make(chan struct{}, 2)
+		errChan  = make(chan error)
+	)
+
+	process := makeBlockingProcess().(*mockProcess)
+	process.init = func(config Config) error { initChan <- struct{}{}; return nil }
+
+	wedged := &livenessProcess{
+		Process: process,
+		check:   func() error { return errors.New("queue stalled") },
+	}
+
+	runner.RegisterProcess(wedged, WithLivenessCheck(LivenessPolicy{
+		Interval: time.Millisecond * 10,
+		Action:   LivenessRestart,
+	}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(initChan).Should(Receive())
+	Eventually(initChan).Should(Receive())
+
+	runner.Shutdown(time.Second)
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestLivenessHealthy(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		checked = make(chan struct{}, 10)
+		errChan = make(chan error)
+	)
+
+	healthy := &livenessProcess{
+		Process: makeBlockingProcess(),
+		check: func() error {
+			select {
+			case checked <- struct{}{}:
+			default:
+			}
+
+			return nil
+		},
+	}
+
+	runner.RegisterProcess(healthy, WithLivenessCheck(LivenessPolicy{Interval: time.Millisecond * 10}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(checked).Should(Receive())
+	Consistently(errChan).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestLivenessZeroPolicy(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	Expect(LivenessPolicy{}.interval()).To(Equal(defaultLivenessInterval))
+	Expect(LivenessPolicy{Interval: -time.Second}.interval()).To(Equal(defaultLivenessInterval))
+
+	healthy := &livenessProcess{
+		Process: makeBlockingProcess(),
+		check:   func() error { return nil },
+	}
+
+	runner.RegisterProcess(healthy, WithLivenessCheck(LivenessPolicy{}))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(errChan).ShouldNot(Receive())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestStartTimeout(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer())
+		initChan = make(chan string, 1)
+		errChan  = make(chan error)
+	)
+
+	next := makeBlockingProcess().(*mockProcess)
+	next.init = func(config Config) error { initChan <- "next"; return nil }
+
+	runner.RegisterProcess(makeBlockingProcess(), WithPriority(1), WithStartTimeout(time.Millisecond*100))
+	runner.RegisterProcess(next, WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	// Next priority waits for the start window to pass
+	Consistently(initChan, time.Millisecond*50).ShouldNot(Receive())
+	Eventually(initChan).Should(Receive(Equal("next")))
+	Eventually(runner.isRunning).Should(BeTrue())
+
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestStartTimeoutExit(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	process := makeBlockingProcess().(*mockProcess)
+	process.start = func() error { return nil }
+
+	runner.RegisterProcess(process, WithProcessName("server"), WithStartTimeout(time.Second))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("server exited within its start window of 1s")))
+	Eventually(errChan).Should(BeClosed())
+	Expect(runner.isRunning()).To(BeFalse())
+}
+
+func (s *RunnerSuite) TestStartTimeoutPanic(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error, 2)
+	)
+
+	process := makeBlockingProcess().(*mockProcess)
+	process.start = func() error { panic("utoh") }
+
+	runner.RegisterProcess(process, WithProcessName("server"), WithStartTimeout(time.Second))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("server exited within its start window of 1s")))
+	Eventually(errChan).Should(Receive(MatchError("server returned a fatal error (panic (utoh))")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestStartTimeoutNotReady(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		ready   = &readyProcess{Process: makeBlockingProcess(), ready: make(chan struct{})}
+		errChan = make(chan error)
+	)
+
+	runner.RegisterProcess(ready, WithProcessName("warm"), WithStartTimeout(time.Millisecond*20))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("warm did not become ready within its start window of 20ms")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestPanicInStart(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer())
+		stopChan = make(chan string, 2)
+		errChan  = make(chan error)
+	)
+
+	other := makeBlockingProcess().(*mockProcess)
+	stopOther := other.stop
+	other.stop = func() error { stopChan <- "other"; return stopOther() }
+
+	panicking := makeBlockingProcess().(*mockProcess)
+	panicking.start = func() error { panic("utoh") }
+	panicking.stop = func() error { stopChan <- "panicking"; return nil }
+
+	runner.RegisterProcess(other, WithPriority(1))
+	runner.RegisterProcess(panicking, WithPriority(2), WithProcessName("server"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	var err error
+	Eventually(errChan).Should(Receive(&err))
+	Expect(err).To(MatchError("server returned a fatal error (panic (utoh))"))
+
+	var panicErr *PanicError
+	Expect(errors.As(err, &panicErr)).To(BeTrue())
+	Expect(panicErr.Value).To(Equal("utoh"))
+	Expect(panicErr.Stack).To(ContainSubstring("runner_test.go"))
+
+	// Processes stopped with reversed priority
+	Eventually(stopChan).Should(Receive(Equal("panicking")))
+	Eventually(stopChan).Should(Receive(Equal("other")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestPanicInInit(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	process := makeBlockingProcess().(*mockProcess)
+	process.init = func(config Config) error { panic(errors.New("utoh")) }
+	runner.RegisterProcess(process, WithProcessName("db"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	var err error
+	Eventually(errChan).Should(Receive(&err))
+	Expect(err).To(MatchError("failed to initialize db (panic (utoh))"))
+
+	var panicErr *PanicError
+	Expect(errors.As(err, &panicErr)).To(BeTrue())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestPanicInStop(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	process := makeBlockingProcess().(*mockProcess)
+	stop := process.stop
+	process.stop = func() error { stop(); panic("utoh") }
+	runner.RegisterProcess(process, WithProcessName("server"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(Receive(MatchError("server returned error from stop (panic (utoh))")))
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestRunAndWait(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	startError := errors.New("error in start")
+	stopError := errors.New("error in stop")
+
+	failing := makeBlockingProcess().(*mockProcess)
+	failing.start = func() error { return startError }
+
+	stopping := makeBlockingProcess().(*mockProcess)
+	stop := stopping.stop
+	stopping.stop = func() error { stop(); return stopError }
+
+	runner.RegisterProcess(stopping, WithPriority(1), WithProcessName("foo"))
+	runner.RegisterProcess(failing, WithPriority(2), WithProcessName("bar"))
+
+	go func() {
+		errChan <- runner.RunAndWait(nil, log.NewNilLogger())
+	}()
+
+	var err error
+	Eventually(errChan).Should(Receive(&err))
+	Expect(err).To(MatchError("encountered 2 errors (bar returned a fatal error (error in start); foo returned error from stop (error in stop))"))
+	Expect(errors.Is(err, startError)).To(BeTrue())
+	Expect(errors.Is(err, stopError)).To(BeTrue())
+}
+
+func (s *RunnerSuite) TestRunAndWaitNoErrors(t sweet.T) {
+	var (
+		runner  = NewProcessRunner(NewServiceContainer())
+		errChan = make(chan error)
+	)
+
+	runner.RegisterProcess(makeBlockingProcess())
+
+	go func() {
+		errChan <- runner.RunAndWait(nil, log.NewNilLogger())
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(Receive(BeNil()))
+}
+
+func (s *RunnerSuite) TestGroupStartedHook(t sweet.T) {
+	var (
+		hookChan = make(chan int, 2)
+		initChan = make(chan string, 1)
+		errChan  = make(chan error)
+	)
+
+	runner := NewProcessRunner(
+		NewServiceContainer(),
+		WithGroupStartedHook(1, func(priority int) error {
+			hookChan <- priority
+			Expect(initChan).NotTo(Receive())
+			return nil
+		}),
+		WithGroupStartedHook(3, func(priority int) error {
+			hookChan <- priority
+			return nil
+		}),
+	)
+
+	next := makeBlockingProcess().(*mockProcess)
+	next.init = func(config Config) error { initChan <- "next"; return nil }
+
+	runner.RegisterProcess(makeBlockingProcess(), WithPriority(1))
+	runner.RegisterProcess(next, WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(hookChan).Should(Receive(Equal(1)))
+	Eventually(initChan).Should(Receive(Equal("next")))
+	Eventually(runner.isRunning).Should(BeTrue())
+	Consistently(hookChan).ShouldNot(Receive())
+
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+}
+
+func (s *RunnerSuite) TestGroupStartedHookError(t sweet.T) {
+	var (
+		initChan = make(chan string, 1)
+		errChan  = make(chan error)
+	)
+
+	runner := NewProcessRunner(
+		NewServiceContainer(),
+		WithGroupStartedHook(1, func(priority int) error { return errors.New("utoh") }),
+	)
+
+	next := makeBlockingProcess().(*mockProcess)
+	next.init = func(config Config) error { initChan <- "next"; return nil }
+
+	runner.RegisterProcess(makeBlockingProcess(), WithPriority(1))
+	runner.RegisterProcess(next, WithPriority(2))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(errChan).Should(Receive(MatchError("group started hook for priority 1 returned error (utoh)")))
+	Eventually(errChan).Should(BeClosed())
+	Expect(initChan).NotTo(Receive())
+}
+
+func (s *RunnerSuite) TestDuplicateRegistration(t sweet.T) {
+	var (
+		runner   = NewProcessRunner(NewServiceContainer())
+		initChan = make(chan string, 10)
+		errChan  = make(chan error)
+	)
+
+	makeInitializer := func(name string) InitializerFunc {
+		return func(config Config) error { initChan <- name; return nil }
+	}
+
+	shared := makeBlockingProcess().(*mockProcess)
+	shared.init = func(config Config) error { initChan <- "shared"; return nil }
+
+	process := makeBlockingProcess().(*mockProcess)
+	process.init = func(config Config) error { initChan <- "process"; return nil }
+
+	runner.RegisterInitializer(shared, WithInitializerName("shared"))
+	runner.RegisterInitializer(shared, WithInitializerName("shared-again"))
+	runner.RegisterInitializer(makeInitializer("keyed"), WithInitializerName("keyed"), WithInitializerKey("db"))
+	runner.RegisterInitializer(makeInitializer("keyed-again"), WithInitializerName("keyed-again"), WithInitializerKey("db"))
+	runner.RegisterInitializer(makeInitializer("func"))
+	runner.RegisterInitializer(makeInitializer("func"))
+	runner.RegisterProcess(process, WithProcessName("process"))
+	runner.RegisterProcess(process, WithProcessName("process-again"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	close(initChan)
+	names := []string{}
+	for name := range initChan {
+		names = append(names, name)
+	}
+
+	Expect(names).To(Equal([]string{"shared", "keyed", "func", "func", "process"}))
+	Expect(runner.duplicates).To(Equal([]duplicate{
+		{kind: "initializer", name: "shared-again", original: "shared"},
+		{kind: "initializer", name: "keyed-again", original: "keyed"},
+		{kind: "process", name: "process-again", original: "process"},
+	}))
+}
+
+func (s *RunnerSuite) TestSelfTest(t sweet.T) {
+	var (
+		runner = NewProcessRunner(NewServiceContainer())
+		steps  = make(chan string, 10)
+	)
+
+	makeSelfTester := func(name string, err error) *selfTestProcess {
+		process := makeBlockingProcess().(*mockProcess)
+		process.start = func() error { steps <- "start " + name; return nil }
+
+		return &selfTestProcess{
+			Process: process,
+			selfTest: func(ctx context.Context) error {
+				steps <- "self-test " + name
+				return err
+			},
+		}
+	}
+
+	finalizer := &finalizerProcess{
+		Process:  makeBlockingProcess(),
+		finalize: func() error { steps <- "finalize"; return nil },
+	}
+
+	runner.RegisterProcess(makeSelfTester("a", nil), WithProcessName("a"), WithPriority(1))
+	runner.RegisterProcess(makeSelfTester("b", errors.New("utoh")), WithProcessName("b"), WithPriority(2))
+	runner.RegisterProcess(finalizer, WithProcessName("c"), WithPriority(2))
+
+	report := runner.SelfTest(context.Background(), nil, log.NewNilLogger())
+	Expect(report.Passed()).To(BeFalse())
+	Expect(report.Err()).To(MatchError("self-test failed (self-test: b: utoh)"))
+	Expect(report.Results).To(HaveLen(2))
+	Expect(report.Results[0].Name).To(Equal("a"))
+	Expect(report.Results[0].Stage).To(Equal(StageSelfTest))
+	Expect(report.Results[0].Passed).To(BeTrue())
+	Expect(report.Results[1].Name).To(Equal("b"))
+	Expect(report.Results[1].Passed).To(BeFalse())
+	Expect(report.Results[1].Error).To(Equal("utoh"))
+
+	// Every self-test runs and no process is started
+	Expect(steps).To(Receive(Equal("self-test a")))
+	Expect(steps).To(Receive(Equal("self-test b")))
+	Expect(steps).To(Receive(Equal("finalize")))
+	Expect(steps).NotTo(Receive())
+}
+
+func (s *RunnerSuite) TestSelfTestInitFailure(t sweet.T) {
+	var (
+		runner = NewProcessRunner(NewServiceContainer())
+		called = make(chan struct{}, 1)
+	)
+
+	process := &selfTestProcess{
+		Process:  makeBlockingProcess(),
+		selfTest: func(ctx context.Context) error { called <- struct{}{}; return nil },
+	}
+
+	runner.RegisterInitializer(InitializerFunc(func(config Config) error { return errors.New("utoh") }), WithInitializerName("init"))
+	runner.RegisterProcess(process, WithProcessName("process"))
+
+	report := runner.SelfTest(context.Background(), nil, log.NewNilLogger())
+	Expect(report.Passed()).To(BeFalse())
+	Expect(report.Results).To(ConsistOf(SelfTestResult{
+		Name:  "init",
+		Stage: StageInit,
+		Error: "failed to initialize init (utoh)",
+	}))
+
+	Expect(called).NotTo(Receive())
+}
+
 //
 // Mocks
 
@@ -380,3 +2833,198 @@ type mockProcess struct {
 func (p *mockProcess) Init(config Config) error { return p.init(config) }
 func (p *mockProcess) Start() error             { return p.start() }
 func (p *mockProcess) Stop() error              { return p.stop() }
+
+func makeBlockingProcess() Process {
+	var (
+		p = &mockProcess{}
+		c = make(chan struct{})
+		o = &sync.Once{}
+	)
+
+	p.init = func(config Config) error { return nil }
+	p.start = func() error { <-c; return nil }
+	p.stop = func() error { o.Do(func() { close(c) }); return nil }
+	return p
+}
+
+type drainerProcess struct {
+	Process
+	drain func() error
+}
+
+func (p *drainerProcess) Drain() error { return p.drain() }
+
+type handleProcess struct {
+	Process
+	Consumer *mockProcess `process:"consumer"`
+}
+
+type inFlightProcess struct {
+	drainerProcess
+	inFlight func() int
+}
+
+func (p *inFlightProcess) InFlight() int { return p.inFlight() }
+
+type shutdownAwareService struct {
+	notify func()
+}
+
+func (s *shutdownAwareService) ShuttingDown() { s.notify() }
+
+type finalizerProcess struct {
+	Process
+	finalize func() error
+}
+
+func (p *finalizerProcess) Finalize() error { return p.finalize() }
+
+type rollbackInitializer struct {
+	Initializer
+	rollback func() error
+}
+
+func (i *rollbackInitializer) Rollback() error { return i.rollback() }
+
+type selfTestProcess struct {
+	Process
+	selfTest func(ctx context.Context) error
+}
+
+func (p *selfTestProcess) SelfTest(ctx context.Context) error { return p.selfTest(ctx) }
+
+type readyProcess struct {
+	Process
+	ready chan struct{}
+}
+
+func (p *readyProcess) Ready() <-chan struct{} { return p.ready }
+
+type livenessProcess struct {
+	Process
+	check func() error
+}
+
+func (p *livenessProcess) HealthCheck() error { return p.check() }
+
+type injectedProcess struct {
+	Process
+	Missing *IntWrapper `service:"missing"`
+}
+
+type progressProcess struct {
+	Process
+	Progress *ProgressReporter `service:"progress"`
+	proceed  chan struct{}
+}
+
+func (p *progressProcess) Init(config Config) error {
+	p.Progress.SetSteps(3)
+	p.Progress.Step("connecting")
+	p.Progress.Step("loading schema")
+	<-p.proceed
+	return p.Process.Init(config)
+}
+
+type progressLogger struct {
+	Logger
+	messages chan string
+}
+
+func (l *progressLogger) InfoWithFields(fields Fields, format string, args ...interface{}) {
+	select {
+	case l.messages <- fmt.Sprintf(format, args...):
+	default:
+	}
+}
+
+type mockContextProcess struct {
+	Value   *IntWrapper `service:"value"`
+	initCtx context.Context
+	started chan struct{}
+	stopped chan error
+}
+
+func (p *mockContextProcess) Init(ctx context.Context, config Config) error {
+	p.initCtx = ctx
+	return nil
+}
+
+func (p *mockContextProcess) Start(ctx context.Context) error {
+	close(p.started)
+	<-ctx.Done()
+	return nil
+}
+
+func (p *mockContextProcess) Stop(ctx context.Context) error {
+	p.stopped <- ctx.Err()
+	return nil
+}
+
+type syncingLogger struct {
+	Logger
+	sync func() error
+}
+
+func (l *syncingLogger) Sync() error { return l.sync() }
+
+type errorLogger struct {
+	Logger
+	messages chan string
+}
+
+func (l *errorLogger) ErrorWithFields(fields Fields, format string, args ...interface{}) {
+	l.messages <- fmt.Sprintf(format, args...)
+}
+
+type warningLogger struct {
+	Logger
+	messages chan string
+}
+
+func (l *warningLogger) Warning(format string, args ...interface{}) {
+	l.messages <- fmt.Sprintf(format, args...)
+}
+
+func (l *warningLogger) WarningWithFields(fields Fields, format string, args ...interface{}) {
+	l.messages <- fmt.Sprintf(format, args...)
+}
+
+type stalledInitializer struct {
+	Progress *ProgressReporter `service:"progress"`
+	block    chan struct{}
+}
+
+func (i *stalledInitializer) Init(config Config) error {
+	i.Progress.SetSteps(2)
+	i.Progress.Step("connecting")
+	<-i.block
+	return nil
+}
+
+type testTenant struct {
+	Name  string `json:"name"`
+	Topic string `json:"topic"`
+}
+
+func (t *testTenant) TenantName() string { return t.Name }
+
+type replicaProcess struct {
+	Replica   *Replica `service:"replica"`
+	startChan chan int
+	halt      chan struct{}
+	once      *sync.Once
+}
+
+func (p *replicaProcess) Init(config Config) error { return nil }
+
+func (p *replicaProcess) Start() error {
+	p.startChan <- p.Replica.Index
+	<-p.halt
+	return nil
+}
+
+func (p *replicaProcess) Stop() error {
+	p.once.Do(func() { close(p.halt) })
+	return nil
+}
@@ -0,0 +1,36 @@
+package nacelle
+
+import "os"
+
+// WithTimelineFile causes the runner to write a timeline of its lifecycle (see
+// Timeline) to the file at the given path once all processes have stopped. A
+// failure to write the timeline is logged but does not fail the run.
+func WithTimelineFile(path string) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) {
+		pr.timeline = NewTimeline()
+		pr.timelinePath = path
+		pr.Subscribe(pr.timeline.Observe)
+	}
+}
+
+// writeTimeline writes the runner's timeline to its timeline file, if set.
+func (pr *ProcessRunner) writeTimeline() {
+	if pr.timeline == nil {
+		return
+	}
+
+	file, err := os.Create(pr.timelinePath)
+	if err != nil {
+		pr.logger.Warning("Failed to write lifecycle timeline (%s)", err.Error())
+		return
+	}
+
+	defer file.Close()
+
+	if err := pr.timeline.Write(file); err != nil {
+		pr.logger.Warning("Failed to write lifecycle timeline (%s)", err.Error())
+		return
+	}
+
+	pr.logger.Info("Wrote lifecycle timeline to %s", pr.timelinePath)
+}
@@ -0,0 +1,58 @@
+package nacelle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RunError summarizes every error sent on the runner's error channel during a
+// call to RunAndWait.
+type RunError struct {
+	Errors []error
+}
+
+// RunAndWait calls Run and blocks until the error channel is closed. A RunError
+// containing each error received from the channel is returned, or nil if no
+// errors were received.
+func (pr *ProcessRunner) RunAndWait(config Config, logger Logger) error {
+	return pr.RunContextAndWait(context.Background(), config, logger)
+}
+
+// RunContextAndWait behaves like RunAndWait, but calls RunContext with the given
+// context.
+func (pr *ProcessRunner) RunContextAndWait(ctx context.Context, config Config, logger Logger) error {
+	errs := []error{}
+	for err := range pr.RunContext(ctx, config, logger) {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &RunError{Errors: errs}
+}
+
+func (e *RunError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+
+	messages := []string{}
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf(
+		"encountered %d errors (%s)",
+		len(e.Errors),
+		strings.Join(messages, "; "),
+	)
+}
+
+// Unwrap returns the errors received from the runner, so that errors.Is and
+// errors.As match against each of them.
+func (e *RunError) Unwrap() []error {
+	return e.Errors
+}
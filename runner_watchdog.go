@@ -0,0 +1,191 @@
+package nacelle
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// activity is a lifecycle operation which the runner is waiting on.
+	activity struct {
+		description string
+		since       time.Time
+		pending     func() []string
+		reported    bool
+	}
+
+	// watchedMutex is a mutex which records where and when it was acquired so
+	// that the watchdog can report a lock which has been held for too long.
+	watchedMutex struct {
+		sync.Mutex
+		meta   sync.Mutex
+		holder string
+		since  time.Time
+	}
+)
+
+const watchdogStackSize = 1 << 20
+
+// WithWatchdog enables a watchdog which logs an error along with a dump of all
+// goroutines when an initialization or shutdown operation (e.g. the Init or Stop
+// method of a process, or waiting for a process to exit) makes no progress for
+// the given duration. The log message names the operation and the processes the
+// runner is waiting on, as well as the location holding the runner's lock if it
+// has been held for the same duration. The watchdog is disabled by default.
+func WithWatchdog(timeout time.Duration) ProcessRunnerConfigFunc {
+	return func(pr *ProcessRunner) { pr.watchdogTimeout = timeout }
+}
+
+// track registers a lifecycle operation with the watchdog. The returned
+// function must be called once the operation has completed.
+func (pr *ProcessRunner) track(format string, args ...interface{}) func() {
+	return pr.trackPending(nil, format, args...)
+}
+
+// trackPending registers a lifecycle operation which waits on a set of processes,
+// the names of which are returned by the given function, with the watchdog.
+func (pr *ProcessRunner) trackPending(pending func() []string, format string, args ...interface{}) func() {
+	a := &activity{
+		description: fmt.Sprintf(format, args...),
+		since:       time.Now(),
+		pending:     pending,
+	}
+
+	pr.activityMutex.Lock()
+	pr.activities[a] = struct{}{}
+	pr.activityMutex.Unlock()
+
+	return func() {
+		pr.activityMutex.Lock()
+		delete(pr.activities, a)
+		pr.activityMutex.Unlock()
+	}
+}
+
+// runWatchdog periodically checks for stalled operations until the runner
+// has stopped.
+func (pr *ProcessRunner) runWatchdog() {
+	ticker := time.NewTicker(pr.watchdogTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pr.checkProgress()
+		case <-pr.done:
+			return
+		}
+	}
+}
+
+// checkProgress logs each operation which has been running for longer than the
+// watchdog timeout and has not yet been reported.
+func (pr *ProcessRunner) checkProgress() {
+	stalled := []string{}
+
+	pr.activityMutex.Lock()
+	for a := range pr.activities {
+		if a.reported || time.Since(a.since) < pr.watchdogTimeout {
+			continue
+		}
+
+		a.reported = true
+		description := a.description
+
+		if a.pending != nil {
+			if names := a.pending(); len(names) > 0 {
+				description = fmt.Sprintf("%s (waiting on %s)", description, strings.Join(names, ", "))
+			}
+		}
+
+		stalled = append(stalled, description)
+	}
+	pr.activityMutex.Unlock()
+
+	if len(stalled) == 0 {
+		return
+	}
+
+	sort.Strings(stalled)
+
+	fields := Fields{
+		"goroutines": dumpGoroutines(),
+	}
+
+	if holder, held := pr.mutex.held(); held >= pr.watchdogTimeout {
+		fields["lock_holder"] = holder
+		fields["lock_held"] = held.Seconds()
+	}
+
+	for _, description := range stalled {
+		pr.logger.ErrorWithFields(fields, "Runner has made no progress for %s while %s", pr.watchdogTimeout, description)
+	}
+}
+
+// unexitedProcesses returns the names of the registered processes whose Start
+// method has not returned. The runner's lock is not waited on, as it may be the
+// cause of the stall; nil is returned if it is held.
+func (pr *ProcessRunner) unexitedProcesses() []string {
+	if !pr.mutex.TryLock() {
+		return nil
+	}
+
+	processes := []*processMeta{}
+	for _, p := range pr.processes {
+		processes = append(processes, p...)
+	}
+
+	pr.mutex.Unlock()
+
+	names := []string{}
+	for _, process := range processes {
+		if process.getExited() != nil && !hasExited(process) {
+			names = append(names, process.Name())
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func dumpGoroutines() string {
+	buffer := make([]byte, watchdogStackSize)
+	return string(buffer[:runtime.Stack(buffer, true)])
+}
+
+func (m *watchedMutex) Lock() {
+	m.Mutex.Lock()
+
+	_, file, line, _ := runtime.Caller(1)
+
+	m.meta.Lock()
+	m.holder = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	m.since = time.Now()
+	m.meta.Unlock()
+}
+
+func (m *watchedMutex) Unlock() {
+	m.meta.Lock()
+	m.holder = ""
+	m.meta.Unlock()
+
+	m.Mutex.Unlock()
+}
+
+// held returns the location which acquired the lock and the duration for
+// which it has been held. An empty location is returned if it is not held.
+func (m *watchedMutex) held() (string, time.Duration) {
+	m.meta.Lock()
+	defer m.meta.Unlock()
+
+	if m.holder == "" {
+		return "", 0
+	}
+
+	return m.holder, time.Since(m.since)
+}
@@ -1,19 +1,58 @@
 package nacelle
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 type (
-	// ServiceContainer is a container used for dependency injection.
+	// ServiceContainer is a container used for dependency injection. A container
+	// is safe for concurrent use, so processes initialized at different priorities
+	// (or a process which registers services from its Start method) do not race on
+	// the registered services.
 	ServiceContainer struct {
-		services map[interface{}]interface{}
+		mutex       sync.RWMutex
+		services    map[interface{}]interface{}
+		factories   map[interface{}]*lazyService
+		frozen      bool
+		interceptor serviceInterceptor
+		mappings    map[reflect.Type]FieldMapping
+		groups      map[string]map[string]interface{}
+		bindings    map[string][]interface{}
+		parent      *ServiceContainer
+		resolving   []resolution
+		order       []interface{}
+		closed      bool
+		usage       serviceUsage
+		origin      *ServiceContainer
+	}
+
+	// PostInjector is implemented by objects which validate or derive state from
+	// their injected services (e.g. checking that one of two optional services
+	// is set). The PostInject method is called once the object's fields have
+	// been set by Inject, and an error returned from it fails the injection.
+	PostInjector interface {
+		PostInject() error
+	}
+
+	// InjectionError is returned by Inject when one or more fields could not
+	// be set.
+	InjectionError struct {
+		Errors []error
 	}
 
 	// ServiceInitializerFunc is an InitializerFunc with a container argument.
 	ServiceInitializerFunc func(config Config, container *ServiceContainer) error
+
+	// unregisteredKeyError is returned when retrieving a key to which no service
+	// is registered.
+	unregisteredKeyError struct {
+		key interface{}
+	}
 )
 
 const (
@@ -21,6 +60,9 @@ const (
 	optionalTag = "optional"
 )
 
+// ErrContainerFrozen is returned when registering a service to a frozen container.
+var ErrContainerFrozen = errors.New("service container is frozen")
+
 // WrapServiceInitializerFunc creates an InitializerFunc from a ServiceInitializerFunc and a container.
 func WrapServiceInitializerFunc(container *ServiceContainer, f ServiceInitializerFunc) InitializerFunc {
 	return InitializerFunc(func(config Config) error {
@@ -41,11 +83,50 @@ func NewServiceContainer() *ServiceContainer {
 // Get retrieves a service by its key. It is an error to retreive a service
 // that has not been registered.
 func (c *ServiceContainer) Get(key interface{}) (interface{}, error) {
+	service, err := c.get(key)
+	if c.interceptor != nil {
+		return c.interceptor.intercept(key, service, err)
+	}
+
+	return service, err
+}
+
+// interceptMember returns the given member of a group or multi-binding, or its
+// substitute if the container is wrapped by a TestContainer.
+func (c *ServiceContainer) interceptMember(service interface{}) interface{} {
+	if c.interceptor != nil {
+		return c.interceptor.interceptMember(service)
+	}
+
+	return service
+}
+
+func (c *ServiceContainer) get(key interface{}) (interface{}, error) {
+	return c.resolve(key, c.resolving)
+}
+
+// resolve retrieves a service by its key, where chain is the sequence of factories
+// currently being invoked on behalf of the retrieval.
+func (c *ServiceContainer) resolve(key interface{}, chain []resolution) (interface{}, error) {
+	c.mutex.RLock()
 	service, ok := c.services[key]
+	lazy := c.factories[key]
+	c.mutex.RUnlock()
+
+	if !ok && lazy != nil {
+		c.markUsed(key)
+		return lazy.get(key, chain)
+	}
+
+	if !ok && c.parent != nil {
+		return c.parent.resolve(key, chain)
+	}
+
 	if !ok {
-		return nil, fmt.Errorf("no service registered to key `%s`", serializeKey(key))
+		return nil, &unregisteredKeyError{key}
 	}
 
+	c.markUsed(key)
 	return service, nil
 }
 
@@ -71,23 +152,84 @@ func (c *ServiceContainer) MustGet(service interface{}) interface{} {
 }
 
 // Set associates a srevice with a key. It is an error to register multiple
-// services to the same key, or to register an object that is not a Logger
-// to the key "logger".
+// services to the same key, to register an object that is not a Logger to
+// the key "logger", or to register any service after the container has been
+// frozen. See Replace and Remove to change an existing registration.
 func (c *ServiceContainer) Set(key, service interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.frozen {
+		return ErrContainerFrozen
+	}
+
 	if key == "logger" {
 		if _, ok := service.(Logger); !ok {
 			return fmt.Errorf("logger instance is not a nacelle.Logger")
 		}
 	}
 
-	if _, ok := c.services[key]; ok {
+	if c.registered(key) {
 		return fmt.Errorf("duplicate service key `%s`", serializeKey(key))
 	}
 
 	c.services[key] = service
+	c.order = append(c.order, key)
 	return nil
 }
 
+// Freeze disallows any further registration of services. This is called by
+// the process runner once all initializers and processes have been initialized
+// so that shared wiring cannot be mutated while the application is running.
+func (c *ServiceContainer) Freeze() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.frozen = true
+}
+
+// Frozen returns true if Freeze has been called on the container.
+func (c *ServiceContainer) Frozen() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.frozen
+}
+
+// snapshot returns a copy of the registered services, including the services
+// of any parent container which are not overridden and the services registered
+// via SetFactory which have been constructed.
+func (c *ServiceContainer) snapshot() map[interface{}]interface{} {
+	return c.snapshotServices(true)
+}
+
+// registeredSnapshot returns a copy of the registered services as snapshot does,
+// but excludes services registered via SetFactory.
+func (c *ServiceContainer) registeredSnapshot() map[interface{}]interface{} {
+	return c.snapshotServices(false)
+}
+
+func (c *ServiceContainer) snapshotServices(constructed bool) map[interface{}]interface{} {
+	services := c.parentSnapshot(constructed)
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if constructed {
+		for key, lazy := range c.factories {
+			if service, ok := lazy.constructedService(); ok {
+				services[key] = service
+			}
+		}
+	}
+
+	for key, service := range c.services {
+		services[key] = service
+	}
+
+	return services
+}
+
 // MustSet calls Set and panics on error.
 func (c *ServiceContainer) MustSet(service, value interface{}) {
 	if err := c.Set(service, value); err != nil {
@@ -95,42 +237,121 @@ func (c *ServiceContainer) MustSet(service, value interface{}) {
 	}
 }
 
-// Inject will set the exported fields tagged as `service:"name"` of
-// the given object with the service registered to that name. Unless
-// the field is tagged with `optional:"true"`, a service missing from
-// the container will result in an error.
+// Inject sets the exported fields of the given struct which are tagged with
+// `service`, `group`, or a field mapping from the container, then calls the
+// PostInject hook if present. Every field is attempted, so the InjectionError
+// reports each missing or mistyped service at once (see the README for tags).
 func (c *ServiceContainer) Inject(obj interface{}) error {
+	return c.injectWithOverrides(obj, nil)
+}
+
+// MustInject calls Inject and panics on error.
+func (c *ServiceContainer) MustInject(obj interface{}) {
+	if err := c.Inject(obj); err != nil {
+		panic(err.Error())
+	}
+}
+
+// injectWithOverrides performs an injection where the given services take
+// precedence over the services registered to the container.
+func (c *ServiceContainer) injectWithOverrides(obj interface{}, overrides map[interface{}]interface{}) error {
+	if err := c.injectFields(obj, overrides); err != nil {
+		return err
+	}
+
+	if postInjector, ok := obj.(PostInjector); ok {
+		if err := postInjector.PostInject(); err != nil {
+			return fmt.Errorf("post-inject hook returned an error (%s)", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *ServiceContainer) injectFields(obj interface{}, overrides map[interface{}]interface{}) error {
+	if c != nil && c.interceptor != nil {
+		c.interceptor.recordInject(obj)
+	}
+
+	if wirer, ok := obj.(Wirer); ok {
+		return wirer.Wire(c.withOverrides(overrides))
+	}
+
+	get := func(key interface{}) (interface{}, error) {
+		if service, ok := overrides[key]; ok {
+			return service, nil
+		}
+
+		return c.Get(key)
+	}
+
 	var (
 		ov = reflect.ValueOf(obj)
 		oi = reflect.Indirect(ov)
-		ot = oi.Type()
 	)
 
-	if oi.Kind() != reflect.Struct {
+	if ov.Kind() == reflect.Func {
+		// Function adapters (e.g. InitializerFunc) have no fields to set
 		return nil
 	}
 
+	if !oi.IsValid() || oi.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot inject services into a value of type %s (expected a struct or a pointer to a struct)", getTypeName(obj))
+	}
+
+	ot := oi.Type()
+
+	errs := c.injectMappedFields(get, oi)
+
 	for i := 0; i < ot.NumField(); i++ {
 		var (
 			fieldType   = ot.Field(i)
 			fieldValue  = oi.Field(i)
 			serviceTag  = fieldType.Tag.Get(serviceTag)
 			optionalTag = fieldType.Tag.Get(optionalTag)
+			defaultTag  = fieldType.Tag.Get(defaultTag)
+			groupTag    = fieldType.Tag.Get(groupTag)
+			typeTag     = fieldType.Tag.Get(typeTag)
 		)
 
+		if groupTag != "" {
+			if err := c.loadGroupField(fieldType, fieldValue, groupTag); err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
 		if serviceTag == "" {
 			continue
 		}
 
-		if err := loadServiceField(c, fieldType, fieldValue, serviceTag, optionalTag); err != nil {
-			return err
+		if typeTag == typeTagBinding {
+			if err := c.loadBindingField(fieldType, fieldValue, serviceTag); err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		if defaultTag != "" && c != nil {
+			// The default is wiring even if the field's own service is present
+			c.markUsed(defaultTag)
+		}
+
+		if err := loadServiceField(get, fieldType, fieldValue, serviceTag, optionalTag, defaultTag); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
+	if len(errs) > 0 {
+		return &InjectionError{Errors: errs}
+	}
+
 	return nil
 }
 
-func loadServiceField(container *ServiceContainer, fieldType reflect.StructField, fieldValue reflect.Value, serviceTag, optionalTag string) error {
+func loadServiceField(get func(interface{}) (interface{}, error), fieldType reflect.StructField, fieldValue reflect.Value, serviceKey interface{}, optionalTag, defaultTag string) error {
 	if !fieldValue.IsValid() {
 		return fmt.Errorf("field '%s' is invalid", fieldType.Name)
 	}
@@ -139,9 +360,16 @@ func loadServiceField(container *ServiceContainer, fieldType reflect.StructField
 		return fmt.Errorf("field '%s' can not be set", fieldType.Name)
 	}
 
-	value, err := container.Get(serviceTag)
+	// Only a missing service falls back to the default or is left unset, so that
+	// a registered service which fails to resolve (e.g. a failing factory or a
+	// circular dependency) is reported
+	value, err := get(serviceKey)
+	if isUnregistered(err) && defaultTag != "" {
+		value, err = get(defaultTag)
+	}
+
 	if err != nil {
-		if optionalTag != "" {
+		if optionalTag != "" && isUnregistered(err) {
 			val, err := strconv.ParseBool(optionalTag)
 			if err != nil {
 				return fmt.Errorf("field '%s' has an invalid optional tag", fieldType.Name)
@@ -161,6 +389,18 @@ func loadServiceField(container *ServiceContainer, fieldType reflect.StructField
 	)
 
 	if !targetValue.IsValid() || !targetValue.Type().ConvertibleTo(targetType) {
+		// Map and function services must match the field's key, element, or
+		// parameter types exactly, so the expected type is included to make a
+		// mismatched signature easy to spot
+		if kind := targetType.Kind(); kind == reflect.Map || kind == reflect.Func {
+			return fmt.Errorf(
+				"field '%s' cannot be assigned a value of type %s (expected %s)",
+				fieldType.Name,
+				getTypeName(value),
+				targetType.String(),
+			)
+		}
+
 		return fmt.Errorf(
 			"field '%s' cannot be assigned a value of type %s",
 			fieldType.Name,
@@ -172,6 +412,29 @@ func loadServiceField(container *ServiceContainer, fieldType reflect.StructField
 	return nil
 }
 
+func (e *InjectionError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+
+	messages := []string{}
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf(
+		"encountered %d injection errors (%s)",
+		len(e.Errors),
+		strings.Join(messages, "; "),
+	)
+}
+
+// Unwrap returns the error of each field, so that errors.Is and errors.As match
+// against each of them.
+func (e *InjectionError) Unwrap() []error {
+	return e.Errors
+}
+
 func getTypeName(v interface{}) string {
 	if v == nil {
 		return "nil"
@@ -179,3 +442,14 @@ func getTypeName(v interface{}) string {
 
 	return reflect.TypeOf(v).String()
 }
+
+func (e *unregisteredKeyError) Error() string {
+	return fmt.Sprintf("no service registered to key `%s`", serializeKey(e.key))
+}
+
+// isUnregistered returns true if the given error was returned when retrieving a
+// key to which no service is registered.
+func isUnregistered(err error) bool {
+	var unregisteredErr *unregisteredKeyError
+	return errors.As(err, &unregisteredErr)
+}
@@ -0,0 +1,97 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	typeTag        = "type"
+	typeTagBinding = "group"
+)
+
+// Add appends a service to the named multi-binding. The services of a multi-binding
+// can be injected together into a slice field tagged with `service:"name" type:"group"`,
+// in the order in which they were added, where each service must be assignable to
+// the slice's element type. Unlike the groups of SetInGroup, the services are not
+// keyed and their order is preserved, which allows middleware, interceptors, or
+// health checks to be composed from independently registered plugins. It is an
+// error to add a service after the container has been frozen.
+func (c *ServiceContainer) Add(name string, service interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.frozen {
+		return ErrContainerFrozen
+	}
+
+	if c.bindings == nil {
+		c.bindings = map[string][]interface{}{}
+	}
+
+	c.bindings[name] = append(c.bindings[name], service)
+	return nil
+}
+
+// MustAdd calls Add and panics on error.
+func (c *ServiceContainer) MustAdd(name string, service interface{}) {
+	if err := c.Add(name, service); err != nil {
+		panic(err.Error())
+	}
+}
+
+// All returns a copy of the services added to the named multi-binding, in the
+// order in which they were added. For a child container, the services added to
+// the multi-binding of its parent precede its own.
+func (c *ServiceContainer) All(name string) []interface{} {
+	services := []interface{}{}
+	if c.parent != nil {
+		services = c.parent.All(name)
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return append(services, c.bindings[name]...)
+}
+
+func (c *ServiceContainer) loadBindingField(fieldType reflect.StructField, fieldValue reflect.Value, name string) error {
+	if !fieldValue.IsValid() {
+		return fmt.Errorf("field '%s' is invalid", fieldType.Name)
+	}
+
+	if !fieldValue.CanSet() {
+		return fmt.Errorf("field '%s' can not be set", fieldType.Name)
+	}
+
+	targetType := fieldValue.Type()
+	if targetType.Kind() != reflect.Slice {
+		return fmt.Errorf("field '%s' tagged with type group must be a slice", fieldType.Name)
+	}
+
+	var (
+		elemType = targetType.Elem()
+		services = c.All(name)
+		target   = reflect.MakeSlice(targetType, 0, len(services))
+	)
+
+	for i, service := range services {
+		service = c.interceptMember(service)
+		value := reflect.ValueOf(service)
+
+		if !value.IsValid() || !value.Type().ConvertibleTo(elemType) {
+			return fmt.Errorf(
+				"field '%s' cannot be assigned a value of type %s (index %d of group `%s`)",
+				fieldType.Name,
+				getTypeName(service),
+				i,
+				name,
+			)
+		}
+
+		target = reflect.Append(target, value.Convert(elemType))
+	}
+
+	fieldValue.Set(target)
+	return nil
+}
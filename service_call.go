@@ -0,0 +1,92 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Call invokes the given function with arguments resolved from the container and
+// returns its results. A parameter whose type is a struct with at least one field
+// tagged with `service` or `group` is resolved by tag: a value of the struct is
+// populated as if by Inject. Every other parameter is resolved by type as by
+// Provide. If the last result of the function is an error, it is returned as the
+// error of Call and excluded from the results. This allows constructor-style
+// wiring in addition to tag-based injection.
+func (c *ServiceContainer) Call(fn interface{}) ([]interface{}, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		return nil, fmt.Errorf("value of type %s is not a function", getTypeName(fn))
+	}
+
+	ft := fv.Type()
+
+	args := []reflect.Value{}
+	for i := 0; i < ft.NumIn(); i++ {
+		value, err := c.resolveArg(ft.In(i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve arguments (parameter %d: %s)", i, err.Error())
+		}
+
+		args = append(args, value)
+	}
+
+	results := fv.Call(args)
+
+	if n := ft.NumOut(); n > 0 && ft.Out(n-1) == errorType {
+		last := results[n-1]
+		results = results[:n-1]
+
+		if !last.IsNil() {
+			return nil, last.Interface().(error)
+		}
+	}
+
+	values := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		values = append(values, result.Interface())
+	}
+
+	return values, nil
+}
+
+// MustCall calls Call and panics on error.
+func (c *ServiceContainer) MustCall(fn interface{}) []interface{} {
+	values, err := c.Call(fn)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return values
+}
+
+// resolveArg resolves a parameter of a function invoked by Call.
+func (c *ServiceContainer) resolveArg(t reflect.Type) (reflect.Value, error) {
+	if !hasInjectionTags(t) {
+		return c.resolveByType(t)
+	}
+
+	value := reflect.New(t)
+	if err := c.Inject(value.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return value.Elem(), nil
+}
+
+// hasInjectionTags returns true if the given type is a struct with at least one
+// field tagged with `service` or `group`.
+func hasInjectionTags(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Tag.Get(serviceTag) != "" || field.Tag.Get(groupTag) != "" {
+			return true
+		}
+	}
+
+	return false
+}
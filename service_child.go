@@ -0,0 +1,65 @@
+package nacelle
+
+import "reflect"
+
+// Child creates a container which resolves services, groups, and field mappings
+// registered to itself first and falls back to this container. Services can be
+// registered to the child under keys already registered to this container, which
+// overrides them for the child only. This allows per-request or per-subsystem
+// overrides without mutating this container. The child is not frozen when this
+// container is frozen, and the key "container" resolves to the child itself.
+func (c *ServiceContainer) Child() *ServiceContainer {
+	c.mutex.RLock()
+	interceptor := c.interceptor
+	c.mutex.RUnlock()
+
+	child := &ServiceContainer{
+		services:    map[interface{}]interface{}{},
+		parent:      c,
+		interceptor: interceptor,
+	}
+
+	child.Set("container", child)
+	return child
+}
+
+// Parent returns the container from which this container was created by Child,
+// or nil if this is not a child container.
+func (c *ServiceContainer) Parent() *ServiceContainer {
+	return c.parent
+}
+
+// parentSnapshot returns a copy of the services registered to the ancestors of
+// this container, where services of nearer ancestors take precedence. Services
+// registered via SetFactory are included once constructed if requested.
+func (c *ServiceContainer) parentSnapshot(constructed bool) map[interface{}]interface{} {
+	if c.parent == nil {
+		return map[interface{}]interface{}{}
+	}
+
+	return c.parent.snapshotServices(constructed)
+}
+
+// parentGroup returns a copy of the services registered within the named group
+// of the ancestors of this container.
+func (c *ServiceContainer) parentGroup(group string) map[string]interface{} {
+	if c.parent == nil {
+		return map[string]interface{}{}
+	}
+
+	return c.parent.Group(group)
+}
+
+// mapping returns the field mapping registered for the given type to this
+// container or, failing that, to its nearest ancestor.
+func (c *ServiceContainer) mapping(t reflect.Type) (FieldMapping, bool) {
+	c.mutex.RLock()
+	mapping, ok := c.mappings[t]
+	c.mutex.RUnlock()
+
+	if !ok && c.parent != nil {
+		return c.parent.mapping(t)
+	}
+
+	return mapping, ok
+}
@@ -0,0 +1,122 @@
+package nacelle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+type (
+	// ServiceShutdowner is implemented by services which release their resources
+	// with a context (e.g. an HTTP client transport or a pool which waits for
+	// borrowed connections to be returned). See ServiceContainer.Close.
+	ServiceShutdowner interface {
+		Shutdown(ctx context.Context) error
+	}
+
+	// CloseError is returned by Close when one or more services fail to close.
+	CloseError struct {
+		// Errors maps the serialized key of each service which failed to close
+		// to the error it returned.
+		Errors map[string]error
+
+		keys []string
+	}
+)
+
+// Close releases the resources of the services registered to the container. Each
+// service which implements io.Closer or ServiceShutdowner is closed, in reverse
+// order of registration so that a service is closed before the services which it
+// was constructed from. A service registered by SetFactory is closed only if it
+// has been constructed, and a service registered to multiple keys is closed once.
+// The logger, the container itself, the services of a parent container, and the
+// services of groups are not closed. The process runner calls Close once all of
+// its processes and initializers have been finalized. Subsequent calls to Close
+// do nothing.
+func (c *ServiceContainer) Close() error {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return nil
+	}
+
+	c.closed = true
+	order := append([]interface{}{}, c.order...)
+	services := map[interface{}]interface{}{}
+	factories := map[interface{}]*lazyService{}
+
+	for key, service := range c.services {
+		services[key] = service
+	}
+
+	for key, lazy := range c.factories {
+		factories[key] = lazy
+	}
+	c.mutex.Unlock()
+
+	var (
+		closeErr = &CloseError{Errors: map[string]error{}}
+		closed   = map[interface{}]struct{}{}
+	)
+
+	for i := len(order) - 1; i >= 0; i-- {
+		key := order[i]
+		if key == "logger" || key == "container" {
+			continue
+		}
+
+		service, ok := services[key]
+		if lazy := factories[key]; lazy != nil {
+			service, ok = lazy.constructedService()
+		}
+
+		if !ok || service == nil || service == interface{}(c) {
+			continue
+		}
+
+		if reflect.TypeOf(service).Comparable() {
+			if _, ok := closed[service]; ok {
+				continue
+			}
+
+			closed[service] = struct{}{}
+		}
+
+		if err := callSafely(func() error { return closeService(service) }); err != nil {
+			closeErr.add(serializeKey(key), err)
+		}
+	}
+
+	if len(closeErr.keys) > 0 {
+		return closeErr
+	}
+
+	return nil
+}
+
+func closeService(service interface{}) error {
+	switch s := service.(type) {
+	case ServiceShutdowner:
+		return s.Shutdown(context.Background())
+	case io.Closer:
+		return s.Close()
+	}
+
+	return nil
+}
+
+func (e *CloseError) add(key string, err error) {
+	e.keys = append(e.keys, key)
+	e.Errors[key] = err
+}
+
+func (e *CloseError) Error() string {
+	messages := []string{}
+	for _, key := range e.keys {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, e.Errors[key].Error()))
+	}
+
+	return fmt.Sprintf("failed to close services (%s)", strings.Join(messages, "; "))
+}
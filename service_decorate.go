@@ -0,0 +1,83 @@
+package nacelle
+
+import "fmt"
+
+// ServiceDecorator wraps a service (e.g. a logger which redacts secrets, or a
+// database client which records traces). See Decorate.
+type ServiceDecorator func(existing interface{}) interface{}
+
+// Decorate replaces the service registered to the given key with the result of
+// the given decorator, so that every consumer which retrieves the key receives
+// the wrapped service. If the key is registered by SetFactory, the decorator is
+// applied to the service once it has been constructed. Decorators applied to the
+// same key are nested in the order in which they were applied. It is an error
+// to decorate a service which has not been registered to this container, to
+// decorate the logger into an object that is not a Logger, or to decorate any
+// service after the container has been frozen. The decorator is called while
+// the container is locked and must not retrieve services from it.
+func (c *ServiceContainer) Decorate(key interface{}, decorator ServiceDecorator) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.frozen {
+		return ErrContainerFrozen
+	}
+
+	if lazy, ok := c.factories[key]; ok {
+		factory := lazy.factory
+
+		decoratedLazy := &lazyService{container: c, factory: func(container *ServiceContainer) (interface{}, error) {
+			service, err := factory(container)
+			if err != nil {
+				return nil, err
+			}
+
+			return decorateService(key, service, decorator)
+		}}
+
+		if service, constructed := lazy.constructedService(); constructed {
+			decorated, err := decorateService(key, service, decorator)
+			if err != nil {
+				return err
+			}
+
+			decoratedLazy.constructed = true
+			decoratedLazy.service = decorated
+		}
+
+		c.factories[key] = decoratedLazy
+		return nil
+	}
+
+	service, ok := c.services[key]
+	if !ok {
+		return fmt.Errorf("no service registered to key `%s`", serializeKey(key))
+	}
+
+	decorated, err := decorateService(key, service, decorator)
+	if err != nil {
+		return err
+	}
+
+	c.services[key] = decorated
+	return nil
+}
+
+// MustDecorate calls Decorate and panics on error.
+func (c *ServiceContainer) MustDecorate(key interface{}, decorator ServiceDecorator) {
+	if err := c.Decorate(key, decorator); err != nil {
+		panic(err.Error())
+	}
+}
+
+func decorateService(key, service interface{}, decorator ServiceDecorator) (interface{}, error) {
+	decorated := decorator(service)
+
+	if key == "logger" {
+		if _, ok := decorated.(Logger); !ok {
+			return nil, fmt.Errorf("logger instance is not a nacelle.Logger")
+		}
+	}
+
+	return decorated, nil
+}
@@ -0,0 +1,90 @@
+package nacelle
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ServiceDescription describes a service registered to a container.
+type ServiceDescription struct {
+	// Key is the serialized key of the service.
+	Key string `json:"key"`
+
+	// Type is the name of the concrete type of the service. It is empty for a
+	// service registered by SetFactory which has not yet been constructed.
+	Type string `json:"type"`
+
+	// Factory is true if the service was registered by SetFactory.
+	Factory bool `json:"factory,omitempty"`
+}
+
+// Keys returns the serialized key of each registered service, in sorted order.
+// For a child container, the keys registered to its parent are included.
+func (c *ServiceContainer) Keys() []string {
+	keys := []string{}
+	for _, description := range c.Describe() {
+		keys = append(keys, description.Key)
+	}
+
+	return keys
+}
+
+// Describe returns a description of each registered service, ordered by key. For
+// a child container, the services registered to its parent which have not been
+// overridden are included. Factories are not invoked. This is intended to be
+// printed by a debug endpoint or at startup to show how an application is wired.
+func (c *ServiceContainer) Describe() []ServiceDescription {
+	descriptions := map[string]ServiceDescription{}
+	c.describe(descriptions)
+
+	sorted := make([]ServiceDescription, 0, len(descriptions))
+	for _, description := range descriptions {
+		sorted = append(sorted, description)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	return sorted
+}
+
+// describeFields returns the description of each registered service as log
+// fields, where the value of each key is the service's type name.
+func (c *ServiceContainer) describeFields() Fields {
+	fields := Fields{}
+	for _, description := range c.Describe() {
+		fields[fmt.Sprintf("service.%s", description.Key)] = description.Type
+	}
+
+	return fields
+}
+
+func (c *ServiceContainer) describe(descriptions map[string]ServiceDescription) {
+	if c.parent != nil {
+		c.parent.describe(descriptions)
+	}
+
+	factories := map[interface{}]*lazyService{}
+
+	c.mutex.RLock()
+	for key, service := range c.services {
+		descriptions[serializeKey(key)] = ServiceDescription{
+			Key:  serializeKey(key),
+			Type: getTypeName(service),
+		}
+	}
+
+	for key, lazy := range c.factories {
+		factories[key] = lazy
+	}
+	c.mutex.RUnlock()
+
+	for key, lazy := range factories {
+		descriptions[serializeKey(key)] = ServiceDescription{
+			Key:     serializeKey(key),
+			Type:    lazy.typeName(),
+			Factory: true,
+		}
+	}
+}
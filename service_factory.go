@@ -0,0 +1,283 @@
+package nacelle
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// ServiceFactory constructs a service on demand. See SetFactory.
+	ServiceFactory func(c *ServiceContainer) (interface{}, error)
+
+	// lazyService is a service registered via SetFactory which has not
+	// necessarily been constructed.
+	lazyService struct {
+		container   *ServiceContainer
+		factory     ServiceFactory
+		mutex       sync.Mutex
+		constructed bool
+		service     interface{}
+		call        *factoryCall
+	}
+
+	// factoryCall is an invocation of a factory which is in progress. Concurrent
+	// retrievals of the service wait for the invocation rather than invoking the
+	// factory again.
+	factoryCall struct {
+		key     interface{}
+		owner   uint64
+		done    chan struct{}
+		service interface{}
+		err     error
+
+		// waiting is the invocation which the retrieval that made this invocation
+		// is blocked on, either because a nested factory is being invoked or
+		// because it is waiting for another retrieval of the service.
+		waiting atomic.Pointer[factoryCall]
+	}
+
+	// resolution is a factory which is being invoked.
+	resolution struct {
+		key  interface{}
+		lazy *lazyService
+		call *factoryCall
+	}
+
+	// CycleError is returned when retrieving a service registered via SetFactory
+	// whose factory (transitively) retrieves itself.
+	CycleError struct {
+		// Chain is the key of each service in the cycle, in the order in which
+		// they were retrieved, ending with the first key repeated.
+		Chain []string
+	}
+)
+
+// SetFactory associates a factory with a key. The factory is called on the first
+// retrieval of the key and its result is memoized; an error is not, so the next
+// retrieval calls the factory again. Factories which retrieve each other in a
+// cycle fail with a CycleError (see the README for details).
+func (c *ServiceContainer) SetFactory(key interface{}, factory ServiceFactory) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.frozen {
+		return ErrContainerFrozen
+	}
+
+	if c.registered(key) {
+		return fmt.Errorf("duplicate service key `%s`", serializeKey(key))
+	}
+
+	if c.factories == nil {
+		c.factories = map[interface{}]*lazyService{}
+	}
+
+	c.factories[key] = &lazyService{container: c, factory: factory}
+	c.order = append(c.order, key)
+	return nil
+}
+
+// MustSetFactory calls SetFactory and panics on error.
+func (c *ServiceContainer) MustSetFactory(key interface{}, factory ServiceFactory) {
+	if err := c.SetFactory(key, factory); err != nil {
+		panic(err.Error())
+	}
+}
+
+// registered returns true if a service or factory is registered to the given
+// key. The caller must hold the container's lock.
+func (c *ServiceContainer) registered(key interface{}) bool {
+	if _, ok := c.services[key]; ok {
+		return true
+	}
+
+	_, ok := c.factories[key]
+	return ok
+}
+
+// get constructs the service if it has not yet been constructed, or waits for a
+// concurrent retrieval which is constructing it. The given chain is the sequence
+// of factories being invoked by the retrieval.
+func (s *lazyService) get(key interface{}, chain []resolution) (interface{}, error) {
+	for i, r := range chain {
+		if r.lazy == s {
+			return nil, newCycleError(append(chain[i:], resolution{key: key, lazy: s}))
+		}
+	}
+
+	var parent *factoryCall
+	if len(chain) > 0 {
+		parent = chain[len(chain)-1].call
+	}
+
+	s.mutex.Lock()
+
+	if s.constructed {
+		service := s.service
+		s.mutex.Unlock()
+		return service, nil
+	}
+
+	call := s.call
+	if call == nil {
+		call = &factoryCall{
+			key:   key,
+			owner: goroutineID(),
+			done:  make(chan struct{}),
+			err:   fmt.Errorf("factory for service `%s` panicked", serializeKey(key)),
+		}
+
+		s.call = call
+		s.mutex.Unlock()
+
+		s.invoke(call, chain, parent)
+		return call.service, call.err
+	}
+
+	s.mutex.Unlock()
+
+	// The invocation belongs to this goroutine, so the factory has retrieved
+	// its own key through a path which does not carry the chain (e.g. the
+	// container itself rather than the view).
+	if call.owner == goroutineID() {
+		cycle := append([]resolution{{key: key}}, chain...)
+		return nil, newCycleError(append(cycle, resolution{key: key}))
+	}
+
+	if parent != nil {
+		parent.waiting.Store(call)
+		defer parent.waiting.Store(nil)
+
+		if cycle := waitCycle(call, parent); cycle != nil {
+			return nil, cycle
+		}
+	}
+
+	<-call.done
+	return call.service, call.err
+}
+
+// invoke calls the factory with a view of the container to which it was
+// registered, so that services which override this container's services during
+// an injection are not memoized. The result is stored in the given call, whose
+// waiters are released once the factory returns.
+func (s *lazyService) invoke(call *factoryCall, chain []resolution, parent *factoryCall) {
+	if parent != nil {
+		parent.waiting.Store(call)
+		defer parent.waiting.Store(nil)
+	}
+
+	defer func() {
+		s.mutex.Lock()
+		s.call = nil
+		s.mutex.Unlock()
+
+		close(call.done)
+	}()
+
+	view := &ServiceContainer{
+		services:    map[interface{}]interface{}{},
+		parent:      s.container,
+		interceptor: s.container.interceptor,
+		resolving:   append(append([]resolution{}, chain...), resolution{key: call.key, lazy: s, call: call}),
+		frozen:      true,
+	}
+
+	service, err := s.factory(view)
+	if err != nil {
+		var cycleErr *CycleError
+		if errors.As(err, &cycleErr) {
+			call.service, call.err = nil, cycleErr
+			return
+		}
+
+		call.service, call.err = nil, fmt.Errorf("factory for service `%s` returned an error (%s)", serializeKey(call.key), err.Error())
+		return
+	}
+
+	s.mutex.Lock()
+	s.service = service
+	s.constructed = true
+	s.mutex.Unlock()
+
+	call.service, call.err = service, nil
+}
+
+// waitCycle returns a CycleError if the invocation of the given parent would be
+// waited on (transitively) by the given call, in which case waiting for the call
+// would deadlock. The parent must already be marked as waiting on the call, so
+// that of two retrievals which begin waiting on each other at the same time at
+// least one observes the cycle.
+func waitCycle(call, parent *factoryCall) *CycleError {
+	keys := []string{serializeKey(parent.key)}
+	seen := map[*factoryCall]struct{}{}
+
+	for next := call; next != nil; next = next.waiting.Load() {
+		if _, ok := seen[next]; ok {
+			return nil
+		}
+
+		seen[next] = struct{}{}
+		keys = append(keys, serializeKey(next.key))
+
+		if next == parent {
+			return &CycleError{Chain: keys}
+		}
+	}
+
+	return nil
+}
+
+// goroutineID returns the identifier of the calling goroutine, which is read
+// from the header of its stack trace (e.g. "goroutine 7 [running]:").
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	fields := strings.Fields(string(buf[:n]))
+	if len(fields) < 2 {
+		return 0
+	}
+
+	id, _ := strconv.ParseUint(fields[1], 10, 64)
+	return id
+}
+
+// typeName returns the type name of the constructed service, or an empty string
+// if the service has not yet been constructed.
+func (s *lazyService) typeName() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.constructed {
+		return ""
+	}
+
+	return getTypeName(s.service)
+}
+
+// constructedService returns the service and true if it has been constructed.
+func (s *lazyService) constructedService() (interface{}, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.service, s.constructed
+}
+
+func newCycleError(chain []resolution) *CycleError {
+	keys := []string{}
+	for _, r := range chain {
+		keys = append(keys, serializeKey(r.key))
+	}
+
+	return &CycleError{Chain: keys}
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("circular dependency between service factories (%s)", strings.Join(e.Chain, " -> "))
+}
@@ -0,0 +1,103 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const groupTag = "group"
+
+// SetInGroup registers a service to the given key within the named group. The
+// services of a group can be injected together into a field of type map[string]T
+// tagged with `group:"name"`, where each service registered to the group must be
+// assignable to T. This allows a route table or command dispatcher to be built
+// from handlers which are registered independently. It is an error to register
+// multiple services to the same key of a group, or to register any service after
+// the container has been frozen.
+func (c *ServiceContainer) SetInGroup(group, key string, service interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.frozen {
+		return ErrContainerFrozen
+	}
+
+	if _, ok := c.groups[group][key]; ok {
+		return fmt.Errorf("duplicate key `%s` in service group `%s`", key, group)
+	}
+
+	if c.groups == nil {
+		c.groups = map[string]map[string]interface{}{}
+	}
+
+	if c.groups[group] == nil {
+		c.groups[group] = map[string]interface{}{}
+	}
+
+	c.groups[group][key] = service
+	return nil
+}
+
+// MustSetInGroup calls SetInGroup and panics on error.
+func (c *ServiceContainer) MustSetInGroup(group, key string, service interface{}) {
+	if err := c.SetInGroup(group, key, service); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Group returns a copy of the services registered within the named group. An
+// empty map is returned if no service has been registered to the group. For a
+// child container, the group also contains the services registered within the
+// group of its parent which are not overridden.
+func (c *ServiceContainer) Group(group string) map[string]interface{} {
+	services := c.parentGroup(group)
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for key, service := range c.groups[group] {
+		services[key] = service
+	}
+
+	return services
+}
+
+func (c *ServiceContainer) loadGroupField(fieldType reflect.StructField, fieldValue reflect.Value, group string) error {
+	if !fieldValue.IsValid() {
+		return fmt.Errorf("field '%s' is invalid", fieldType.Name)
+	}
+
+	if !fieldValue.CanSet() {
+		return fmt.Errorf("field '%s' can not be set", fieldType.Name)
+	}
+
+	targetType := fieldValue.Type()
+	if targetType.Kind() != reflect.Map || targetType.Key().Kind() != reflect.String {
+		return fmt.Errorf("field '%s' tagged with a group must be a map with string keys", fieldType.Name)
+	}
+
+	var (
+		elemType = targetType.Elem()
+		target   = reflect.MakeMap(targetType)
+	)
+
+	for key, service := range c.Group(group) {
+		service = c.interceptMember(service)
+		value := reflect.ValueOf(service)
+
+		if !value.IsValid() || !value.Type().ConvertibleTo(elemType) {
+			return fmt.Errorf(
+				"field '%s' cannot be assigned a value of type %s (key `%s` of group `%s`)",
+				fieldType.Name,
+				getTypeName(service),
+				key,
+				group,
+			)
+		}
+
+		target.SetMapIndex(reflect.ValueOf(key).Convert(targetType.Key()), value.Convert(elemType))
+	}
+
+	fieldValue.Set(target)
+	return nil
+}
@@ -0,0 +1,93 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FieldMapping maps the names of the exported fields of a struct to the keys of
+// the services with which they are populated on injection.
+type FieldMapping map[string]interface{}
+
+// RegisterFieldMapping registers a mapping used to inject objects of the same
+// struct type as the given prototype. This allows types which cannot carry
+// `service` tags (e.g. those defined in a third-party package) to be injected
+// as if each mapped field were tagged with the associated service key. Fields
+// of the struct which are tagged are injected as usual in addition to the
+// mapped fields. It is an error to map a field which does not exist or is not
+// exported, or to register multiple mappings for the same type.
+func (c *ServiceContainer) RegisterFieldMapping(prototype interface{}, mapping FieldMapping) error {
+	t := reflect.TypeOf(prototype)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("field mapping target %s is not a struct", getTypeName(prototype))
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.mappings[t]; ok {
+		return fmt.Errorf("duplicate field mapping for %s", t.String())
+	}
+
+	for name := range mapping {
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return fmt.Errorf("field mapping for %s refers to unknown field '%s'", t.String(), name)
+		}
+
+		if field.PkgPath != "" {
+			return fmt.Errorf("field mapping for %s refers to unexported field '%s'", t.String(), name)
+		}
+	}
+
+	if c.mappings == nil {
+		c.mappings = map[reflect.Type]FieldMapping{}
+	}
+
+	c.mappings[t] = mapping
+	return nil
+}
+
+// MustRegisterFieldMapping calls RegisterFieldMapping and panics on error.
+func (c *ServiceContainer) MustRegisterFieldMapping(prototype interface{}, mapping FieldMapping) {
+	if err := c.RegisterFieldMapping(prototype, mapping); err != nil {
+		panic(err.Error())
+	}
+}
+
+// injectMappedFields populates the fields of the given struct value according
+// to the mapping registered for its type, if any, and returns the error of each
+// field which could not be set.
+func (c *ServiceContainer) injectMappedFields(get func(interface{}) (interface{}, error), oi reflect.Value) []error {
+	if c == nil {
+		return nil
+	}
+
+	mapping, ok := c.mapping(oi.Type())
+	if !ok {
+		return nil
+	}
+
+	names := []string{}
+	for name := range mapping {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	errs := []error{}
+	for _, name := range names {
+		fieldType, _ := oi.Type().FieldByName(name)
+
+		if err := loadServiceField(get, fieldType, oi.FieldByName(name), mapping[name], "", ""); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
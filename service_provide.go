@@ -0,0 +1,104 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Provide invokes the given constructor function and registers the result to
+// the given key. The constructor must be a function returning either a single
+// value or a value and an error. Each parameter of the constructor is resolved
+// from the container by type: a parameter is given the unique service whose
+// concrete type is assignable to the parameter type. It is an error for zero
+// or more than one registered service to match a parameter.
+func (c *ServiceContainer) Provide(constructor interface{}, key interface{}) error {
+	fv := reflect.ValueOf(constructor)
+	if fv.Kind() != reflect.Func {
+		return fmt.Errorf("provider for `%s` is not a function", serializeKey(key))
+	}
+
+	ft := fv.Type()
+	if ft.NumOut() == 0 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return fmt.Errorf("provider for `%s` must return a value or a value and an error", serializeKey(key))
+	}
+
+	args, err := c.resolveArgs(ft)
+	if err != nil {
+		return fmt.Errorf("failed to resolve arguments of provider for `%s` (%s)", serializeKey(key), err.Error())
+	}
+
+	results := fv.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		return fmt.Errorf("provider for `%s` returned an error (%s)", serializeKey(key), results[1].Interface().(error).Error())
+	}
+
+	return c.Set(key, results[0].Interface())
+}
+
+// MustProvide calls Provide and panics on error.
+func (c *ServiceContainer) MustProvide(constructor interface{}, key interface{}) {
+	if err := c.Provide(constructor, key); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (c *ServiceContainer) resolveArgs(ft reflect.Type) ([]reflect.Value, error) {
+	args := []reflect.Value{}
+	for i := 0; i < ft.NumIn(); i++ {
+		value, err := c.resolveByType(ft.In(i))
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d: %s", i, err.Error())
+		}
+
+		args = append(args, value)
+	}
+
+	return args, nil
+}
+
+func (c *ServiceContainer) resolveByType(t reflect.Type) (reflect.Value, error) {
+	var (
+		matches  = []string{}
+		match    = reflect.Value{}
+		matchKey interface{}
+	)
+
+	for key, service := range c.registeredSnapshot() {
+		if service == nil || !reflect.TypeOf(service).AssignableTo(t) {
+			continue
+		}
+
+		matches = append(matches, serializeKey(key))
+		match = reflect.ValueOf(service)
+		matchKey = key
+	}
+
+	if len(matches) == 0 {
+		return reflect.Value{}, fmt.Errorf("no service registered with a type assignable to %s", t.String())
+	}
+
+	if len(matches) > 1 {
+		sort.Strings(matches)
+		return reflect.Value{}, fmt.Errorf("multiple services registered with a type assignable to %s (%v)", t.String(), matches)
+	}
+
+	c.markUsed(matchKey)
+
+	if c.interceptor != nil {
+		service, err := c.interceptor.intercept(matchKey, match.Interface(), nil)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if service == nil || !reflect.TypeOf(service).AssignableTo(t) {
+			return reflect.Value{}, fmt.Errorf("substitute for `%s` is not assignable to %s", serializeKey(matchKey), t.String())
+		}
+
+		return reflect.ValueOf(service), nil
+	}
+
+	return match, nil
+}
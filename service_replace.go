@@ -0,0 +1,71 @@
+package nacelle
+
+import "fmt"
+
+// Replace associates a service with a key to which a service (or factory) has
+// already been registered, replacing it. This allows tests and hot-reload flows
+// to swap implementations. Objects into which the previous service has already
+// been injected are not updated. As replacing a service does not change which
+// keys are registered, Replace may be called after the container has been frozen.
+// It is an error to replace a service which has not been registered, or to
+// replace the logger with an object that is not a Logger.
+func (c *ServiceContainer) Replace(key, service interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if key == "logger" {
+		if _, ok := service.(Logger); !ok {
+			return fmt.Errorf("logger instance is not a nacelle.Logger")
+		}
+	}
+
+	if !c.registered(key) {
+		return fmt.Errorf("no service registered to key `%s`", serializeKey(key))
+	}
+
+	delete(c.factories, key)
+	c.services[key] = service
+	return nil
+}
+
+// MustReplace calls Replace and panics on error.
+func (c *ServiceContainer) MustReplace(key, service interface{}) {
+	if err := c.Replace(key, service); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Remove unregisters the service (or factory) registered to the given key. It is
+// an error to remove a service which has not been registered, or to remove any
+// service after the container has been frozen.
+func (c *ServiceContainer) Remove(key interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.frozen {
+		return ErrContainerFrozen
+	}
+
+	if !c.registered(key) {
+		return fmt.Errorf("no service registered to key `%s`", serializeKey(key))
+	}
+
+	delete(c.factories, key)
+	delete(c.services, key)
+
+	for i, registered := range c.order {
+		if registered == key {
+			c.order = append(c.order[:i:i], c.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// MustRemove calls Remove and panics on error.
+func (c *ServiceContainer) MustRemove(key interface{}) {
+	if err := c.Remove(key); err != nil {
+		panic(err.Error())
+	}
+}
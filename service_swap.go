@@ -0,0 +1,193 @@
+package nacelle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// ServiceRebuilder constructs a new instance of a swappable service from the
+	// reloaded config. See NewSwappable.
+	ServiceRebuilder func(config Config) (interface{}, error)
+
+	// Swappable holds a service which is rebuilt when the config it is constructed
+	// from is reloaded (e.g. a log backend or a client with rotating credentials).
+	// A Swappable is registered to the container in place of the service. Consumers
+	// retrieve the current instance with Current on each use, generally from a thin
+	// proxy which implements the service's interface by delegating to the current
+	// instance, so that a swap is observed without re-injection. A replaced instance
+	// is closed (see ServiceContainer.Close) after a grace period so that calls which
+	// are in progress on it can complete.
+	Swappable struct {
+		current     atomic.Value
+		rebuild     ServiceRebuilder
+		configKeys  []interface{}
+		gracePeriod time.Duration
+		retiring    map[*time.Timer]interface{}
+		closed      bool
+		mutex       sync.Mutex
+	}
+
+	swappableService struct {
+		service interface{}
+	}
+)
+
+// NewSwappable creates a swappable service with the given initial instance. The
+// given function is called to construct a new instance whenever any of the given
+// config keys is reloaded by ServiceContainer.HotReload. A replaced instance is
+// closed once the given grace period has elapsed.
+func NewSwappable(service interface{}, rebuild ServiceRebuilder, gracePeriod time.Duration, configKeys ...interface{}) *Swappable {
+	s := &Swappable{
+		rebuild:     rebuild,
+		configKeys:  configKeys,
+		gracePeriod: gracePeriod,
+		retiring:    map[*time.Timer]interface{}{},
+	}
+
+	s.current.Store(swappableService{service})
+	return s
+}
+
+// Current returns the current instance of the service.
+func (s *Swappable) Current() interface{} {
+	return s.current.Load().(swappableService).service
+}
+
+// Swap atomically replaces the current instance of the service and schedules
+// the previous instance to be closed after the grace period.
+func (s *Swappable) Swap(service interface{}) {
+	s.swap(service, emergencyLogger())
+}
+
+// Close closes the current instance of the service and each replaced instance
+// whose grace period has not yet elapsed.
+func (s *Swappable) Close() error {
+	s.mutex.Lock()
+	s.closed = true
+	services := []interface{}{s.Current()}
+	for timer, service := range s.retiring {
+		if timer.Stop() {
+			services = append(services, service)
+		}
+	}
+	s.retiring = map[*time.Timer]interface{}{}
+	s.mutex.Unlock()
+
+	var closeErr error
+	for _, service := range services {
+		if err := callSafely(func() error { return closeService(service) }); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+
+	return closeErr
+}
+
+func (s *Swappable) swap(service interface{}, logger Logger) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		// The new instance would never be closed
+		s.retire(service, logger)
+		return
+	}
+
+	previous := s.Current()
+	s.current.Store(swappableService{service})
+
+	var timer *time.Timer
+	timer = time.AfterFunc(s.gracePeriod, func() {
+		s.mutex.Lock()
+		delete(s.retiring, timer)
+		s.mutex.Unlock()
+
+		s.retire(previous, logger)
+	})
+
+	s.retiring[timer] = previous
+}
+
+func (s *Swappable) retire(service interface{}, logger Logger) {
+	if err := callSafely(func() error { return closeService(service) }); err != nil {
+		logger.Warning("Failed to close replaced service (%s)", err.Error())
+	}
+}
+
+func (s *Swappable) dependsOn(keys []interface{}) bool {
+	for _, configKey := range s.configKeys {
+		for _, key := range keys {
+			if configKey == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HotReload reloads the given config keys and rebuilds each Swappable service
+// registered to the container (or its parents) with Set which depends on one of
+// them. A service whose rebuild fails keeps its current instance. The config must
+// implement ReloadableConfig. If any key fails to reload, no service is rebuilt.
+func (c *ServiceContainer) HotReload(config Config, keys ...interface{}) error {
+	reloadable, ok := config.(ReloadableConfig)
+	if !ok {
+		return ErrConfigNotReloadable
+	}
+
+	if errs := reloadable.Reload(keys...); len(errs) > 0 {
+		messages := []string{}
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+
+		return fmt.Errorf("failed to reload config (%s)", strings.Join(messages, "; "))
+	}
+
+	swappables := map[string]*Swappable{}
+	for key, service := range c.snapshot() {
+		if swappable, ok := service.(*Swappable); ok && swappable.dependsOn(keys) {
+			swappables[serializeKey(key)] = swappable
+		}
+	}
+
+	names := []string{}
+	for name := range swappables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var (
+		logger   = c.GetLogger()
+		messages = []string{}
+	)
+
+	for _, name := range names {
+		swappable := swappables[name]
+
+		var service interface{}
+		if err := callSafely(func() (err error) {
+			service, err = swappable.rebuild(config)
+			return err
+		}); err != nil {
+			messages = append(messages, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+
+		swappable.swap(service, logger)
+		logger.Info("Rebuilt %s after config reload", name)
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("failed to rebuild services (%s)", strings.Join(messages, "; "))
+	}
+
+	return nil
+}
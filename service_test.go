@@ -1,6 +1,14 @@
 package nacelle
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/aphistic/sweet"
 	"github.com/efritz/nacelle/log"
 	. "github.com/onsi/gomega"
@@ -59,6 +67,42 @@ func (s *ServiceSuite) TestInjectNonStruct(t sweet.T) {
 	Expect(err).To(BeNil())
 }
 
+func (s *ServiceSuite) TestInjectNonStructErrors(t sweet.T) {
+	container := NewServiceContainer()
+	value := &TestSimpleProcess{}
+
+	Expect(container.Inject(nil)).To(MatchError("cannot inject services into a value of type nil (expected a struct or a pointer to a struct)"))
+	Expect(container.Inject(&value)).To(MatchError("cannot inject services into a value of type **nacelle.TestSimpleProcess (expected a struct or a pointer to a struct)"))
+	Expect(container.Inject(map[string]int{})).To(MatchError("cannot inject services into a value of type map[string]int (expected a struct or a pointer to a struct)"))
+	Expect(container.Inject((*TestSimpleProcess)(nil))).To(MatchError("cannot inject services into a value of type *nacelle.TestSimpleProcess (expected a struct or a pointer to a struct)"))
+}
+
+func (s *ServiceSuite) TestInjectMapAndFunc(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSet("limits", map[string]int{"a": 1})
+	container.MustSet("lookup", func(key string) (int, bool) { return len(key), true })
+
+	obj := &TestMapAndFuncProcess{}
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.Limits).To(Equal(TestLimits{"a": 1}))
+
+	value, ok := obj.Lookup("foo")
+	Expect(ok).To(BeTrue())
+	Expect(value).To(Equal(3))
+}
+
+func (s *ServiceSuite) TestInjectMapAndFuncMismatch(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSet("limits", map[string]string{"a": "1"})
+	container.MustSet("lookup", func(key string) int { return len(key) })
+
+	Expect(container.Inject(&TestMapAndFuncProcess{})).To(MatchError(
+		"encountered 2 injection errors (" +
+			"field 'Limits' cannot be assigned a value of type map[string]string (expected nacelle.TestLimits); " +
+			"field 'Lookup' cannot be assigned a value of type func(string) int (expected nacelle.TestLookupFunc))",
+	))
+}
+
 func (s *ServiceSuite) TestInjectMissingService(t sweet.T) {
 	container := NewServiceContainer()
 	obj := &TestSimpleProcess{}
@@ -95,6 +139,72 @@ func (s *ServiceSuite) TestInjectOptional(t sweet.T) {
 	Expect(obj.Value.val).To(Equal(42))
 }
 
+func (s *ServiceSuite) TestInjectDefault(t sweet.T) {
+	container := NewServiceContainer()
+	obj := &TestDefaultServiceProcess{}
+	Expect(container.Inject(obj)).To(MatchError("no service registered to key `noop`"))
+
+	constructed := 0
+	container.MustSetFactory("noop", func(c *ServiceContainer) (interface{}, error) {
+		constructed++
+		return &IntWrapper{0}, nil
+	})
+
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.Value.val).To(Equal(0))
+	Expect(constructed).To(Equal(1))
+
+	container.Set("value", &IntWrapper{42})
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.Value.val).To(Equal(42))
+}
+
+func (s *ServiceSuite) TestInjectFallbackErrors(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSetFactory("value", func(c *ServiceContainer) (interface{}, error) {
+		return nil, fmt.Errorf("utoh")
+	})
+
+	container.MustSet("noop", &IntWrapper{0})
+
+	// A registered service which fails to resolve is not replaced by the
+	// default service or left unset
+	Expect(container.Inject(&TestDefaultServiceProcess{})).To(MatchError("factory for service `value` returned an error (utoh)"))
+	Expect(container.Inject(&TestOptionalServiceProcess{})).To(MatchError("factory for service `value` returned an error (utoh)"))
+}
+
+func (s *ServiceSuite) TestInjectPostInject(t sweet.T) {
+	container := NewServiceContainer()
+	obj := &TestPostInjectProcess{}
+	Expect(container.Inject(obj)).To(MatchError("post-inject hook returned an error (value is required)"))
+
+	container.Set("value", &IntWrapper{42})
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.doubled).To(Equal(84))
+}
+
+func (s *ServiceSuite) TestInjectAggregatesErrors(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("b", &FloatWrapper{3.14})
+
+	err := container.Inject(&TestMultipleServiceProcess{})
+	Expect(err).To(MatchError("encountered 3 injection errors (no service registered to key `a`; field 'B' cannot be assigned a value of type *nacelle.FloatWrapper; no service registered to key `c`)"))
+
+	var injectionErr *InjectionError
+	Expect(errors.As(err, &injectionErr)).To(BeTrue())
+	Expect(injectionErr.Errors).To(HaveLen(3))
+}
+
+func (s *ServiceSuite) TestMustInject(t sweet.T) {
+	container := NewServiceContainer()
+	Expect(func() { container.MustInject(&TestSimpleProcess{}) }).To(Panic())
+
+	container.Set("value", &IntWrapper{42})
+	obj := &TestSimpleProcess{}
+	Expect(func() { container.MustInject(obj) }).NotTo(Panic())
+	Expect(obj.Value.val).To(Equal(42))
+}
+
 func (s *ServiceSuite) TestInjectBadOptional(t sweet.T) {
 	container := NewServiceContainer()
 	obj := &TestBadOptionalServiceProcess{}
@@ -117,6 +227,423 @@ func (s *ServiceSuite) TestDuplicateRegistration(t sweet.T) {
 	Expect(err2).To(MatchError("duplicate service key `dup`"))
 }
 
+func (s *ServiceSuite) TestFreeze(t sweet.T) {
+	container := NewServiceContainer()
+	Expect(container.Set("a", &IntWrapper{10})).To(BeNil())
+	Expect(container.Frozen()).To(BeFalse())
+
+	container.Freeze()
+	Expect(container.Frozen()).To(BeTrue())
+	Expect(container.Set("b", &IntWrapper{20})).To(Equal(ErrContainerFrozen))
+
+	value, err := container.Get("a")
+	Expect(err).To(BeNil())
+	Expect(value).To(Equal(&IntWrapper{10}))
+}
+
+func (s *ServiceSuite) TestConcurrentAccess(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		wg        = sync.WaitGroup{}
+	)
+
+	container.Set("value", &IntWrapper{42})
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			container.Set(fmt.Sprintf("service-%d", i), &IntWrapper{i})
+			container.SetInGroup("group", fmt.Sprintf("%d", i), &IntWrapper{i})
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			obj := &TestSimpleProcess{}
+			Expect(container.Inject(obj)).To(BeNil())
+			Expect(obj.Value).To(Equal(&IntWrapper{42}))
+			container.Group("group")
+		}()
+	}
+
+	wg.Wait()
+	Expect(container.Group("group")).To(HaveLen(10))
+
+	for i := 0; i < 10; i++ {
+		Expect(container.MustGet(fmt.Sprintf("service-%d", i))).To(Equal(&IntWrapper{i}))
+	}
+}
+
+func (s *ServiceSuite) TestSetFactory(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		calls     = 0
+	)
+
+	Expect(container.SetFactory("value", func(c *ServiceContainer) (interface{}, error) {
+		calls++
+		return &IntWrapper{42}, nil
+	})).To(BeNil())
+
+	Expect(calls).To(Equal(0))
+
+	obj := &TestSimpleProcess{}
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.Value).To(Equal(&IntWrapper{42}))
+	Expect(calls).To(Equal(1))
+
+	// Memoized
+	value, err := container.Get("value")
+	Expect(err).To(BeNil())
+	Expect(value).To(BeIdenticalTo(obj.Value))
+	Expect(calls).To(Equal(1))
+}
+
+func (s *ServiceSuite) TestSetFactoryError(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		calls     = 0
+	)
+
+	container.MustSetFactory("value", func(c *ServiceContainer) (interface{}, error) {
+		if calls++; calls == 1 {
+			return nil, fmt.Errorf("utoh")
+		}
+
+		return &IntWrapper{42}, nil
+	})
+
+	_, err := container.Get("value")
+	Expect(err).To(MatchError("factory for service `value` returned an error (utoh)"))
+
+	// Retried on the next retrieval
+	Expect(container.MustGet("value")).To(Equal(&IntWrapper{42}))
+	Expect(calls).To(Equal(2))
+}
+
+func (s *ServiceSuite) TestSetFactoryCycle(t sweet.T) {
+	container := NewServiceContainer()
+
+	for key, dependency := range map[string]string{"a": "b", "b": "c", "c": "a"} {
+		dependency := dependency
+
+		container.MustSetFactory(key, func(c *ServiceContainer) (interface{}, error) {
+			if _, err := c.Get(dependency); err != nil {
+				return nil, err
+			}
+
+			return &IntWrapper{}, nil
+		})
+	}
+
+	_, err := container.Get("b")
+	Expect(err).To(MatchError("circular dependency between service factories (b -> c -> a -> b)"))
+
+	var cycleErr *CycleError
+	Expect(errors.As(err, &cycleErr)).To(BeTrue())
+	Expect(cycleErr.Chain).To(Equal([]string{"b", "c", "a", "b"}))
+
+	container.MustSetFactory("self", func(c *ServiceContainer) (interface{}, error) { return c.Get("self") })
+	_, err = container.Get("self")
+	Expect(err).To(MatchError("circular dependency between service factories (self -> self)"))
+}
+
+func (s *ServiceSuite) TestSetFactoryCycleThroughContainer(t sweet.T) {
+	container := NewServiceContainer()
+
+	// Factories which close over the container rather than using the view
+	container.MustSetFactory("self", func(c *ServiceContainer) (interface{}, error) { return container.Get("self") })
+	container.MustSetFactory("a", func(c *ServiceContainer) (interface{}, error) { return container.Get("b") })
+	container.MustSetFactory("b", func(c *ServiceContainer) (interface{}, error) { return c.Get("a") })
+
+	_, err := container.Get("self")
+	Expect(err).To(MatchError("circular dependency between service factories (self -> self)"))
+
+	_, err = container.Get("a")
+	Expect(err).To(MatchError("circular dependency between service factories (a -> b -> a)"))
+}
+
+func (s *ServiceSuite) TestSetFactoryConcurrentCycle(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		started   = make(chan struct{}, 2)
+		proceed   = make(chan struct{})
+		errs      = make(chan error, 2)
+	)
+
+	for key, dependency := range map[string]string{"a": "b", "b": "a"} {
+		dependency := dependency
+
+		container.MustSetFactory(key, func(c *ServiceContainer) (interface{}, error) {
+			started <- struct{}{}
+			<-proceed
+
+			return c.Get(dependency)
+		})
+	}
+
+	for _, key := range []string{"a", "b"} {
+		go func(key string) {
+			_, err := container.Get(key)
+			errs <- err
+		}(key)
+	}
+
+	// Both factories are in progress before either retrieves the other
+	Eventually(started).Should(Receive())
+	Eventually(started).Should(Receive())
+	close(proceed)
+
+	for i := 0; i < 2; i++ {
+		var err error
+		Eventually(errs).Should(Receive(&err))
+
+		var cycleErr *CycleError
+		Expect(errors.As(err, &cycleErr)).To(BeTrue())
+		Expect(cycleErr.Chain).To(Or(
+			Equal([]string{"a", "b", "a"}),
+			Equal([]string{"b", "a", "b"}),
+		))
+	}
+}
+
+func (s *ServiceSuite) TestSetFactoryConcurrentRetrieval(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		started   = make(chan struct{})
+		proceed   = make(chan struct{})
+		results   = make(chan interface{}, 2)
+		calls     int32
+	)
+
+	container.MustSetFactory("value", func(c *ServiceContainer) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		<-proceed
+
+		return &IntWrapper{42}, nil
+	})
+
+	retrieve := func() { results <- container.MustGet("value") }
+
+	go retrieve()
+	Eventually(started).Should(BeClosed())
+	go retrieve()
+
+	// The second retrieval waits for the factory which is in progress
+	Consistently(results).ShouldNot(Receive())
+	close(proceed)
+
+	for i := 0; i < 2; i++ {
+		Eventually(results).Should(Receive(Equal(&IntWrapper{42})))
+	}
+
+	Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+}
+
+func (s *ServiceSuite) TestSetFactoryChain(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSetFactory("a", func(c *ServiceContainer) (interface{}, error) {
+		b, err := Resolve[*IntWrapper](c, "b")
+		if err != nil {
+			return nil, err
+		}
+
+		return &IntWrapper{b.val + 1}, nil
+	})
+
+	container.MustSetFactory("b", func(c *ServiceContainer) (interface{}, error) {
+		return &IntWrapper{1}, nil
+	})
+
+	// Dependencies which are not cyclic resolve normally
+	Expect(container.MustGet("a")).To(Equal(&IntWrapper{2}))
+	Expect(container.MustGet("b")).To(Equal(&IntWrapper{1}))
+}
+
+func (s *ServiceSuite) TestSetFactoryDuplicate(t sweet.T) {
+	container := NewServiceContainer()
+	factory := func(c *ServiceContainer) (interface{}, error) { return nil, nil }
+
+	container.Set("a", &IntWrapper{10})
+	container.SetFactory("b", factory)
+
+	Expect(container.SetFactory("a", factory)).To(MatchError("duplicate service key `a`"))
+	Expect(container.SetFactory("b", factory)).To(MatchError("duplicate service key `b`"))
+	Expect(container.Set("b", &IntWrapper{10})).To(MatchError("duplicate service key `b`"))
+
+	container.Freeze()
+	Expect(container.SetFactory("c", factory)).To(Equal(ErrContainerFrozen))
+}
+
+func (s *ServiceSuite) TestProvide(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("a", &IntWrapper{10})
+	container.Set("b", &FloatWrapper{3.14})
+
+	err := container.Provide(func(i *IntWrapper, f *FloatWrapper) (*IntWrapper, error) {
+		return &IntWrapper{i.val + int(f.val)}, nil
+	}, "c")
+
+	Expect(err).To(BeNil())
+	Expect(container.MustGet("c")).To(Equal(&IntWrapper{13}))
+}
+
+func (s *ServiceSuite) TestProvideError(t sweet.T) {
+	container := NewServiceContainer()
+
+	err := container.Provide(func() (*IntWrapper, error) {
+		return nil, fmt.Errorf("utoh")
+	}, "a")
+
+	Expect(err).To(MatchError("provider for `a` returned an error (utoh)"))
+}
+
+func (s *ServiceSuite) TestProvideUnresolvable(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("a", &IntWrapper{10})
+	container.Set("b", &IntWrapper{20})
+
+	err := container.Provide(func(f *FloatWrapper) *IntWrapper { return nil }, "c")
+	Expect(err).To(MatchError("failed to resolve arguments of provider for `c` (parameter 0: no service registered with a type assignable to *nacelle.FloatWrapper)"))
+
+	err = container.Provide(func(i *IntWrapper) *IntWrapper { return i }, "c")
+	Expect(err).To(MatchError("failed to resolve arguments of provider for `c` (parameter 0: multiple services registered with a type assignable to *nacelle.IntWrapper ([a b]))"))
+}
+
+func (s *ServiceSuite) TestProvideBadSignature(t sweet.T) {
+	container := NewServiceContainer()
+	Expect(container.Provide(&IntWrapper{}, "a")).To(MatchError("provider for `a` is not a function"))
+	Expect(container.Provide(func() {}, "a")).To(MatchError("provider for `a` must return a value or a value and an error"))
+	Expect(container.Provide(func() (int, int) { return 0, 0 }, "a")).To(MatchError("provider for `a` must return a value or a value and an error"))
+}
+
+func (s *ServiceSuite) TestReplace(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSet("a", &IntWrapper{1})
+	container.MustSetFactory("b", func(c *ServiceContainer) (interface{}, error) { return &IntWrapper{2}, nil })
+	container.Freeze()
+
+	Expect(container.Replace("a", &IntWrapper{3})).To(BeNil())
+	Expect(container.Replace("b", &IntWrapper{4})).To(BeNil())
+	Expect(container.MustGet("a")).To(Equal(&IntWrapper{3}))
+	Expect(container.MustGet("b")).To(Equal(&IntWrapper{4}))
+
+	Expect(container.Replace("c", &IntWrapper{5})).To(MatchError("no service registered to key `c`"))
+	Expect(container.Replace("logger", &IntWrapper{5})).To(MatchError("logger instance is not a nacelle.Logger"))
+}
+
+func (s *ServiceSuite) TestRemove(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSet("a", &IntWrapper{1})
+	container.MustSetFactory("b", func(c *ServiceContainer) (interface{}, error) { return &IntWrapper{2}, nil })
+
+	Expect(container.Remove("a")).To(BeNil())
+	Expect(container.Remove("b")).To(BeNil())
+	Expect(container.Remove("c")).To(MatchError("no service registered to key `c`"))
+
+	_, err := container.Get("a")
+	Expect(err).To(MatchError("no service registered to key `a`"))
+	_, err = container.Get("b")
+	Expect(err).To(MatchError("no service registered to key `b`"))
+
+	// The key can be registered again
+	Expect(container.Set("a", &IntWrapper{3})).To(BeNil())
+	Expect(container.MustGet("a")).To(Equal(&IntWrapper{3}))
+
+	container.Freeze()
+	Expect(container.Remove("a")).To(Equal(ErrContainerFrozen))
+}
+
+func (s *ServiceSuite) TestClose(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		closed    = []string{}
+		pool      = &closerService{close: func() error { closed = append(closed, "pool"); return nil }}
+	)
+
+	container.MustSet("pool", pool)
+	container.MustSet("pool-alias", pool)
+	container.MustSet("value", &IntWrapper{42})
+	container.MustSet("client", &shutdownerService{shutdown: func(ctx context.Context) error {
+		closed = append(closed, "client")
+		return errors.New("utoh")
+	}})
+
+	container.MustSetFactory("cache", func(c *ServiceContainer) (interface{}, error) {
+		return &closerService{close: func() error { closed = append(closed, "cache"); return nil }}, nil
+	})
+
+	container.MustSetFactory("unused", func(c *ServiceContainer) (interface{}, error) {
+		return &closerService{close: func() error { closed = append(closed, "unused"); return nil }}, nil
+	})
+
+	container.MustGet("cache")
+
+	// Closed in reverse order of registration, skipping unconstructed factories
+	Expect(container.Close()).To(MatchError("failed to close services (client: utoh)"))
+	Expect(closed).To(Equal([]string{"cache", "client", "pool"}))
+
+	Expect(container.Close()).To(BeNil())
+	Expect(closed).To(HaveLen(3))
+}
+
+func (s *ServiceSuite) TestDescribe(t sweet.T) {
+	parent := NewServiceContainer()
+	parent.MustSet("b", &IntWrapper{1})
+	parent.MustSet("c", &IntWrapper{2})
+	parent.MustSetFactory("a", func(c *ServiceContainer) (interface{}, error) { return &FloatWrapper{3.14}, nil })
+
+	child := parent.Child()
+	child.MustSet("c", &FloatWrapper{2.71})
+
+	Expect(child.Keys()).To(Equal([]string{"a", "b", "c", "container"}))
+	Expect(child.Describe()).To(Equal([]ServiceDescription{
+		{Key: "a", Type: "", Factory: true},
+		{Key: "b", Type: "*nacelle.IntWrapper"},
+		{Key: "c", Type: "*nacelle.FloatWrapper"},
+		{Key: "container", Type: "*nacelle.ServiceContainer"},
+	}))
+
+	parent.MustGet("a")
+	Expect(parent.Describe()[0]).To(Equal(ServiceDescription{Key: "a", Type: "*nacelle.FloatWrapper", Factory: true}))
+}
+
+func (s *ServiceSuite) TestCall(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("value", &IntWrapper{10})
+	container.Set("b", &FloatWrapper{3.14})
+
+	values, err := container.Call(func(deps TestSimpleProcess, f *FloatWrapper) (*IntWrapper, string, error) {
+		return &IntWrapper{deps.Value.val + int(f.val)}, "ok", nil
+	})
+
+	Expect(err).To(BeNil())
+	Expect(values).To(Equal([]interface{}{&IntWrapper{13}, "ok"}))
+
+	values, err = container.Call(func() {})
+	Expect(err).To(BeNil())
+	Expect(values).To(BeEmpty())
+}
+
+func (s *ServiceSuite) TestCallErrors(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("a", &IntWrapper{10})
+
+	_, err := container.Call(&IntWrapper{})
+	Expect(err).To(MatchError("value of type *nacelle.IntWrapper is not a function"))
+
+	_, err = container.Call(func(f *FloatWrapper) {})
+	Expect(err).To(MatchError("failed to resolve arguments (parameter 0: no service registered with a type assignable to *nacelle.FloatWrapper)"))
+
+	_, err = container.Call(func(deps TestSimpleProcess) {})
+	Expect(err).To(MatchError("failed to resolve arguments (parameter 0: no service registered to key `value`)"))
+
+	_, err = container.Call(func(i *IntWrapper) (*IntWrapper, error) { return nil, fmt.Errorf("utoh") })
+	Expect(err).To(MatchError("utoh"))
+}
+
 func (s *ServiceSuite) TestGetUnregisteredKey(t sweet.T) {
 	container := NewServiceContainer()
 	_, err := container.Get("unregistered")
@@ -137,6 +664,221 @@ func (s *ServiceSuite) TestMustGetPanics(t sweet.T) {
 	}).To(Panic())
 }
 
+func (s *ServiceSuite) TestResolve(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("a", &IntWrapper{10})
+
+	value, err := Resolve[*IntWrapper](container, "a")
+	Expect(err).To(BeNil())
+	Expect(value).To(Equal(&IntWrapper{10}))
+
+	_, err = Resolve[*FloatWrapper](container, "a")
+	Expect(err).To(MatchError("service `a` has unexpected type *nacelle.IntWrapper"))
+
+	_, err = Resolve[*IntWrapper](container, "b")
+	Expect(err).To(MatchError("no service registered to key `b`"))
+
+	Expect(MustResolve[*IntWrapper](container, "a")).To(Equal(&IntWrapper{10}))
+}
+
+func (s *ServiceSuite) TestResolveOptional(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("a", &IntWrapper{10})
+	container.MustSetFactory("failing", func(c *ServiceContainer) (interface{}, error) {
+		return nil, fmt.Errorf("utoh")
+	})
+
+	value, err := ResolveOptional[*IntWrapper](container, "a")
+	Expect(err).To(BeNil())
+	Expect(value).To(Equal(&IntWrapper{10}))
+
+	value, err = ResolveOptional[*IntWrapper](container, "b")
+	Expect(err).To(BeNil())
+	Expect(value).To(BeNil())
+
+	// A registered service which cannot be retrieved is not treated as missing
+	_, err = ResolveOptional[*IntWrapper](container, "failing")
+	Expect(err).To(MatchError("factory for service `failing` returned an error (utoh)"))
+
+	container.MustSetFactory("self", func(c *ServiceContainer) (interface{}, error) {
+		return ResolveOptional[*IntWrapper](c, "self")
+	})
+
+	_, err = ResolveOptional[*IntWrapper](container, "self")
+	Expect(err).To(MatchError("circular dependency between service factories (self -> self)"))
+}
+
+func (s *ServiceSuite) TestMustResolvePanics(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("a", &IntWrapper{10})
+
+	Expect(func() { MustResolve[*IntWrapper](container, "b") }).To(Panic())
+	Expect(func() { MustResolve[*FloatWrapper](container, "a") }).To(Panic())
+}
+
+func (s *ServiceSuite) TestInjectWirer(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("value", &IntWrapper{42})
+
+	obj := &TestWiredProcess{}
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.Value.val).To(Equal(42))
+	Expect(obj.Other).To(BeNil())
+}
+
+func (s *ServiceSuite) TestInjectWirerOverrides(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("value", &IntWrapper{42})
+
+	obj := &TestWiredProcess{}
+	Expect(container.injectWithOverrides(obj, map[interface{}]interface{}{"value": &IntWrapper{43}})).To(BeNil())
+	Expect(obj.Value.val).To(Equal(43))
+}
+
+func (s *ServiceSuite) TestInjectWirerBadType(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("value", &FloatWrapper{3.14})
+
+	obj := &TestWiredProcess{}
+	Expect(container.Inject(obj)).To(MatchError("service `value` has unexpected type *nacelle.FloatWrapper"))
+}
+
+func (s *ServiceSuite) TestInjectWirerMissing(t sweet.T) {
+	obj := &TestWiredProcess{}
+	Expect(NewServiceContainer().Inject(obj)).To(MatchError("no service registered to key `value`"))
+}
+
+func (s *ServiceSuite) TestInjectFieldMapping(t sweet.T) {
+	container := NewServiceContainer()
+	container.Set("value", &IntWrapper{42})
+	container.Set("float", &FloatWrapper{3.14})
+
+	Expect(container.RegisterFieldMapping(&TestThirdPartyProcess{}, FieldMapping{
+		"Value": "value",
+		"Float": "float",
+	})).To(BeNil())
+
+	obj := &TestThirdPartyProcess{}
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.Value.val).To(Equal(42))
+	Expect(obj.Float.val).To(Equal(3.14))
+}
+
+func (s *ServiceSuite) TestInjectFieldMappingMissing(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustRegisterFieldMapping(TestThirdPartyProcess{}, FieldMapping{"Value": "value"})
+	Expect(container.Inject(&TestThirdPartyProcess{})).To(MatchError("no service registered to key `value`"))
+}
+
+func (s *ServiceSuite) TestRegisterFieldMappingErrors(t sweet.T) {
+	container := NewServiceContainer()
+	Expect(container.RegisterFieldMapping(3, FieldMapping{})).To(MatchError("field mapping target int is not a struct"))
+	Expect(container.RegisterFieldMapping(&TestThirdPartyProcess{}, FieldMapping{"Missing": "value"})).To(MatchError("field mapping for nacelle.TestThirdPartyProcess refers to unknown field 'Missing'"))
+	Expect(container.RegisterFieldMapping(&TestThirdPartyProcess{}, FieldMapping{"internal": "value"})).To(MatchError("field mapping for nacelle.TestThirdPartyProcess refers to unexported field 'internal'"))
+	Expect(container.RegisterFieldMapping(&TestThirdPartyProcess{}, FieldMapping{"Value": "value"})).To(BeNil())
+	Expect(container.RegisterFieldMapping(&TestThirdPartyProcess{}, FieldMapping{"Float": "float"})).To(MatchError("duplicate field mapping for nacelle.TestThirdPartyProcess"))
+}
+
+func (s *ServiceSuite) TestInjectGroup(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSetInGroup("handlers", "a", &IntWrapper{1})
+	container.MustSetInGroup("handlers", "b", &IntWrapper{2})
+	container.MustSetInGroup("others", "c", &IntWrapper{3})
+
+	obj := &TestGroupProcess{}
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(obj.Handlers).To(HaveLen(2))
+	Expect(obj.Handlers["a"].val).To(Equal(1))
+	Expect(obj.Handlers["b"].val).To(Equal(2))
+}
+
+func (s *ServiceSuite) TestInjectEmptyGroup(t sweet.T) {
+	obj := &TestGroupProcess{}
+	Expect(NewServiceContainer().Inject(obj)).To(BeNil())
+	Expect(obj.Handlers).To(BeEmpty())
+	Expect(obj.Handlers).NotTo(BeNil())
+}
+
+func (s *ServiceSuite) TestInjectGroupBadType(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSetInGroup("handlers", "a", &FloatWrapper{3.14})
+	Expect(container.Inject(&TestGroupProcess{})).To(MatchError("field 'Handlers' cannot be assigned a value of type *nacelle.FloatWrapper (key `a` of group `handlers`)"))
+	Expect(container.Inject(&TestBadGroupProcess{})).To(MatchError("field 'Handlers' tagged with a group must be a map with string keys"))
+}
+
+func (s *ServiceSuite) TestSetInGroupDuplicate(t sweet.T) {
+	container := NewServiceContainer()
+	Expect(container.SetInGroup("handlers", "a", &IntWrapper{1})).To(BeNil())
+	Expect(container.SetInGroup("handlers", "a", &IntWrapper{2})).To(MatchError("duplicate key `a` in service group `handlers`"))
+
+	container.Freeze()
+	Expect(container.SetInGroup("handlers", "b", &IntWrapper{2})).To(Equal(ErrContainerFrozen))
+}
+
+func (s *ServiceSuite) TestInjectBinding(t sweet.T) {
+	parent := NewServiceContainer()
+	parent.MustAdd("middleware", &IntWrapper{1})
+	parent.MustAdd("middleware", &IntWrapper{2})
+	parent.MustAdd("others", &IntWrapper{3})
+
+	child := parent.Child()
+	child.MustAdd("middleware", &IntWrapper{4})
+
+	obj := &TestBindingProcess{}
+	Expect(child.Inject(obj)).To(BeNil())
+	Expect(obj.Middleware).To(Equal([]*IntWrapper{{1}, {2}, {4}}))
+
+	Expect(parent.Inject(obj)).To(BeNil())
+	Expect(obj.Middleware).To(Equal([]*IntWrapper{{1}, {2}}))
+
+	Expect(NewServiceContainer().Inject(obj)).To(BeNil())
+	Expect(obj.Middleware).To(BeEmpty())
+	Expect(obj.Middleware).NotTo(BeNil())
+}
+
+func (s *ServiceSuite) TestInjectBindingErrors(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustAdd("middleware", &IntWrapper{1})
+	container.MustAdd("middleware", &FloatWrapper{3.14})
+	Expect(container.Inject(&TestBindingProcess{})).To(MatchError("field 'Middleware' cannot be assigned a value of type *nacelle.FloatWrapper (index 1 of group `middleware`)"))
+	Expect(container.Inject(&TestBadBindingProcess{})).To(MatchError("field 'Middleware' tagged with type group must be a slice"))
+
+	container.Freeze()
+	Expect(container.Add("middleware", &IntWrapper{2})).To(Equal(ErrContainerFrozen))
+}
+
+func (s *ServiceSuite) TestChild(t sweet.T) {
+	parent := NewServiceContainer()
+	parent.MustSet("a", &IntWrapper{1})
+	parent.MustSet("value", &IntWrapper{2})
+	parent.MustSetInGroup("handlers", "a", &IntWrapper{1})
+	parent.Freeze()
+
+	child := parent.Child()
+	Expect(child.Parent()).To(BeIdenticalTo(parent))
+	Expect(child.Set("value", &IntWrapper{3})).To(BeNil())
+	Expect(child.Set("c", &IntWrapper{4})).To(BeNil())
+	Expect(child.SetInGroup("handlers", "b", &IntWrapper{5})).To(BeNil())
+
+	// Local services take precedence over the parent's
+	Expect(child.MustGet("a")).To(Equal(&IntWrapper{1}))
+	Expect(child.MustGet("value")).To(Equal(&IntWrapper{3}))
+	Expect(child.MustGet("c")).To(Equal(&IntWrapper{4}))
+	Expect(child.MustGet("container")).To(BeIdenticalTo(child))
+	Expect(child.Group("handlers")).To(HaveLen(2))
+
+	// The parent is not mutated
+	Expect(parent.MustGet("value")).To(Equal(&IntWrapper{2}))
+	Expect(parent.Group("handlers")).To(HaveLen(1))
+
+	_, err := parent.Get("c")
+	Expect(err).To(MatchError("no service registered to key `c`"))
+
+	obj := &TestSimpleProcess{}
+	Expect(child.Inject(obj)).To(BeNil())
+	Expect(obj.Value).To(Equal(&IntWrapper{3}))
+}
+
 //
 // Processes
 
@@ -153,6 +895,17 @@ type (
 		Value *IntWrapper `service:"value"`
 	}
 
+	TestPostInjectProcess struct {
+		Value   *IntWrapper `service:"value" optional:"true"`
+		doubled int
+	}
+
+	TestMultipleServiceProcess struct {
+		A *IntWrapper `service:"a"`
+		B *IntWrapper `service:"b"`
+		C *IntWrapper `service:"c"`
+	}
+
 	TestUnsettableService struct {
 		value *IntWrapper `service:"value"`
 	}
@@ -161,11 +914,235 @@ type (
 		Value IntWrapper `service:"value"`
 	}
 
+	TestLimits     map[string]int
+	TestLookupFunc func(key string) (int, bool)
+
+	TestMapAndFuncProcess struct {
+		Limits TestLimits     `service:"limits"`
+		Lookup TestLookupFunc `service:"lookup"`
+	}
+
 	TestOptionalServiceProcess struct {
 		Value *IntWrapper `service:"value" optional:"true"`
 	}
 
+	TestDefaultServiceProcess struct {
+		Value *IntWrapper `service:"value" default:"noop"`
+	}
+
 	TestBadOptionalServiceProcess struct {
 		Value *IntWrapper `service:"value" optional:"yup"`
 	}
+
+	TestGroupProcess struct {
+		Handlers map[string]*IntWrapper `group:"handlers"`
+	}
+
+	TestBadGroupProcess struct {
+		Handlers []*IntWrapper `group:"handlers"`
+	}
+
+	TestBindingProcess struct {
+		Middleware []*IntWrapper `service:"middleware" type:"group"`
+	}
+
+	TestBadBindingProcess struct {
+		Middleware map[string]*IntWrapper `service:"middleware" type:"group"`
+	}
+
+	TestThirdPartyProcess struct {
+		Value    *IntWrapper
+		Float    *FloatWrapper
+		internal *IntWrapper
+	}
+
+	TestWiredProcess struct {
+		Value *IntWrapper
+		Other *FloatWrapper
+	}
 )
+
+func (p *TestWiredProcess) Wire(c *ServiceContainer) (err error) {
+	if p.Value, err = Resolve[*IntWrapper](c, "value"); err != nil {
+		return err
+	}
+
+	p.Other, err = ResolveOptional[*FloatWrapper](c, "other")
+	return err
+}
+
+func (p *TestPostInjectProcess) PostInject() error {
+	if p.Value == nil {
+		return fmt.Errorf("value is required")
+	}
+
+	p.doubled = p.Value.val * 2
+	return nil
+}
+
+type closerService struct {
+	close func() error
+}
+
+func (s *closerService) Close() error { return s.close() }
+
+type shutdownerService struct {
+	shutdown func(ctx context.Context) error
+}
+
+func (s *shutdownerService) Shutdown(ctx context.Context) error { return s.shutdown(ctx) }
+
+func (s *ServiceSuite) TestDecorate(t sweet.T) {
+	container := NewServiceContainer()
+	container.MustSet("value", &IntWrapper{2})
+
+	double := func(existing interface{}) interface{} {
+		return &IntWrapper{existing.(*IntWrapper).val * 2}
+	}
+
+	Expect(container.Decorate("value", double)).To(BeNil())
+	Expect(container.Decorate("value", double)).To(BeNil())
+	Expect(container.MustGet("value")).To(Equal(&IntWrapper{8}))
+}
+
+func (s *ServiceSuite) TestDecorateFactory(t sweet.T) {
+	calls := 0
+	container := NewServiceContainer()
+	container.MustSetFactory("value", func(c *ServiceContainer) (interface{}, error) {
+		calls++
+		return &IntWrapper{3}, nil
+	})
+
+	Expect(container.Decorate("value", func(existing interface{}) interface{} {
+		return &IntWrapper{existing.(*IntWrapper).val + 1}
+	})).To(BeNil())
+
+	Expect(calls).To(Equal(0))
+	Expect(container.MustGet("value")).To(Equal(&IntWrapper{4}))
+
+	// Decorating a constructed service does not call the factory again
+	Expect(container.Decorate("value", func(existing interface{}) interface{} {
+		return &IntWrapper{existing.(*IntWrapper).val * 10}
+	})).To(BeNil())
+
+	Expect(container.MustGet("value")).To(Equal(&IntWrapper{40}))
+	Expect(calls).To(Equal(1))
+}
+
+func (s *ServiceSuite) TestDecorateErrors(t sweet.T) {
+	identity := func(existing interface{}) interface{} { return existing }
+
+	container := NewServiceContainer()
+	container.MustSet("logger", log.NewNilLogger())
+	Expect(container.Decorate("value", identity)).To(MatchError("no service registered to key `value`"))
+	Expect(container.Decorate("logger", func(existing interface{}) interface{} {
+		return &IntWrapper{1}
+	})).To(MatchError("logger instance is not a nacelle.Logger"))
+
+	container.MustSet("value", &IntWrapper{1})
+	container.Freeze()
+	Expect(container.Decorate("value", identity)).To(Equal(ErrContainerFrozen))
+	Expect(func() { container.MustDecorate("value", identity) }).To(Panic())
+}
+
+func (s *ServiceSuite) TestHotReload(t sweet.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	config := NewEnvConfig("app")
+	Expect(config.Register("simple", &TestSimpleConfig{})).To(BeNil())
+	Expect(config.Register("other", &TestSimpleConfig{})).To(BeNil())
+
+	os.Setenv("APP_X", "foo")
+	Expect(config.Load()).To(BeEmpty())
+
+	closed := make(chan string, 2)
+	build := func(config Config) (interface{}, error) {
+		c := &TestSimpleConfig{}
+		if err := config.Fetch("simple", c); err != nil {
+			return nil, err
+		}
+
+		return &closerService{close: func() error { closed <- c.X; return nil }}, nil
+	}
+
+	initial, err := build(config)
+	Expect(err).To(BeNil())
+
+	swappable := NewSwappable(initial, build, time.Millisecond*10, "simple")
+	container := NewServiceContainer()
+	container.MustSet("logger", log.NewNilLogger())
+	container.MustSet("client", swappable)
+
+	// Reloading an unrelated key does not rebuild the service
+	Expect(container.HotReload(config, "other")).To(BeNil())
+	Expect(swappable.Current()).To(BeIdenticalTo(initial))
+
+	os.Setenv("APP_X", "bar")
+	Expect(container.HotReload(config, "simple")).To(BeNil())
+	Expect(swappable.Current()).NotTo(BeIdenticalTo(initial))
+
+	// The previous instance is closed after the grace period
+	Consistently(closed, time.Millisecond*5).ShouldNot(Receive())
+	Eventually(closed).Should(Receive(Equal("foo")))
+
+	Expect(container.Close()).To(BeNil())
+	Eventually(closed).Should(Receive(Equal("bar")))
+}
+
+func (s *ServiceSuite) TestHotReloadErrors(t sweet.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	config := NewEnvConfig("app")
+	Expect(config.Register("simple", &TestSimpleConfig{})).To(BeNil())
+	Expect(config.Load()).To(BeEmpty())
+
+	swappable := NewSwappable(&IntWrapper{1}, func(config Config) (interface{}, error) {
+		return nil, fmt.Errorf("utoh")
+	}, 0, "simple")
+
+	container := NewServiceContainer()
+	container.MustSet("logger", log.NewNilLogger())
+	container.MustSet("client", swappable)
+
+	Expect(container.HotReload(config, "missing")).To(MatchError("failed to reload config (unregistered config key `missing`)"))
+	Expect(container.HotReload(config, "simple")).To(MatchError("failed to rebuild services (client: utoh)"))
+	Expect(swappable.Current()).To(Equal(&IntWrapper{1}))
+	Expect(container.HotReload(&nonReloadableConfig{config}, "simple")).To(Equal(ErrConfigNotReloadable))
+	Expect(container.HotReload(NewPrefixedConfig(&nonReloadableConfig{config}, "a"), "simple")).To(MatchError("failed to reload config (config does not support reloading)"))
+}
+
+type nonReloadableConfig struct {
+	Config
+}
+
+func (s *ServiceSuite) TestUnusedServices(t sweet.T) {
+	parent := NewServiceContainer()
+	parent.MustSet("logger", log.NewNilLogger())
+	parent.ignoreRegisteredServices()
+
+	parent.MustSet("a", &IntWrapper{1})
+	parent.MustSet("value", &IntWrapper{2})
+	parent.MustSet("float", &FloatWrapper{3.14})
+	parent.MustSet("unused", &IntWrapper{4})
+	parent.MustSet("noop", &IntWrapper{0})
+	parent.MustSetFactory("lazy", func(c *ServiceContainer) (interface{}, error) { return &IntWrapper{5}, nil })
+
+	// Factories are not reported until they are constructed
+	Expect(parent.UnusedServices()).To(Equal([]string{"a", "float", "noop", "unused", "value"}))
+
+	child := parent.Child()
+	child.MustSet("b", &IntWrapper{6})
+
+	// Retrieved via the child, by a wirer, and by type
+	Expect(child.MustGet("a")).To(Equal(&IntWrapper{1}))
+	Expect(parent.injectWithOverrides(&TestWiredProcess{}, map[interface{}]interface{}{"progress": nil})).To(BeNil())
+	Expect(parent.Provide(func(f *FloatWrapper) *IntWrapper { return &IntWrapper{7} }, "provided")).To(BeNil())
+
+	// The default of an injected field is referenced even if unneeded
+	Expect(parent.Inject(&TestDefaultServiceProcess{})).To(BeNil())
+
+	Expect(parent.UnusedServices()).To(Equal([]string{"provided", "unused"}))
+	Expect(child.UnusedServices()).To(Equal([]string{"b"}))
+}
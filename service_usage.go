@@ -0,0 +1,109 @@
+package nacelle
+
+import (
+	"sort"
+	"sync"
+)
+
+// serviceUsage records which of the services registered to a container have
+// been retrieved. See UnusedServices.
+type serviceUsage struct {
+	mutex     sync.Mutex
+	retrieved map[interface{}]struct{}
+	ignored   map[interface{}]struct{}
+}
+
+// UnusedServices returns the serialized keys of the services registered to this
+// container (not including its parents) which have never been retrieved, whether
+// by Get, by injection, or as a parameter of a constructor passed to Provide.
+// A key named by the `default` tag of an injected field counts as retrieved.
+// Services registered by the bootstrapper, the container itself, and services
+// registered via SetFactory which have not been constructed (as they are only
+// constructed on demand) are excluded. An unused service generally indicates
+// dead wiring or a mistyped `service` tag. See WithStrictServices.
+func (c *ServiceContainer) UnusedServices() []string {
+	c.mutex.RLock()
+	order := append([]interface{}{}, c.order...)
+	lazies := map[interface{}]*lazyService{}
+	for key, lazy := range c.factories {
+		lazies[key] = lazy
+	}
+	c.mutex.RUnlock()
+
+	c.usage.mutex.Lock()
+	defer c.usage.mutex.Unlock()
+
+	unused := []string{}
+	for _, key := range order {
+		if key == "container" {
+			continue
+		}
+
+		if lazy, ok := lazies[key]; ok {
+			if _, constructed := lazy.constructedService(); !constructed {
+				continue
+			}
+		}
+
+		_, retrieved := c.usage.retrieved[key]
+		_, ignored := c.usage.ignored[key]
+
+		if !retrieved && !ignored {
+			unused = append(unused, serializeKey(key))
+		}
+	}
+
+	sort.Strings(unused)
+	return unused
+}
+
+// ignoreRegisteredServices excludes the services currently registered to the
+// container from the result of UnusedServices.
+func (c *ServiceContainer) ignoreRegisteredServices() {
+	c.mutex.RLock()
+	order := append([]interface{}{}, c.order...)
+	c.mutex.RUnlock()
+
+	c.usage.mutex.Lock()
+	defer c.usage.mutex.Unlock()
+
+	if c.usage.ignored == nil {
+		c.usage.ignored = map[interface{}]struct{}{}
+	}
+
+	for _, key := range order {
+		c.usage.ignored[key] = struct{}{}
+	}
+}
+
+// markUsed records the retrieval of the service registered to the given key by
+// the nearest container (this container or one of its ancestors) to which the key
+// is registered.
+func (c *ServiceContainer) markUsed(key interface{}) {
+	if c.origin != nil {
+		c.origin.markUsed(key)
+		return
+	}
+
+	c.mutex.RLock()
+	_, ok := c.services[key]
+	_, lazy := c.factories[key]
+	c.mutex.RUnlock()
+
+	if !ok && !lazy {
+		if c.parent != nil {
+			c.parent.markUsed(key)
+		}
+
+		return
+	}
+
+	c.usage.mutex.Lock()
+	defer c.usage.mutex.Unlock()
+
+	if c.usage.retrieved == nil {
+		c.usage.retrieved = map[interface{}]struct{}{}
+	}
+
+	c.usage.retrieved[key] = struct{}{}
+}
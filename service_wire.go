@@ -0,0 +1,123 @@
+package nacelle
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Wirer is implemented by types which populate their own dependencies from a
+// service container. When an object passed to Inject (or injected by the process
+// runner) implements Wirer, its Wire method is called in place of the tag-based
+// injection, so no reflection is performed on the object. Wire methods can be
+// written by hand or generated, and generally consist of calls to Resolve and
+// ResolveOptional.
+type Wirer interface {
+	Wire(c *ServiceContainer) error
+}
+
+// Resolve retrieves the service registered to the given key and asserts that it
+// has type T. It is an error for the service to be missing or to have another type.
+func Resolve[T any](c *ServiceContainer, key interface{}) (T, error) {
+	service, err := c.Get(key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return assertService[T](key, service)
+}
+
+// ResolveOptional retrieves the service registered to the given key and asserts
+// that it has type T. The zero value of T is returned if no service is registered
+// to the key. It is an error for the service to have another type, and an error
+// retrieving a registered service (e.g. a failing factory) is returned as-is.
+func ResolveOptional[T any](c *ServiceContainer, key interface{}) (T, error) {
+	service, err := c.Get(key)
+	if err != nil {
+		var zero T
+		if isUnregistered(err) {
+			return zero, nil
+		}
+
+		return zero, err
+	}
+
+	return assertService[T](key, service)
+}
+
+// MustResolve calls Resolve and panics on error. This replaces a call to MustGet
+// followed by a type assertion.
+func MustResolve[T any](c *ServiceContainer, key interface{}) T {
+	value, err := Resolve[T](c, key)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return value
+}
+
+func assertService[T any](key, service interface{}) (T, error) {
+	value, ok := service.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("service `%s` has unexpected type %T", serializeKey(key), service)
+	}
+
+	return value, nil
+}
+
+// withOverrides returns a container in which the given services take precedence
+// over the services registered to this container. The container is returned
+// unchanged if there are no overrides.
+func (c *ServiceContainer) withOverrides(overrides map[interface{}]interface{}) *ServiceContainer {
+	if len(overrides) == 0 {
+		return c
+	}
+
+	container := &ServiceContainer{
+		services: map[interface{}]interface{}{},
+		frozen:   true,
+	}
+
+	if c != nil {
+		container.services = c.registeredSnapshot()
+
+		c.mutex.RLock()
+		// Retrievals from the derived container are recorded to this container
+		container.origin = c
+		container.interceptor = c.interceptor
+		container.parent = c.parent
+		container.resolving = c.resolving
+		container.factories = map[interface{}]*lazyService{}
+		for key, lazy := range c.factories {
+			container.factories[key] = lazy
+		}
+
+		// Copied so that registrations to this container do not race with
+		// reads from the derived container
+		container.mappings = map[reflect.Type]FieldMapping{}
+		for t, mapping := range c.mappings {
+			container.mappings[t] = mapping
+		}
+
+		container.groups = map[string]map[string]interface{}{}
+		for group, services := range c.groups {
+			container.groups[group] = map[string]interface{}{}
+			for key, service := range services {
+				container.groups[group][key] = service
+			}
+		}
+
+		container.bindings = map[string][]interface{}{}
+		for name, services := range c.bindings {
+			container.bindings[name] = append([]interface{}{}, services...)
+		}
+		c.mutex.RUnlock()
+	}
+
+	for key, service := range overrides {
+		container.services[key] = service
+	}
+
+	return container
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package nacelle
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals which start a graceful shutdown.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// forcesExit returns true if receiving the given signal after a graceful
+// shutdown has started should cause the runner to stop waiting for processes
+// to exit.
+func forcesExit(sig os.Signal) bool {
+	return true
+}
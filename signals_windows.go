@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package nacelle
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals which start a graceful shutdown. The Go
+// runtime delivers CTRL_C_EVENT and CTRL_BREAK_EVENT as os.Interrupt and
+// delivers CTRL_CLOSE_EVENT, CTRL_LOGOFF_EVENT, and CTRL_SHUTDOWN_EVENT as
+// SIGTERM. While a SIGTERM is being handled, the runtime blocks the console
+// control handler, so the process is given the grace period allowed by the
+// system (after which it is terminated) to drain its processes.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// forcesExit returns true if receiving the given signal after a graceful
+// shutdown has started should cause the runner to stop waiting for processes
+// to exit. Windows may deliver several console control events for the same
+// shutdown (e.g. a close event followed by a logoff or shutdown event), so only
+// a repeated interrupt from the console forces an exit.
+func forcesExit(sig os.Signal) bool {
+	return sig == os.Interrupt
+}
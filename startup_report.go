@@ -0,0 +1,91 @@
+package nacelle
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// StartupReport collects every problem found while validating an application
+	// before its processes are started, so that a misconfigured application can
+	// be fixed in one pass rather than failing on each problem in turn. A report
+	// with at least one problem is returned as an error.
+	StartupReport struct {
+		Problems []StartupProblem
+	}
+
+	// StartupProblem is a single problem found while validating an application.
+	StartupProblem struct {
+		// Stage is the validation stage which found the problem (e.g. "config"
+		// or "inject").
+		Stage string
+
+		// Source is the name of the initializer or process with the problem. It
+		// is empty for problems which do not belong to a single source.
+		Source string
+
+		// Err is the underlying error.
+		Err error
+	}
+)
+
+const (
+	StageConfig   = "config"
+	StageInject   = "inject"
+	StageInit     = "init"
+	StageSelfTest = "self-test"
+)
+
+func (r *StartupReport) add(stage, source string, err error) {
+	r.Problems = append(r.Problems, StartupProblem{
+		Stage:  stage,
+		Source: source,
+		Err:    err,
+	})
+}
+
+// Err returns the report as an error, or nil if the report has no problems.
+func (r *StartupReport) Err() error {
+	if len(r.Problems) == 0 {
+		return nil
+	}
+
+	return r
+}
+
+func (r *StartupReport) Error() string {
+	messages := []string{}
+	for _, problem := range r.Problems {
+		messages = append(messages, problem.String())
+	}
+
+	return fmt.Sprintf(
+		"found %d startup problems (%s)",
+		len(r.Problems),
+		strings.Join(messages, "; "),
+	)
+}
+
+// Fields returns the problems of the report grouped by stage, suitable for
+// structured logging.
+func (r *StartupReport) Fields() Fields {
+	stages := map[string][]string{}
+	for _, problem := range r.Problems {
+		stages[problem.Stage] = append(stages[problem.Stage], problem.String())
+	}
+
+	fields := Fields{}
+	for stage, messages := range stages {
+		fields[stage] = messages
+	}
+
+	return fields
+}
+
+func (p StartupProblem) String() string {
+	if p.Source == "" {
+		return fmt.Sprintf("%s: %s", p.Stage, p.Err.Error())
+	}
+
+	return fmt.Sprintf("%s: %s: %s", p.Stage, p.Source, p.Err.Error())
+}
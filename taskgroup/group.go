@@ -0,0 +1,117 @@
+// Package taskgroup provides a helper for processes which spawn goroutines
+// from their Start method. Each goroutine is tied to the lifecycle of the
+// group: it is cancelled when the group is stopped, a panic is recovered and
+// reported as an error, and the group can wait for all goroutines to exit.
+package taskgroup
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+type (
+	// Group is a collection of goroutines tied to the lifecycle of a process.
+	Group struct {
+		ctx    context.Context
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+		errs   chan error
+		once   sync.Once
+		err    error
+	}
+
+	// TaskFunc is a function run by a group. The given context is cancelled
+	// when the group is stopped or when another task in the group fails.
+	TaskFunc func(ctx context.Context) error
+
+	// PanicError is the error reported by a group when a task panics.
+	PanicError struct {
+		Value interface{}
+		Stack []byte
+	}
+)
+
+// New creates a new group.
+func New() *Group {
+	return WithContext(context.Background())
+}
+
+// WithContext creates a new group whose context is derived from the given context.
+func WithContext(ctx context.Context) *Group {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+		errs:   make(chan error, 1),
+	}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked (%v)\n%s", e.Value, e.Stack)
+}
+
+// Context returns the context shared by the tasks of the group.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs the given task in a new goroutine. If the task returns a non-nil
+// error or panics, the group is stopped and the error is reported on the
+// channel returned by Err.
+func (g *Group) Go(f TaskFunc) {
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		if err := run(g.ctx, f); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+// Err returns a channel which receives the first error returned by (or the
+// first panic of) a task in the group. The Start method of a process can use
+// this channel to return the error of a failed task.
+func (g *Group) Err() <-chan error {
+	return g.errs
+}
+
+// Stop cancels the context of the group. This method does not block.
+func (g *Group) Stop() {
+	g.cancel()
+}
+
+// Wait blocks until all tasks of the group have returned, then returns the
+// first error returned by a task in the group.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+// StopAndWait stops the group and waits for all tasks to return.
+func (g *Group) StopAndWait() error {
+	g.Stop()
+	return g.Wait()
+}
+
+func (g *Group) fail(err error) {
+	g.once.Do(func() {
+		g.err = err
+		g.errs <- err
+		g.cancel()
+	})
+}
+
+func run(ctx context.Context, f TaskFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return f(ctx)
+}
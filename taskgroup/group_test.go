@@ -0,0 +1,61 @@
+package taskgroup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type GroupSuite struct{}
+
+func (s *GroupSuite) TestStop(t sweet.T) {
+	var (
+		group   = New()
+		started = make(chan struct{}, 2)
+	)
+
+	for i := 0; i < 2; i++ {
+		group.Go(func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil
+		})
+	}
+
+	Eventually(started).Should(Receive())
+	Eventually(started).Should(Receive())
+	Expect(group.StopAndWait()).To(BeNil())
+	Consistently(group.Err()).ShouldNot(Receive())
+}
+
+func (s *GroupSuite) TestError(t sweet.T) {
+	group := New()
+
+	group.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	group.Go(func(ctx context.Context) error {
+		return errors.New("utoh")
+	})
+
+	Eventually(group.Err()).Should(Receive(MatchError("utoh")))
+	Expect(group.Wait()).To(MatchError("utoh"))
+	Expect(group.Context().Err()).NotTo(BeNil())
+}
+
+func (s *GroupSuite) TestPanic(t sweet.T) {
+	group := New()
+
+	group.Go(func(ctx context.Context) error {
+		panic("oops")
+	})
+
+	err := group.Wait()
+	Expect(err).To(BeAssignableToTypeOf(&PanicError{}))
+	Expect(err.(*PanicError).Value).To(Equal("oops"))
+	Expect(err.Error()).To(ContainSubstring("task panicked (oops)"))
+}
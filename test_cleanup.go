@@ -0,0 +1,129 @@
+package nacelle
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// CleanupChecker is implemented by services and processes which can report
+	// whether they have released their resources (e.g. a worker whose tick loop
+	// is still running). The CheckCleanup method returns a non-nil error which
+	// describes the resources which are still held. See VerifyCleanup.
+	CleanupChecker interface {
+		CheckCleanup() error
+	}
+
+	// CleanupError lists each resource which was not released once the process
+	// runner had shut down.
+	CleanupError struct {
+		Problems []string
+	}
+
+	closedReporter interface {
+		Closed() bool
+	}
+)
+
+// VerifyCleanup checks that an application has released its resources after the
+// given runner has shut down, so that lifecycle hygiene can be enforced in tests.
+// It returns a CleanupError listing every problem found, or nil if none are found.
+// The following are checked:
+//
+//   - every process registered to the runner has exited;
+//   - every process and every service registered to the container which
+//     implements CleanupChecker reports no problem;
+//   - every service registered to the container which reports whether it has
+//     been closed with a `Closed() bool` method has been closed; and
+//   - every port claimed in the port registry (the service "ports") can be bound
+//     again, i.e. its listener has been closed.
+//
+// As io.Closer provides no way to query whether a value has been closed, a closer
+// which implements neither CleanupChecker nor Closed is not checked.
+func VerifyCleanup(runner *ProcessRunner, container *ServiceContainer) error {
+	problems := []string{}
+
+	for _, priority := range runner.getPriorities() {
+		for _, process := range runner.getProcesses(priority) {
+			if process.getExited() != nil && !hasExited(process) {
+				problems = append(problems, fmt.Sprintf("process %s has not exited", process.Name()))
+			}
+
+			if checker, ok := injectionTarget(process.Process).(CleanupChecker); ok {
+				if err := checker.CheckCleanup(); err != nil {
+					problems = append(problems, fmt.Sprintf("process %s has not cleaned up (%s)", process.Name(), err.Error()))
+				}
+			}
+		}
+	}
+
+	problems = append(problems, checkServiceCleanup(container)...)
+	problems = append(problems, checkPortCleanup(container)...)
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return &CleanupError{Problems: problems}
+}
+
+func (e *CleanupError) Error() string {
+	return fmt.Sprintf("resources were not released after shutdown (%s)", strings.Join(e.Problems, "; "))
+}
+
+func checkServiceCleanup(container *ServiceContainer) []string {
+	var (
+		problems = []string{}
+		checked  = map[interface{}]struct{}{}
+	)
+
+	for key, service := range container.snapshot() {
+		if service == nil {
+			continue
+		}
+
+		if reflect.TypeOf(service).Comparable() {
+			if _, ok := checked[service]; ok {
+				continue
+			}
+
+			checked[service] = struct{}{}
+		}
+
+		if checker, ok := service.(CleanupChecker); ok {
+			if err := checker.CheckCleanup(); err != nil {
+				problems = append(problems, fmt.Sprintf("service `%s` has not cleaned up (%s)", serializeKey(key), err.Error()))
+			}
+		}
+
+		if reporter, ok := service.(closedReporter); ok && !reporter.Closed() {
+			problems = append(problems, fmt.Sprintf("service `%s` has not been closed", serializeKey(key)))
+		}
+	}
+
+	return problems
+}
+
+func checkPortCleanup(container *ServiceContainer) []string {
+	ports, err := Resolve[*Ports](container, "ports")
+	if err != nil {
+		return nil
+	}
+
+	problems := []string{}
+	for key, port := range ports.Ports() {
+		listener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("port %d claimed by %s is still bound", port, key))
+			continue
+		}
+
+		listener.Close()
+	}
+
+	return problems
+}
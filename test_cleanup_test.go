@@ -0,0 +1,77 @@
+package nacelle
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/nacelle/log"
+	. "github.com/onsi/gomega"
+)
+
+type CleanupSuite struct{}
+
+func (s *CleanupSuite) TestVerifyCleanup(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		runner    = NewProcessRunner(container)
+		ports     = NewPorts()
+		conn      = &closableService{}
+		pool      = &checkedService{err: errors.New("2 connections open")}
+		errChan   = make(chan error)
+	)
+
+	container.MustSet("ports", ports)
+	container.MustSet("conn", conn)
+	container.MustSet("conn-alias", conn)
+	container.MustSet("pool", pool)
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("blocking"))
+
+	listener, err := ports.Listen("http", 0)
+	Expect(err).To(BeNil())
+	port, _ := ports.Port("http")
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+
+	err = VerifyCleanup(runner, container)
+	Expect(err).To(BeAssignableToTypeOf(&CleanupError{}))
+	Expect(err.(*CleanupError).Problems).To(Equal([]string{
+		fmt.Sprintf("port %d claimed by http is still bound", port),
+		"process blocking has not exited",
+		"service `conn` has not been closed",
+		"service `pool` has not cleaned up (2 connections open)",
+	}))
+
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	listener.Close()
+	Expect(conn.closed).To(BeTrue())
+	pool.err = nil
+	Expect(VerifyCleanup(runner, container)).To(BeNil())
+}
+
+//
+// Mocks
+
+type closableService struct {
+	closed bool
+}
+
+func (s *closableService) Close() error { s.closed = true; return nil }
+func (s *closableService) Closed() bool { return s.closed }
+
+type checkedService struct {
+	err error
+}
+
+func (s *checkedService) CheckCleanup() error { return s.err }
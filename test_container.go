@@ -0,0 +1,143 @@
+package nacelle
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	// TestContainer wraps a service container for use in tests. It records
+	// every service retrieval and injection performed through the test
+	// container and allows services to be substituted (by key or by type)
+	// with mock implementations while leaving the remaining wiring intact.
+	TestContainer struct {
+		*ServiceContainer
+		mutex          sync.Mutex
+		keySubstitutes map[interface{}]interface{}
+		typeRules      []*typeSubstitute
+		gets           []interface{}
+		injections     []interface{}
+	}
+
+	typeSubstitute struct {
+		targetType reflect.Type
+		service    interface{}
+	}
+
+	serviceInterceptor interface {
+		intercept(key, service interface{}, err error) (interface{}, error)
+		interceptMember(service interface{}) interface{}
+		recordInject(obj interface{})
+	}
+)
+
+// NewTestContainer wraps a child of the given service container (see Child),
+// so that the given container itself is not modified. Any retrieval from or
+// injection performed by the test container (including those made by a process
+// runner created with it) is observed, whether by key, by type (see Provide and
+// Call), or as a member of a group or multi-binding.
+func NewTestContainer(container *ServiceContainer) *TestContainer {
+	c := &TestContainer{
+		ServiceContainer: container.Child(),
+		keySubstitutes:   map[interface{}]interface{}{},
+	}
+
+	c.ServiceContainer.interceptor = c
+	return c
+}
+
+// SubstituteKey causes every retrieval of the given key to return the given
+// service instead of the registered one. The key does not need to be registered.
+func (c *TestContainer) SubstituteKey(key, service interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.keySubstitutes[key] = service
+}
+
+// SubstituteType causes every retrieval of a registered service whose type is
+// assignable to the given type to return the given service instead. The target
+// type should be given as a nil pointer to the type, e.g. (*Client)(nil).
+func (c *TestContainer) SubstituteType(target, service interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.typeRules = append(c.typeRules, &typeSubstitute{
+		targetType: reflect.TypeOf(target).Elem(),
+		service:    service,
+	})
+}
+
+// Gets returns the keys of each retrieval, in order.
+func (c *TestContainer) Gets() []interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]interface{}{}, c.gets...)
+}
+
+// Injections returns the targets of each injection, in order.
+func (c *TestContainer) Injections() []interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]interface{}{}, c.injections...)
+}
+
+// Reset clears all recorded retrievals and injections and all substitution rules.
+func (c *TestContainer) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.keySubstitutes = map[interface{}]interface{}{}
+	c.typeRules = nil
+	c.gets = nil
+	c.injections = nil
+}
+
+func (c *TestContainer) intercept(key, service interface{}, err error) (interface{}, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.gets = append(c.gets, key)
+
+	if substitute, ok := c.keySubstitutes[key]; ok {
+		return substitute, nil
+	}
+
+	if err != nil {
+		return service, err
+	}
+
+	return c.substituteType(service), nil
+}
+
+func (c *TestContainer) interceptMember(service interface{}) interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.substituteType(service)
+}
+
+// substituteType returns the substitute of the first type rule matching the
+// given service, or the service itself. The caller must hold the lock.
+func (c *TestContainer) substituteType(service interface{}) interface{} {
+	if service == nil {
+		return nil
+	}
+
+	for _, rule := range c.typeRules {
+		if reflect.TypeOf(service).AssignableTo(rule.targetType) {
+			return rule.service
+		}
+	}
+
+	return service
+}
+
+func (c *TestContainer) recordInject(obj interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.injections = append(c.injections, obj)
+}
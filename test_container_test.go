@@ -0,0 +1,100 @@
+package nacelle
+
+import (
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type TestContainerSuite struct{}
+
+func (s *TestContainerSuite) TestRecord(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		wrapper   = NewTestContainer(container)
+		obj       = &TestSimpleProcess{}
+	)
+
+	container.Set("value", &IntWrapper{42})
+	Expect(wrapper.Inject(obj)).To(BeNil())
+	_, err := wrapper.Get("missing")
+	Expect(err).NotTo(BeNil())
+
+	Expect(wrapper.Injections()).To(Equal([]interface{}{obj}))
+	Expect(wrapper.Gets()).To(Equal([]interface{}{"value", "missing"}))
+
+	// The wrapped container is not intercepted
+	Expect(container.Inject(obj)).To(BeNil())
+	Expect(wrapper.Injections()).To(HaveLen(1))
+	Expect(wrapper.Gets()).To(HaveLen(2))
+}
+
+func (s *TestContainerSuite) TestSubstituteKey(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		wrapper   = NewTestContainer(container)
+		obj       = &TestSimpleProcess{}
+	)
+
+	wrapper.SubstituteKey("value", &IntWrapper{24})
+	Expect(wrapper.Inject(obj)).To(BeNil())
+	Expect(obj.Value.val).To(Equal(24))
+	Expect(container.Inject(obj)).NotTo(BeNil())
+}
+
+func (s *TestContainerSuite) TestSubstituteType(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		wrapper   = NewTestContainer(container)
+		obj       = &TestSimpleProcess{}
+	)
+
+	container.Set("value", &IntWrapper{42})
+	container.Set("other", &FloatWrapper{3.14})
+	wrapper.SubstituteType((**IntWrapper)(nil), &IntWrapper{24})
+
+	Expect(wrapper.Inject(obj)).To(BeNil())
+	Expect(obj.Value.val).To(Equal(24))
+	Expect(wrapper.MustGet("other")).To(Equal(&FloatWrapper{3.14}))
+
+	wrapper.Reset()
+	Expect(wrapper.Inject(obj)).To(BeNil())
+	Expect(obj.Value.val).To(Equal(42))
+	Expect(wrapper.Gets()).To(Equal([]interface{}{"value"}))
+}
+
+func (s *TestContainerSuite) TestSubstituteByTypeResolution(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		wrapper   = NewTestContainer(container)
+	)
+
+	container.Set("value", &IntWrapper{42})
+	wrapper.SubstituteKey("value", &IntWrapper{24})
+
+	values, err := wrapper.Call(func(w *IntWrapper) int { return w.val })
+	Expect(err).To(BeNil())
+	Expect(values).To(Equal([]interface{}{24}))
+	Expect(wrapper.Gets()).To(Equal([]interface{}{"value"}))
+
+	Expect(wrapper.Provide(func(w *IntWrapper) *FloatWrapper { return &FloatWrapper{float64(w.val)} }, "float")).To(BeNil())
+	Expect(wrapper.MustGet("float")).To(Equal(&FloatWrapper{24}))
+}
+
+func (s *TestContainerSuite) TestSubstituteGroupMembers(t sweet.T) {
+	var (
+		container = NewServiceContainer()
+		wrapper   = NewTestContainer(container)
+		obj       = &struct {
+			Group   map[string]*IntWrapper `group:"handlers"`
+			Binding []*IntWrapper          `service:"plugins" type:"group"`
+		}{}
+	)
+
+	container.MustSetInGroup("handlers", "a", &IntWrapper{1})
+	container.MustAdd("plugins", &IntWrapper{2})
+	wrapper.SubstituteType((**IntWrapper)(nil), &IntWrapper{24})
+
+	Expect(wrapper.Inject(obj)).To(BeNil())
+	Expect(obj.Group).To(Equal(map[string]*IntWrapper{"a": {24}}))
+	Expect(obj.Binding).To(Equal([]*IntWrapper{{24}}))
+}
@@ -0,0 +1,226 @@
+package nacelle
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Timeline builds a timeline of the lifecycle of a runner from its lifecycle
+	// events: a span for the initialization, the run, and the shutdown of each
+	// initializer and process, as well as a marker for each restart and reload.
+	// The timeline is written in the Chrome trace event format, which can be
+	// opened in Perfetto (ui.perfetto.dev) or chrome://tracing in order to
+	// visually inspect the behavior of an application during incident review.
+	// A timeline is populated by subscribing its Observe method to a runner (see
+	// WithTimelineFile).
+	Timeline struct {
+		start  time.Time
+		events []traceEvent
+		open   map[timelineSpan]openSpan
+		tracks map[string]int
+		mutex  sync.Mutex
+	}
+
+	// timelineSpan identifies a span of a single initializer or process which
+	// has started but not yet finished.
+	timelineSpan struct {
+		name  string
+		phase string
+	}
+
+	openSpan struct {
+		start time.Time
+		args  map[string]interface{}
+	}
+
+	// traceEvent is an event of the Chrome trace event format. Timestamps and
+	// durations are in microseconds.
+	traceEvent struct {
+		Name      string                 `json:"name"`
+		Category  string                 `json:"cat,omitempty"`
+		Phase     string                 `json:"ph"`
+		Timestamp int64                  `json:"ts"`
+		Duration  int64                  `json:"dur,omitempty"`
+		PID       int                    `json:"pid"`
+		TID       int                    `json:"tid"`
+		Scope     string                 `json:"s,omitempty"`
+		Args      map[string]interface{} `json:"args,omitempty"`
+	}
+)
+
+const (
+	timelinePhaseInit = "init"
+	timelinePhaseRun  = "run"
+	timelinePhaseStop = "stop"
+)
+
+// NewTimeline creates an empty timeline whose timestamps are relative to the
+// current time.
+func NewTimeline() *Timeline {
+	return &Timeline{
+		start:  time.Now(),
+		open:   map[timelineSpan]openSpan{},
+		tracks: map[string]int{},
+	}
+}
+
+// Observe adds a lifecycle event to the timeline. This method conforms to the
+// LifecycleSubscriber type.
+func (t *Timeline) Observe(event LifecycleEvent) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	switch event.Type {
+	case EventInitStarted:
+		t.begin(event, timelinePhaseInit)
+
+	case EventInitCompleted:
+		t.end(event, timelinePhaseInit)
+
+	case EventStartCalled:
+		t.begin(event, timelinePhaseRun)
+
+	case EventStopping:
+		t.begin(event, timelinePhaseStop)
+
+	case EventStopped:
+		t.end(event, timelinePhaseRun)
+		t.end(event, timelinePhaseStop)
+
+	case EventErrored:
+		// An initialization failure ends the init span. Errors returned from
+		// Start are attached to the run span by the following EventStopped.
+		t.end(event, timelinePhaseInit)
+
+	case EventRestarting, EventReloaded:
+		t.instant(event)
+	}
+}
+
+// Write writes the timeline to the given writer as a JSON trace object. Spans
+// which have not finished (e.g. a process which is still running) end at the
+// time of the write.
+func (t *Timeline) Write(w io.Writer) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var (
+		now    = time.Now()
+		events = append([]traceEvent{}, t.events...)
+	)
+
+	for span, open := range t.open {
+		events = append(events, t.makeSpan(span, open, now, map[string]interface{}{"unfinished": true}))
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+
+	names := make([]string, len(t.tracks))
+	for name, tid := range t.tracks {
+		names[tid-1] = name
+	}
+
+	for i, name := range names {
+		events = append(events, traceEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			PID:   1,
+			TID:   i + 1,
+			Args:  map[string]interface{}{"name": name},
+		})
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
+
+func (t *Timeline) begin(event LifecycleEvent, phase string) {
+	args := map[string]interface{}{}
+	for key, value := range event.Tags {
+		args[key] = value
+	}
+
+	t.open[timelineSpan{event.Name, phase}] = openSpan{start: event.Time, args: args}
+}
+
+func (t *Timeline) end(event LifecycleEvent, phase string) {
+	span := timelineSpan{event.Name, phase}
+
+	open, ok := t.open[span]
+	if !ok {
+		return
+	}
+
+	delete(t.open, span)
+
+	var args map[string]interface{}
+	if event.Err != nil {
+		args = map[string]interface{}{"error": event.Err.Error()}
+	}
+
+	t.events = append(t.events, t.makeSpan(span, open, event.Time, args))
+}
+
+func (t *Timeline) instant(event LifecycleEvent) {
+	args := map[string]interface{}{}
+	if event.Err != nil {
+		args["error"] = event.Err.Error()
+	}
+
+	t.events = append(t.events, traceEvent{
+		Name:      event.Type.String(),
+		Category:  "lifecycle",
+		Phase:     "i",
+		Timestamp: t.timestamp(event.Time),
+		PID:       1,
+		TID:       t.track(event.Name),
+		Scope:     "t",
+		Args:      args,
+	})
+}
+
+func (t *Timeline) makeSpan(span timelineSpan, open openSpan, end time.Time, extra map[string]interface{}) traceEvent {
+	args := map[string]interface{}{}
+	for key, value := range open.args {
+		args[key] = value
+	}
+
+	for key, value := range extra {
+		args[key] = value
+	}
+
+	return traceEvent{
+		Name:      span.phase,
+		Category:  "lifecycle",
+		Phase:     "X",
+		Timestamp: t.timestamp(open.start),
+		Duration:  end.Sub(open.start).Microseconds(),
+		PID:       1,
+		TID:       t.track(span.name),
+		Args:      args,
+	}
+}
+
+// track returns the thread identifier under which the spans of the initializer
+// or process with the given name are displayed.
+func (t *Timeline) track(name string) int {
+	if tid, ok := t.tracks[name]; ok {
+		return tid
+	}
+
+	tid := len(t.tracks) + 1
+	t.tracks[name] = tid
+	return tid
+}
+
+func (t *Timeline) timestamp(at time.Time) int64 {
+	return at.Sub(t.start).Microseconds()
+}
@@ -0,0 +1,123 @@
+package nacelle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/nacelle/log"
+	. "github.com/onsi/gomega"
+)
+
+type TimelineSuite struct{}
+
+func (s *TimelineSuite) TestSpans(t sweet.T) {
+	timeline := NewTimeline()
+	at := func(ms int) time.Time { return timeline.start.Add(time.Duration(ms) * time.Millisecond) }
+
+	for _, event := range []LifecycleEvent{
+		{Type: EventProcessRegistered, Name: "api", Time: at(0)},
+		{Type: EventInitStarted, Name: "api", Time: at(1), Tags: map[string]string{"team": "web"}},
+		{Type: EventInitCompleted, Name: "api", Time: at(3)},
+		{Type: EventStartCalled, Name: "api", Time: at(4)},
+		{Type: EventErrored, Name: "api", Time: at(10), Err: errors.New("utoh")},
+		{Type: EventStopped, Name: "api", Time: at(10), Err: errors.New("utoh")},
+		{Type: EventRestarting, Name: "api", Time: at(11), Err: errors.New("utoh")},
+		{Type: EventReloaded, Name: "kill switches", Time: at(12)},
+		{Type: EventStartCalled, Name: "api", Time: at(13)},
+		{Type: EventStopping, Name: "api", Time: at(20)},
+		{Type: EventStopped, Name: "api", Time: at(22)},
+	} {
+		timeline.Observe(event)
+	}
+
+	events := decodeTimeline(timeline)
+	Expect(events).To(Equal([]decodedTraceEvent{
+		{Name: "init", Phase: "X", Timestamp: 1000, Duration: 2000, TID: 1, Args: map[string]interface{}{"team": "web"}},
+		{Name: "run", Phase: "X", Timestamp: 4000, Duration: 6000, TID: 1, Args: map[string]interface{}{"error": "utoh"}},
+		{Name: "restarting", Phase: "i", Timestamp: 11000, TID: 1, Args: map[string]interface{}{"error": "utoh"}},
+		{Name: "reloaded", Phase: "i", Timestamp: 12000, TID: 2},
+		{Name: "run", Phase: "X", Timestamp: 13000, Duration: 9000, TID: 1},
+		{Name: "stop", Phase: "X", Timestamp: 20000, Duration: 2000, TID: 1},
+		{Name: "thread_name", Phase: "M", TID: 1, Args: map[string]interface{}{"name": "api"}},
+		{Name: "thread_name", Phase: "M", TID: 2, Args: map[string]interface{}{"name": "kill switches"}},
+	}))
+}
+
+func (s *TimelineSuite) TestUnfinishedSpans(t sweet.T) {
+	timeline := NewTimeline()
+	timeline.Observe(LifecycleEvent{Type: EventStartCalled, Name: "api", Time: timeline.start})
+
+	events := decodeTimeline(timeline)
+	Expect(events).To(HaveLen(2))
+	Expect(events[0].Name).To(Equal("run"))
+	Expect(events[0].Args).To(Equal(map[string]interface{}{"unfinished": true}))
+}
+
+func (s *TimelineSuite) TestWithTimelineFile(t sweet.T) {
+	dir, err := ioutil.TempDir("", "nacelle-timeline")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	var (
+		path    = filepath.Join(dir, "timeline.json")
+		runner  = NewProcessRunner(NewServiceContainer(), WithTimelineFile(path))
+		errChan = make(chan error)
+	)
+
+	runner.RegisterProcess(makeBlockingProcess(), WithProcessName("proc"))
+
+	go func() {
+		defer close(errChan)
+
+		for err := range runner.Run(nil, log.NewNilLogger()) {
+			errChan <- err
+		}
+	}()
+
+	Eventually(runner.isRunning).Should(BeTrue())
+	runner.RecordReload("settings")
+	Expect(runner.Shutdown(time.Second)).To(BeNil())
+	Eventually(errChan).Should(BeClosed())
+
+	content, err := ioutil.ReadFile(path)
+	Expect(err).To(BeNil())
+
+	trace := struct {
+		TraceEvents []decodedTraceEvent `json:"traceEvents"`
+	}{}
+	Expect(json.Unmarshal(content, &trace)).To(BeNil())
+
+	names := []string{}
+	for _, event := range trace.TraceEvents {
+		names = append(names, event.Name)
+	}
+
+	Expect(names).To(Equal([]string{"init", "run", "reloaded", "stop", "thread_name", "thread_name"}))
+}
+
+type decodedTraceEvent struct {
+	Name      string                 `json:"name"`
+	Phase     string                 `json:"ph"`
+	Timestamp int64                  `json:"ts"`
+	Duration  int64                  `json:"dur"`
+	TID       int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+func decodeTimeline(timeline *Timeline) []decodedTraceEvent {
+	buffer := &bytes.Buffer{}
+	Expect(timeline.Write(buffer)).To(BeNil())
+
+	trace := struct {
+		TraceEvents []decodedTraceEvent `json:"traceEvents"`
+	}{}
+
+	Expect(json.Unmarshal(buffer.Bytes(), &trace)).To(BeNil())
+	return trace.TraceEvents
+}
@@ -0,0 +1,119 @@
+package nacelle
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+type (
+	// Timeouts is a timeout policy shared by the servers and clients of an
+	// application. A zero value means no limit, except when the timeouts are
+	// used as overrides (see Override), where a zero value inherits the value
+	// being overridden. The bootstrapper registers the timeouts read from the
+	// TimeoutsConfig as the service "timeouts", which the base processes use
+	// as the defaults of their own timeout config values.
+	Timeouts struct {
+		Dial     time.Duration
+		Read     time.Duration
+		Write    time.Duration
+		Idle     time.Duration
+		Shutdown time.Duration
+	}
+
+	// TimeoutsConfig is the config of the timeouts registered by the bootstrapper.
+	// Each value is given in seconds.
+	TimeoutsConfig struct {
+		RawDialTimeout     int `env:"timeout_dial" default:"5"`
+		RawReadTimeout     int `env:"timeout_read"`
+		RawWriteTimeout    int `env:"timeout_write"`
+		RawIdleTimeout     int `env:"timeout_idle"`
+		RawShutdownTimeout int `env:"timeout_shutdown" default:"5"`
+
+		Timeouts Timeouts
+	}
+
+	timeoutsConfigToken string
+)
+
+var (
+	TimeoutsConfigToken = timeoutsConfigToken("nacelle-timeouts")
+	ErrBadTimeoutConfig = errors.New("timeouts must not be negative")
+
+	// DefaultTimeouts are the timeouts used by a base process when no timeouts
+	// have been registered to the service container. They match the defaults
+	// of TimeoutsConfig.
+	DefaultTimeouts = Timeouts{
+		Dial:     time.Second * 5,
+		Shutdown: time.Second * 5,
+	}
+)
+
+func (c *TimeoutsConfig) PostLoad() error {
+	timeouts, err := MakeTimeouts(c.RawDialTimeout, c.RawReadTimeout, c.RawWriteTimeout, c.RawIdleTimeout, c.RawShutdownTimeout)
+	if err != nil {
+		return err
+	}
+
+	c.Timeouts = timeouts
+	return nil
+}
+
+// MakeTimeouts creates timeouts from values given in seconds. This is used by the
+// config structs of components which override the shared timeouts. It is an error
+// for any value to be negative.
+func MakeTimeouts(dial, read, write, idle, shutdown int) (Timeouts, error) {
+	for _, value := range []int{dial, read, write, idle, shutdown} {
+		if value < 0 {
+			return Timeouts{}, ErrBadTimeoutConfig
+		}
+	}
+
+	return Timeouts{
+		Dial:     time.Duration(dial) * time.Second,
+		Read:     time.Duration(read) * time.Second,
+		Write:    time.Duration(write) * time.Second,
+		Idle:     time.Duration(idle) * time.Second,
+		Shutdown: time.Duration(shutdown) * time.Second,
+	}, nil
+}
+
+// Override returns a copy of these timeouts in which each non-zero value of the
+// given timeouts takes precedence.
+func (t Timeouts) Override(overrides Timeouts) Timeouts {
+	override := func(value, override time.Duration) time.Duration {
+		if override != 0 {
+			return override
+		}
+
+		return value
+	}
+
+	return Timeouts{
+		Dial:     override(t.Dial, overrides.Dial),
+		Read:     override(t.Read, overrides.Read),
+		Write:    override(t.Write, overrides.Write),
+		Idle:     override(t.Idle, overrides.Idle),
+		Shutdown: override(t.Shutdown, overrides.Shutdown),
+	}
+}
+
+// Dialer creates a dialer which applies the dial timeout, for use by clients
+// which should follow the shared timeout policy.
+func (t Timeouts) Dialer() *net.Dialer {
+	return &net.Dialer{Timeout: t.Dial}
+}
+
+// ResolveTimeouts returns the timeouts registered to the given container under
+// the key "timeouts" overridden by the given component-specific timeouts. The
+// DefaultTimeouts are used if no timeouts are registered.
+func ResolveTimeouts(container *ServiceContainer, overrides Timeouts) Timeouts {
+	timeouts := DefaultTimeouts
+	if container != nil {
+		if registered, err := Resolve[*Timeouts](container, "timeouts"); err == nil {
+			timeouts = *registered
+		}
+	}
+
+	return timeouts.Override(overrides)
+}
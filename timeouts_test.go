@@ -0,0 +1,53 @@
+package nacelle
+
+import (
+	"time"
+
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type TimeoutsSuite struct{}
+
+func (s *TimeoutsSuite) TestOverride(t sweet.T) {
+	timeouts := Timeouts{
+		Dial:     time.Second,
+		Read:     time.Second * 2,
+		Shutdown: time.Second * 3,
+	}
+
+	Expect(timeouts.Override(Timeouts{Read: time.Second * 4, Idle: time.Second * 5})).To(Equal(Timeouts{
+		Dial:     time.Second,
+		Read:     time.Second * 4,
+		Idle:     time.Second * 5,
+		Shutdown: time.Second * 3,
+	}))
+}
+
+func (s *TimeoutsSuite) TestConfig(t sweet.T) {
+	c := &TimeoutsConfig{RawDialTimeout: 5, RawWriteTimeout: 10, RawShutdownTimeout: 15}
+	Expect(c.PostLoad()).To(BeNil())
+	Expect(c.Timeouts).To(Equal(Timeouts{
+		Dial:     time.Second * 5,
+		Write:    time.Second * 10,
+		Shutdown: time.Second * 15,
+	}))
+
+	c = &TimeoutsConfig{RawReadTimeout: -1}
+	Expect(c.PostLoad()).To(Equal(ErrBadTimeoutConfig))
+}
+
+func (s *TimeoutsSuite) TestResolveTimeouts(t sweet.T) {
+	Expect(ResolveTimeouts(nil, Timeouts{Idle: time.Second})).To(Equal(Timeouts{
+		Dial:     time.Second * 5,
+		Idle:     time.Second,
+		Shutdown: time.Second * 5,
+	}))
+
+	container := NewServiceContainer()
+	container.Set("timeouts", &Timeouts{Read: time.Second * 2})
+	Expect(ResolveTimeouts(container, Timeouts{Shutdown: time.Second})).To(Equal(Timeouts{
+		Read:     time.Second * 2,
+		Shutdown: time.Second,
+	}))
+}